@@ -0,0 +1,16 @@
+package constant
+
+// APIKeyPrefix starts every API key, which tells them apart from access
+// tokens in the Authorization header.
+const APIKeyPrefix = "g12_"
+
+// Scopes an API key can be granted. Login sessions are not limited by scope.
+const (
+	ScopeEventsRead  = "events:read"
+	ScopeEventsWrite = "events:write"
+	ScopeFeedsRead   = "feeds:read"
+	ScopeFeedsWrite  = "feeds:write"
+
+	ScopeWebhooksRead  = "webhooks:read"
+	ScopeWebhooksWrite = "webhooks:write"
+)
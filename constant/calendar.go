@@ -0,0 +1,37 @@
+package constant
+
+const (
+	DefaultCalendarColor    = "#4285f4"
+	DefaultCalendarTimezone = "UTC"
+)
+
+// States of the default workflow, which calendars without one of their own
+// and events outside calendars follow.
+const (
+	WorkflowTodo       = "todo"
+	WorkflowInProgress = "in progress"
+	WorkflowReview     = "review"
+	WorkflowDone       = "done"
+)
+
+// Modes of deleting a calendar that still has events
+const (
+	CalendarDeleteCascade = "cascade"
+	CalendarDeleteMove    = "move"
+)
+
+// Roles a calendar is shared with, from least to most access. Viewers may
+// read its events, editors may also write them and owners may also manage
+// the calendar and who it is shared with.
+const (
+	CalendarRoleViewer = "viewer"
+	CalendarRoleEditor = "editor"
+	CalendarRoleOwner  = "owner"
+)
+
+// States of a calendar share. Only accepted shares grant access.
+const (
+	CalendarSharePending  = "pending"
+	CalendarShareAccepted = "accepted"
+	CalendarShareDeclined = "declined"
+)
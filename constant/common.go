@@ -4,3 +4,15 @@ const (
 	Success = "SUCCESS"
 	Failed  = "FAILED"
 )
+
+// Gin context keys the auth middleware stores the authenticated caller under
+const (
+	ContextUserID = "userID"
+	ContextActor  = "actor"
+)
+
+// Content types accepted by PATCH endpoints
+const (
+	MergePatchContentType = "application/merge-patch+json" // RFC 7396
+	JSONPatchContentType  = "application/json-patch+json"  // RFC 6902
+)
@@ -0,0 +1,25 @@
+package constant
+
+// Item types. Events take up a window of time; tasks are things to do and
+// may have a window, a due date, both or neither.
+const (
+	EventTypeEvent = "event"
+	EventTypeTask  = "task"
+)
+
+// MaxEventItems bounds the checklist of an event.
+const MaxEventItems = 100
+
+// Priorities from lowest to highest. Items store the index of theirs, so
+// they sort by it.
+var Priorities = []string{"none", "low", "medium", "high", "urgent"}
+
+// iCalendar import and export
+const (
+	ICalendarContentType = "text/calendar"
+	MaxImportSize        = 10 << 20 // bytes
+
+	ImportCreated = "CREATED"
+	ImportSkipped = "SKIPPED"
+	ImportFailed  = "FAILED"
+)
@@ -0,0 +1,22 @@
+package constant
+
+import "time"
+
+// Reminders are at most MaxReminderMinutes before the event, four weeks.
+// The scheduler claims up to ReminderBatchSize due ones at a time, for
+// ReminderLease, and gives up on one after MaxReminderAttempts failed
+// deliveries. A claimed reminder not saved by the end of its lease is sent
+// again.
+const (
+	MaxReminderMinutes  = 4 * 7 * 24 * 60
+	ReminderBatchSize   = 100
+	MaxReminderAttempts = 5
+	ReminderLease       = 10 * time.Minute
+)
+
+// Notifiers reminders may be delivered through
+const (
+	NotifierLog     = "log"
+	NotifierSMTP    = "smtp"
+	NotifierWebhook = "webhook"
+)
@@ -0,0 +1,28 @@
+package constant
+
+import "time"
+
+// Kinds of change the real-time stream sends, the SSE event name of each.
+// Completing an event is an update.
+const (
+	StreamEventCreated = "created"
+	StreamEventUpdated = "updated"
+	StreamEventDeleted = "deleted"
+)
+
+const (
+	// EventChangesChannel is the Postgres channel writes to events notify,
+	// with the ID of the change, so every replica hears of them.
+	EventChangesChannel = "event_changes"
+	// OutboxLockKey is the advisory lock writes to the outbox take turns on.
+	OutboxLockKey = 0x6731325f6f7574 // "g12_out"
+	// StreamBatchSize is how many changes a stream reads at a time.
+	StreamBatchSize = 100
+	// StreamHeartbeat is how often an idle stream is kept alive, and checks
+	// for changes whose notification it missed.
+	StreamHeartbeat = 15 * time.Second
+)
+
+// HeaderLastEventID resumes a stream after the change it names; browsers
+// send it when an EventSource reconnects.
+const HeaderLastEventID = "Last-Event-ID"
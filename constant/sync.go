@@ -0,0 +1,24 @@
+package constant
+
+// Outcomes of a mutation sent to the sync endpoint.
+const (
+	SyncApplied  = "APPLIED"
+	SyncConflict = "CONFLICT"
+	SyncFailed   = "FAILED"
+)
+
+// Writes a client may sync.
+const (
+	SyncOpCreate = "create"
+	SyncOpUpdate = "update"
+	SyncOpDelete = "delete"
+)
+
+const (
+	// SyncTokenVersion is bumped whenever the contents of sync tokens
+	// change; older tokens are refused, so their clients sync from scratch.
+	SyncTokenVersion = 1
+	// SyncBatchSize is how many changes, or events of a snapshot, a sync
+	// returns unless asked for another number.
+	SyncBatchSize = 100
+)
@@ -0,0 +1,8 @@
+package constant
+
+// How the tags of an event list filter combine: "any" matches events with
+// at least one of them, "all" events with every one.
+const (
+	TagModeAny = "any"
+	TagModeAll = "all"
+)
@@ -0,0 +1,46 @@
+package constant
+
+import "time"
+
+// Changes to events webhooks can subscribe to. A change that completes an
+// event is published as WebhookEventCompleted rather than as an update, and
+// restoring one from the trash as WebhookEventCreated.
+const (
+	WebhookEventCreated   = "event.created"
+	WebhookEventUpdated   = "event.updated"
+	WebhookEventCompleted = "event.completed"
+	WebhookEventDeleted   = "event.deleted"
+)
+
+var WebhookEvents = []string{WebhookEventCreated, WebhookEventUpdated, WebhookEventCompleted, WebhookEventDeleted}
+
+// States of a webhook delivery. Deliveries that failed MaxWebhookAttempts
+// times are dead-lettered: kept for the delivery log until redelivered.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// The dispatcher fans out up to WebhookBatchSize outbox events and sends up
+// to as many deliveries at a time. A failed delivery is retried after
+// WebhookRetryDelay, doubling after each attempt up to MaxWebhookRetryDelay.
+// Deliveries being sent are leased for WebhookLease, longer than sending a
+// whole batch can take, and are sent again if not saved by then.
+const (
+	WebhookBatchSize     = 100
+	MaxWebhookAttempts   = 8
+	WebhookRetryDelay    = 30 * time.Second
+	MaxWebhookRetryDelay = 6 * time.Hour
+	WebhookTimeout       = 10 * time.Second
+	WebhookLease         = 30 * time.Minute
+)
+
+// Headers webhook deliveries are sent with. The signature is
+// "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">", keyed with
+// the secret of the webhook.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
@@ -0,0 +1,14 @@
+package constant
+
+// HeaderWorkspaceID selects the workspace a logged in user acts in. Without
+// it they act in their personal space, PersonalWorkspaceID.
+const (
+	HeaderWorkspaceID   = "X-Workspace-ID"
+	PersonalWorkspaceID = 0
+)
+
+// Roles of a workspace member. Admins manage the workspace and its members.
+const (
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxListenRetryDelay = 30 * time.Second
+
+// EventChanges hears of the writes to events, made by any replica, once it
+// is started.
+var EventChanges = NewListener(constant.EventChangesChannel)
+
+// Listener fans the notifications Postgres sends on a channel out to the
+// subscribers in this process. Notifications are only a wake-up call:
+// subscribers read what changed from the database, so one notification
+// stands for any number.
+type Listener struct {
+	channel     string
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func NewListener(channel string) *Listener {
+	return &Listener{channel: channel, subscribers: make(map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel that receives after notifications, and a func
+// to stop receiving. Notifications that come while one is waiting to be
+// received are folded into it.
+func (l *Listener) Subscribe() (<-chan struct{}, func()) {
+	wake := make(chan struct{}, 1)
+	l.mu.Lock()
+	l.subscribers[wake] = struct{}{}
+	l.mu.Unlock()
+
+	return wake, func() {
+		l.mu.Lock()
+		delete(l.subscribers, wake)
+		l.mu.Unlock()
+	}
+}
+
+// Notify wakes every subscriber.
+func (l *Listener) Notify() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for wake := range l.subscribers {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start listens on its own connection to the database until ctx is done,
+// reconnecting when the connection is lost.
+func (l *Listener) Start(ctx context.Context) error {
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "[Listener.Start]: Error listening on %s", l.channel)
+	}
+	go l.listen(ctx, conn)
+
+	log.Infof("[Listener.Start]: Listening on %s", l.channel)
+	return nil
+}
+
+func (l *Listener) listen(ctx context.Context, conn *pgx.Conn) {
+	delay := time.Second
+	for {
+		if conn == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			var err error
+			if conn, err = l.connect(ctx); err != nil {
+				log.Warn(errors.Wrapf(err, "[Listener.listen]: Error reconnecting to %s", l.channel))
+				delay = min(2*delay, maxListenRetryDelay)
+				continue
+			}
+			delay = time.Second
+			// Whatever was notified while it was gone
+			l.Notify()
+		}
+
+		_, err := conn.WaitForNotification(ctx)
+		if ctx.Err() != nil {
+			conn.Close(context.Background())
+			return
+		}
+		if err != nil {
+			log.Warn(errors.Wrapf(err, "[Listener.listen]: Error waiting on %s", l.channel))
+			conn.Close(context.Background())
+			conn = nil
+			continue
+		}
+		l.Notify()
+	}
+}
+
+func (l *Listener) connect(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, connectionString())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,39 @@
+package database
+
+import "testing"
+
+func TestListener_Notify(t *testing.T) {
+	listener := NewListener("changes")
+	first, unsubscribeFirst := listener.Subscribe()
+	second, unsubscribeSecond := listener.Subscribe()
+	defer unsubscribeSecond()
+
+	// Notifications not received yet fold into one
+	listener.Notify()
+	listener.Notify()
+	for i, wake := range []<-chan struct{}{first, second} {
+		select {
+		case <-wake:
+		default:
+			t.Fatalf("Expected subscriber %d woken", i+1)
+		}
+		select {
+		case <-wake:
+			t.Errorf("Expected subscriber %d woken once", i+1)
+		default:
+		}
+	}
+
+	unsubscribeFirst()
+	listener.Notify()
+	select {
+	case <-first:
+		t.Error("Expected no wake-up after unsubscribing")
+	default:
+	}
+	select {
+	case <-second:
+	default:
+		t.Error("Expected the other subscriber still woken")
+	}
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateOutboxEvents adds changes to the outbox and notifies
+// constant.EventChangesChannel once tx commits. Writers take turns from
+// here until they commit, so changes are numbered in the order they commit
+// and a stream reading them in order never passes one that commits late;
+// it is the last write of a transaction so the turn is short.
+func CreateOutboxEvents(tx *gorm.DB, changes ...*models.OutboxEvents) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", constant.OutboxLockKey).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&changes).Error; err != nil {
+		return err
+	}
+	last := changes[len(changes)-1].ID
+	return tx.Exec("SELECT pg_notify(?, ?)", constant.EventChangesChannel, strconv.FormatUint(last, 10)).Error
+}
+
+// NewOutboxEvent is the change change to event, at its current version.
+func NewOutboxEvent(event *models.Events, change string) *models.OutboxEvents {
+	now := time.Now()
+	return &models.OutboxEvents{
+		Type:               change,
+		EventID:            event.ID,
+		OwnerID:            event.OwnerID,
+		CalendarID:         event.CalendarID,
+		PreviousCalendarID: event.PreviousCalendarID,
+		Version:            event.Version,
+		CreatedAt:          &now,
+	}
+}
+
+// SaveEventChanges adds the changes events publish to the outbox, at their
+// new versions, and clears them, along with the updates of the events
+// waiting on those whose completion changed.
+func SaveEventChanges(tx *gorm.DB, events ...*models.Events) error {
+	var changes []*models.OutboxEvents
+	for _, event := range events {
+		for _, change := range event.Changes {
+			changes = append(changes, NewOutboxEvent(event, change))
+		}
+	}
+	dependents, err := touchDependents(tx, events)
+	if err != nil {
+		return err
+	}
+	changes = append(changes, dependents...)
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := CreateOutboxEvents(tx, changes...); err != nil {
+		return err
+	}
+	for _, event := range events {
+		event.Changes = nil
+		event.CompletionChanged = false
+		event.PreviousCalendarID = nil
+	}
+	return nil
+}
+
+// touchDependents bumps the version of every event waiting on one of the
+// events whose completion changed, trashed ones included, since whether
+// they are blocked changes with it. It returns the updates to publish for
+// the ones not in the trash; the others were already published as deleted.
+func touchDependents(tx *gorm.DB, events []*models.Events) ([]*models.OutboxEvents, error) {
+	var ids []uint64
+	for _, event := range events {
+		if event.CompletionChanged {
+			ids = append(ids, event.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	waiting := tx.Session(&gorm.Session{NewDB: true}).Model(&models.EventDependencies{}).
+		Select("event_id").
+		Where("blocker_id IN ?", ids)
+	var dependents []*models.Events
+	if err := tx.Unscoped().Model(&dependents).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "owner_id"}, {Name: "calendar_id"}, {Name: "version"}, {Name: "delete_at"}}}).
+		Where("id IN (?)", waiting).
+		UpdateColumns(map[string]interface{}{
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	var changes []*models.OutboxEvents
+	for _, dependent := range dependents {
+		if !dependent.DeleteAt.Valid {
+			changes = append(changes, NewOutboxEvent(dependent, constant.WebhookEventUpdated))
+		}
+	}
+	return changes, nil
+}
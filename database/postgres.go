@@ -3,7 +3,10 @@ package database
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
 	"github.com/pubestpubest/g12-todo-backend/models"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
@@ -14,22 +17,151 @@ import (
 var DB *gorm.DB
 
 func ConnectDB(runEnv string) (err error) {
-	connectionString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s",
+	db, err := gorm.Open(postgres.Open(connectionString()), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	log.Info("[database]: Connected to database")
+
+	// UIDs became unique per owner rather than globally
+	if err := db.Exec(`DROP INDEX IF EXISTS idx_events_uid`).Error; err != nil {
+		log.Error("[database]: Error dropping event uid index: ", err)
+	}
+	// and later per owner within a workspace
+	if err := db.Exec(`DROP INDEX IF EXISTS idx_events_owner_uid`).Error; err != nil {
+		log.Error("[database]: Error dropping event owner uid index: ", err)
+	}
+
+	db.AutoMigrate(
+		&models.Events{},
+		&models.EventExceptions{},
+		&models.Feeds{},
+		&models.Calendars{},
+		&models.CalendarShares{},
+		&models.Users{},
+		&models.RefreshTokens{},
+		&models.APIKeys{},
+		&models.Workspaces{},
+		&models.WorkspaceMembers{},
+		&models.Tags{},
+		&models.EventTags{},
+		&models.EventItems{},
+		&models.EventDependencies{},
+		&models.Reminders{},
+		&models.Webhooks{},
+		&models.OutboxEvents{},
+		&models.WebhookDeliveries{},
+	)
+
+	if err := migrateEventSearch(db); err != nil {
+		log.Error("[database]: Error migrating event search: ", err)
+	}
+
+	if err := migrateEventStatus(db); err != nil {
+		log.Error("[database]: Error migrating event status: ", err)
+	}
+
+	if err := claimUnownedRows(db); err != nil {
+		return err
+	}
+
+	if err := RegisterTenantScope(db); err != nil {
+		return err
+	}
+
+	DB = db
+
+	return err
+}
+
+// connectionString is the DSN of the database the environment names.
+func connectionString() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s",
 		os.Getenv("DATABASE_HOST"),
 		os.Getenv("DATABASE_PORT"),
 		os.Getenv("DATABASE_USERNAME"),
 		os.Getenv("DATABASE_PASSWORD"),
 		os.Getenv("DATABASE_NAME"),
 	)
+}
 
-	db, err := gorm.Open(postgres.Open(connectionString), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
-	log.Info("[database]: Connected to database")
+// migrateEventSearch adds the generated tsvector column used for full-text
+// search over events, plus the GIN index backing it. Title matches weigh
+// more than location, which weighs more than description.
+func migrateEventSearch(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('english', coalesce(location, '')), 'B') ||
+				setweight(to_tsvector('english', coalesce(description, '')), 'C')
+			) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_events_search_vector ON events USING GIN (search_vector)`,
+	}
 
-	db.AutoMigrate(
-		&models.Events{},
-	)
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	DB = db
+// migrateEventStatus puts the events from before workflows in the state of
+// the default workflow agreeing with whether they are complete.
+func migrateEventStatus(db *gorm.DB) error {
+	return db.Exec(`UPDATE events SET status = CASE WHEN complete THEN ? ELSE ? END WHERE status = ''`,
+		constant.WorkflowDone, constant.WorkflowTodo).Error
+}
 
-	return err
+// unownedTables hold rows from before events had owners, which migrated to
+// owner 0 and so belong to no user.
+var unownedTables = []string{"events", "feeds"}
+
+// claimUnownedRows gives the rows from before owners, trashed ones included,
+// to the user signed up with the email UNOWNED_DATA_OWNER. Nobody could
+// ever reach them otherwise, so it fails while there are any and no owner
+// is named. They stay unowned until the named user signs up.
+func claimUnownedRows(db *gorm.DB) error {
+	var unowned int64
+	for _, table := range unownedTables {
+		var count int64
+		if err := db.Table(table).Where("owner_id = 0").Count(&count).Error; err != nil {
+			return errors.Wrap(err, "[database]: Error counting unowned rows")
+		}
+		unowned += count
+	}
+	if unowned == 0 {
+		return nil
+	}
+
+	email := strings.ToLower(strings.TrimSpace(os.Getenv("UNOWNED_DATA_OWNER")))
+	if email == "" {
+		return errors.Errorf("[database]: %d events and feeds have no owner; set UNOWNED_DATA_OWNER to the email of the user to give them to", unowned)
+	}
+	var owner models.Users
+	if err := db.Where("email = ?", email).First(&owner).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warnf("[database]: %d events and feeds stay unowned until %s signs up", unowned, email)
+			return nil
+		}
+		return errors.Wrap(err, "[database]: Error getting the owner of unowned rows")
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// The owner's own events keep their UIDs; the claimed ones get the
+		// generated kind
+		if err := tx.Exec(`UPDATE events SET uid = NULL WHERE owner_id = 0 AND uid IN (
+			SELECT uid FROM events WHERE owner_id = ? AND workspace_id = 0 AND delete_at IS NULL)`, owner.ID).Error; err != nil {
+			return err
+		}
+		for _, table := range unownedTables {
+			if err := tx.Table(table).Where("owner_id = 0").Update("owner_id", owner.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "[database]: Error claiming unowned rows")
+	}
+	log.Infof("[database]: Gave %d unowned events and feeds to %s", unowned, email)
+	return nil
 }
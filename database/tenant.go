@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrWorkspaceRequired fails statements on a tenant table whose context
+// names no workspace, so a repository that forgets to scope a query gets an
+// error rather than every workspace's rows.
+var ErrWorkspaceRequired = errors.New("statement on a tenant table without a workspace")
+
+// tenantTables hold the rows of a single workspace each, in their
+// workspace_id column. The join tables event_tags and event_dependencies
+// have none: they only link rows of one workspace, and are only reached
+// through rows that are scoped, so they are left out.
+var tenantTables = map[string]bool{
+	"events":           true,
+	"event_exceptions": true,
+	"event_items":      true,
+	"calendars":        true,
+	"calendar_shares":  true,
+	"feeds":            true,
+	"reminders":        true,
+	"tags":             true,
+
+	// Webhooks, the changes they are sent and their deliveries
+	"webhooks":           true,
+	"outbox_events":      true,
+	"webhook_deliveries": true,
+}
+
+type workspaceContextKey struct{}
+
+// workspaceScope is the workspace statements of a context run in; all lets
+// them see every workspace.
+type workspaceScope struct {
+	id  uint64
+	all bool
+}
+
+// WithWorkspace returns db with its statements on tenant tables limited to
+// workspaceID, which rows it creates are put in. Workspace 0 is the
+// personal space of each user.
+func WithWorkspace(db *gorm.DB, workspaceID uint64) *gorm.DB {
+	return db.WithContext(context.WithValue(db.Statement.Context, workspaceContextKey{}, workspaceScope{id: workspaceID}))
+}
+
+// AllWorkspaces returns db with its statements on tenant tables unscoped,
+// for jobs spanning workspaces and lookups by a secret such as a feed token.
+func AllWorkspaces(db *gorm.DB) *gorm.DB {
+	return db.WithContext(context.WithValue(db.Statement.Context, workspaceContextKey{}, workspaceScope{all: true}))
+}
+
+// RegisterTenantScope makes db scope every query, update and delete on a
+// tenant table to the workspace of its context and stamp created rows with
+// it. Raw SQL is left alone.
+func RegisterTenantScope(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", stampWorkspace); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeWorkspace); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeWorkspace); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeWorkspace); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("tenant:row", scopeWorkspace)
+}
+
+// statementWorkspace returns the workspace scope of a statement on a
+// tenant table; ok is false for other tables.
+func statementWorkspace(db *gorm.DB) (scope workspaceScope, ok bool) {
+	if !tenantTables[db.Statement.Table] {
+		return workspaceScope{}, false
+	}
+	scope, found := db.Statement.Context.Value(workspaceContextKey{}).(workspaceScope)
+	if !found {
+		db.AddError(errors.Wrapf(ErrWorkspaceRequired, "table %s", db.Statement.Table))
+		return workspaceScope{}, false
+	}
+	return scope, true
+}
+
+func scopeWorkspace(db *gorm.DB) {
+	scope, ok := statementWorkspace(db)
+	if !ok || scope.all {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: db.Statement.Table, Name: "workspace_id"}, Value: scope.id},
+	}})
+}
+
+func stampWorkspace(db *gorm.DB) {
+	scope, ok := statementWorkspace(db)
+	if !ok || scope.all || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField("WorkspaceID")
+	if field == nil {
+		return
+	}
+
+	stamp := func(value reflect.Value) {
+		if err := field.Set(db.Statement.Context, value, scope.id); err != nil {
+			db.AddError(err)
+		}
+	}
+	switch value := db.Statement.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			stamp(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		stamp(value)
+	}
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB returns a database with the tenant scope that only builds
+// statements, so no server is needed.
+func newDryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := RegisterTenantScope(db); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return db
+}
+
+func TestTenantScope_Query(t *testing.T) {
+	db := newDryRunDB(t)
+
+	var events []*models.Events
+	statement := WithWorkspace(db, 3).Where("owner_id = ?", 1).Find(&events).Statement
+	if !strings.Contains(statement.SQL.String(), `"events"."workspace_id" = $`) || !containsVar(statement.Vars, uint64(3)) {
+		t.Errorf("Expected the query scoped to workspace 3, got %s %v", statement.SQL.String(), statement.Vars)
+	}
+
+	statement = WithWorkspace(db, 3).Model(&models.Calendars{}).Where("id = ?", 1).Update("name", "Lectures").Statement
+	if !strings.Contains(statement.SQL.String(), `"calendars"."workspace_id" = $`) {
+		t.Errorf("Expected the update scoped to the workspace, got %s", statement.SQL.String())
+	}
+
+	statement = AllWorkspaces(db).Find(&events).Statement
+	if strings.Contains(statement.SQL.String(), "workspace_id") {
+		t.Errorf("Expected an unscoped query across workspaces, got %s", statement.SQL.String())
+	}
+
+	var users []*models.Users
+	if err := db.Find(&users).Error; err != nil {
+		t.Errorf("Expected other tables to need no workspace, got: %v", err)
+	}
+}
+
+func TestTenantScope_RequiresWorkspace(t *testing.T) {
+	db := newDryRunDB(t)
+
+	var events []*models.Events
+	if err := db.Find(&events).Error; !errors.Is(err, ErrWorkspaceRequired) {
+		t.Errorf("Expected a query without a workspace to fail, got: %v", err)
+	}
+	if err := db.Where("id = ?", 1).Delete(&models.Feeds{}).Error; !errors.Is(err, ErrWorkspaceRequired) {
+		t.Errorf("Expected a delete without a workspace to fail, got: %v", err)
+	}
+	if err := db.Create(&models.Calendars{Name: "Work"}).Error; !errors.Is(err, ErrWorkspaceRequired) {
+		t.Errorf("Expected a create without a workspace to fail, got: %v", err)
+	}
+}
+
+func TestTenantScope_Create(t *testing.T) {
+	db := newDryRunDB(t)
+
+	calendar := &models.Calendars{Name: "Work"}
+	if err := WithWorkspace(db, 3).Create(calendar).Error; err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if calendar.WorkspaceID != 3 {
+		t.Errorf("Expected the calendar put in workspace 3, got %d", calendar.WorkspaceID)
+	}
+
+	shares := []*models.CalendarShares{{CalendarID: 1, UserID: 2}, {CalendarID: 1, UserID: 3}}
+	if err := WithWorkspace(db, 4).Create(&shares).Error; err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, share := range shares {
+		if share.WorkspaceID != 4 {
+			t.Errorf("Expected every share put in workspace 4, got %d", share.WorkspaceID)
+		}
+	}
+}
+
+func containsVar(vars []interface{}, value interface{}) bool {
+	for _, v := range vars {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+var ErrAPIKeyNotFound = NewNotFoundError("api key not found")
+
+type APIKeyUsecase interface {
+	GetAPIKeyList(actor Actor) ([]*response.APIKeyResponse, error)
+	// CreateAPIKey returns the key itself, which is never shown again.
+	CreateAPIKey(actor Actor, req *request.APIKeyRequest) (*response.APIKeyResponse, error)
+	RevokeAPIKey(actor Actor, id uint64) error
+	// Authenticate resolves an API key to an actor limited to its scopes.
+	Authenticate(key string) (Actor, error)
+}
+
+type APIKeyRepository interface {
+	GetAPIKeyList(actor Actor) ([]*models.APIKeys, error)
+	GetAPIKeyByHash(keyHash string) (*models.APIKeys, error)
+	CreateAPIKey(apiKey *models.APIKeys) error
+	// TouchAPIKey records that the key was used at usedAt.
+	TouchAPIKey(id uint64, usedAt time.Time) error
+	DeleteAPIKey(actor Actor, id uint64) error
+}
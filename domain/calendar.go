@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+var (
+	ErrCalendarNotFound      = NewNotFoundError("calendar not found")
+	ErrCalendarReadOnly      = NewForbiddenError("you may only view this calendar")
+	ErrCalendarNotOwner      = NewForbiddenError("only an owner of this calendar may do this")
+	ErrCalendarNotCreator    = NewForbiddenError("only the user who created this calendar may delete it")
+	ErrCalendarShareNotFound = NewNotFoundError("calendar share not found")
+	ErrCalendarShareExists   = NewConflictError("the calendar is already shared with this user")
+)
+
+type CalendarUsecase interface {
+	// Calendars shared with the actor are listed and read like their own.
+	GetCalendarList(actor Actor) ([]*response.CalendarResponse, error)
+	GetCalendarByID(actor Actor, id uint64) (*response.CalendarResponse, error)
+	CreateCalendar(actor Actor, req *request.CalendarRequest) (*response.CalendarResponse, error)
+	// UpdateCalendar keeps the workflow when req has none. Events in states
+	// a new workflow drops move to one of its states, and events in states
+	// turning terminal or non-terminal complete or reopen.
+	UpdateCalendar(actor Actor, id uint64, req *request.CalendarRequest) (*response.CalendarResponse, error)
+	DeleteCalendar(actor Actor, id uint64, req *request.CalendarDeleteRequest) error
+	// GetCalendarEvents lists the events of a calendar like GetEventList.
+	GetCalendarEvents(actor Actor, id uint64, req *request.EventListRequest) (*response.PaginatedResponse[*response.EventResponse], error)
+
+	// Sharing. Owners of a calendar manage its shares; a user may also
+	// remove their own share to leave a calendar.
+	GetCalendarShareList(actor Actor, id uint64) ([]*response.CalendarShareResponse, error)
+	ShareCalendar(actor Actor, id uint64, req *request.CalendarShareRequest) (*response.CalendarShareResponse, error)
+	UpdateCalendarShare(actor Actor, id, shareID uint64, req *request.CalendarShareUpdateRequest) (*response.CalendarShareResponse, error)
+	DeleteCalendarShare(actor Actor, id, shareID uint64) error
+	// Invitations are the pending shares of the actor.
+	GetInvitationList(actor Actor) ([]*response.CalendarShareResponse, error)
+	AcceptInvitation(actor Actor, shareID uint64) (*response.CalendarShareResponse, error)
+	DeclineInvitation(actor Actor, shareID uint64) (*response.CalendarShareResponse, error)
+}
+
+// CalendarRepository sees the calendars the actor owns and those shared
+// with them; writes are scoped by the calendar's OwnerID.
+type CalendarRepository interface {
+	GetCalendarList(actor Actor) ([]*models.Calendars, error)
+	GetCalendarByID(actor Actor, id uint64) (*models.Calendars, error)
+	CreateCalendar(calendar *models.Calendars) error
+	// UpdateCalendar saves calendar and, with restate, hands it the events
+	// in the calendar, trashed ones included, and saves the ones it returns
+	// changed with a new version, publishing them like the EventRepository
+	// writes do, atomically.
+	UpdateCalendar(calendar *models.Calendars, restate func(events []*models.Events) []*models.Events) error
+	// DeleteCalendar deletes a calendar and its shares and, atomically,
+	// trashes its events or, with moveTo, moves them to that calendar,
+	// publishing them deleted or updated like the EventRepository writes do,
+	// and their deletion for each user the calendar was shared with.
+	DeleteCalendar(actor Actor, id uint64, moveTo *uint64) error
+
+	// Shares are returned with their Calendar and User.
+	GetCalendarShareList(actor Actor, calendarID uint64) ([]*models.CalendarShares, error)
+	// GetUserCalendarShares returns the shares of actor in the given status.
+	GetUserCalendarShares(actor Actor, status string) ([]*models.CalendarShares, error)
+	GetCalendarShareByID(actor Actor, id uint64) (*models.CalendarShares, error)
+	// GetCalendarShare returns the share of calendarID with userID, whatever its status.
+	GetCalendarShare(actor Actor, calendarID, userID uint64) (*models.CalendarShares, error)
+	CreateCalendarShare(share *models.CalendarShares) error
+	// UpdateCalendarShare and DeleteCalendarShare atomically publish, for the
+	// user of an accepted share they end alone, the deletion of the events
+	// of the calendar not in the trash.
+	UpdateCalendarShare(share *models.CalendarShares) error
+	DeleteCalendarShare(actor Actor, id uint64) error
+}
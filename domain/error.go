@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// Error is a typed business error. It is wrapped with errors.Wrap like any
+// other error on its way up; the delivery layer finds it again with
+// errors.As and picks the status code from Code.
+type Error struct {
+	Code    string
+	Message string
+	Fields  []response.FieldError // offending inputs, validation errors only
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func NewNotFoundError(message string) error {
+	return &Error{Code: constant.CodeNotFound, Message: message}
+}
+
+func NewValidationError(message string, fields ...response.FieldError) error {
+	return &Error{Code: constant.CodeValidationFailed, Message: message, Fields: fields}
+}
+
+func NewUnauthorizedError(message string) error {
+	return &Error{Code: constant.CodeUnauthorized, Message: message}
+}
+
+func NewForbiddenError(message string) error {
+	return &Error{Code: constant.CodeForbidden, Message: message}
+}
+
+func NewConflictError(message string) error {
+	return &Error{Code: constant.CodeConflict, Message: message}
+}
+
+func NewPreconditionError(message string) error {
+	return &Error{Code: constant.CodePreconditionFailed, Message: message}
+}
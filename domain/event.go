@@ -1,23 +1,218 @@
 package domain
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/pubestpubest/g12-todo-backend/models"
 	"github.com/pubestpubest/g12-todo-backend/request"
 	"github.com/pubestpubest/g12-todo-backend/response"
 )
 
+var (
+	ErrEventNotFound = NewNotFoundError("event not found")
+	// ErrEventVersionMismatch is returned when a conditional write targets
+	// an event version that is no longer current.
+	ErrEventVersionMismatch = NewPreconditionError("event has been modified since it was retrieved")
+	ErrEventUIDConflict     = NewConflictError("an event with this uid already exists")
+	ErrEventReadOnly        = NewForbiddenError("you may only view this event")
+	ErrEventNotOwner        = NewForbiddenError("only the owner of this event may do this")
+	ErrEventItemNotFound    = NewNotFoundError("checklist item not found")
+	ErrEventBlocked         = NewConflictError("event is blocked by events that are not complete")
+	ErrEventCursorInvalid   = NewValidationError("cursor is invalid for this list", response.FieldError{
+		Field: "cursor", Rule: "cursor", Message: "cursor must be a cursor of this list, as sorted now",
+	})
+	// ErrSyncTokenInvalid is returned for sync tokens that are malformed,
+	// from another user or workspace or too old to read; the client syncs
+	// from scratch.
+	ErrSyncTokenInvalid = NewValidationError("sync token is invalid, sync from scratch", response.FieldError{
+		Field: "since", Rule: "token", Message: "since must be a token returned by a sync",
+	})
+)
+
+// EventFilter narrows and orders the event list. Sort columns are already
+// validated database column names.
+type EventFilter struct {
+	Page       int
+	Limit      int
+	CalendarID *uint64
+	Type       string // event or task, both when empty
+	Complete   *bool
+	Status     string // normalized workflow state
+	Location   string
+	Tags       []string // normalized names, matching any of them
+	AllTags    bool     // match events carrying every one of Tags instead
+	From       *time.Time
+	To         *time.Time
+	Sort       []SortField
+	Query      string // full-text search terms, search only
+	// Expand returns every matching event unpaged, recurring ones by
+	// whether the series reaches into From/To, for the caller to expand.
+	Expand bool
+	// Cursor pages from Seek rather than by Page, reading up to Limit+1
+	// events so the caller can tell whether there are more, and only counts
+	// them WithTotal.
+	Cursor    bool
+	Seek      *EventSeek
+	WithTotal bool
+}
+
+// EventSeek continues a sorted list from an event: with the events after
+// it or, when Backward, the ones before it, nearest first, and the event
+// itself when Inclusive. Values holds its value of each Sort column, nil
+// for NULL.
+type EventSeek struct {
+	Values    []any
+	ID        uint64
+	Backward  bool
+	Inclusive bool
+}
+
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
 type EventUsecase interface {
-	GetEventList(page, limit int) (*response.PaginatedResponse[*response.EventResponse], error)
-	GetEventByID(id uint64) (*response.EventResponse, error)
-	CreateEvent(req *request.EventRequest) (*response.EventResponse, error)
-	UpdateEvent(id uint64, req *request.EventRequest) (*response.EventResponse, error)
-	DeleteEvent(id uint64) error
+	GetEventList(actor Actor, req *request.EventListRequest) (*response.PaginatedResponse[*response.EventResponse], error)
+	SearchEvents(actor Actor, req *request.EventSearchRequest) (*response.PaginatedResponse[*response.EventResponse], error)
+	GetEventByID(actor Actor, id uint64) (*response.EventResponse, error)
+	CreateEvent(actor Actor, req *request.EventRequest) (*response.EventResponse, error)
+	// Writes take the version from If-Match; 0 skips the version check.
+	UpdateEvent(actor Actor, id, version uint64, req *request.EventRequest) (*response.EventResponse, error)
+	PatchEvent(actor Actor, id, version uint64, contentType string, patch []byte) (*response.EventResponse, error)
+	DeleteEvent(actor Actor, id, version uint64) error
+	// Occurrence writes take scope "this" or "following" and a recurring event.
+	UpdateOccurrence(actor Actor, id, version uint64, occurrence *request.OccurrenceRequest, req *request.EventRequest) (*response.EventResponse, error)
+	DeleteOccurrence(actor Actor, id, version uint64, occurrence *request.OccurrenceRequest) error
+	// ExportEvents renders the events matching the list filters as an
+	// iCalendar feed; recurring events are exported as their series.
+	ExportEvents(actor Actor, req *request.EventListRequest) (*response.EventExportResponse, error)
+	// ImportEvents creates the VEVENTs of an iCalendar file, skipping UIDs
+	// that already exist, and reports the outcome of each one.
+	ImportEvents(actor Actor, r io.Reader) (*response.EventImportResponse, error)
+
+	GetDeletedEventList(actor Actor, req *request.PaginationRequest) (*response.PaginatedResponse[*response.EventResponse], error)
+	RestoreEvent(actor Actor, id uint64) (*response.EventResponse, error)
+	// PurgeEvent permanently deletes an event, whether or not it is in the trash.
+	PurgeEvent(actor Actor, id, version uint64) error
+	// PurgeDeletedEvents permanently deletes events of every user trashed
+	// longer than retention ago and returns how many there were.
+	PurgeDeletedEvents(retention time.Duration) (int64, error)
+	// SendDueReminders delivers the reminders of every user that are due
+	// through notifier, flagging those more than lateAfter overdue as
+	// late, and returns how many were sent.
+	SendDueReminders(ctx context.Context, notifier Notifier, lateAfter time.Duration) (int, error)
+
+	// Checklist items. Reading them takes read access to the event, writing
+	// them edit access; each write bumps the event version.
+	GetEventItemList(actor Actor, id uint64) ([]*response.EventItemResponse, error)
+	CreateEventItem(actor Actor, id uint64, req *request.EventItemRequest) (*response.EventItemResponse, error)
+	UpdateEventItem(actor Actor, id, itemID uint64, req *request.EventItemRequest) (*response.EventItemResponse, error)
+	DeleteEventItem(actor Actor, id, itemID uint64) error
+	// MoveEvent puts an event in a workflow state of its calendar at a
+	// position of that column, taking the version from If-Match.
+	MoveEvent(actor Actor, id, version uint64, req *request.EventMoveRequest) (*response.EventResponse, error)
+	// GetEventGraph returns the events the event id transitively waits on
+	// or is waited on by, the ones actor can see.
+	GetEventGraph(actor Actor, id uint64) (*response.EventGraphResponse, error)
+	// StreamEvents hands send the changes to the events actor can see made
+	// after the change after, or from now on without one, as they are made,
+	// until ctx is done or send fails. A stream idle for
+	// constant.StreamHeartbeat hands send no changes, so the connection can
+	// be kept alive.
+	StreamEvents(ctx context.Context, actor Actor, after *uint64, send func(changes []*response.EventChangeResponse) error) error
+	// SyncEvents returns a page of what changed in the events actor can see
+	// since the sync token req.Since, or of all of them without one, and
+	// the token to sync from next.
+	SyncEvents(actor Actor, req *request.SyncRequest) (*response.SyncResponse, error)
+	// ApplySyncMutations applies writes a client made offline one by one,
+	// and reports which conflict with the current version of their event.
+	ApplySyncMutations(actor Actor, req *request.SyncMutationsRequest) (*response.SyncMutationsResponse, error)
 }
 
+// EventRepository only sees the events the actor it is given owns or that
+// are in a calendar shared with them, and only the owned ones by UID or in
+// the trash; methods taking an event are scoped by its OwnerID. Whether the
+// actor may write is up to the usecase.
+//
+// Writes add the Changes of the events they save to the outbox, for
+// webhooks and the real-time stream, in the transaction saving them, and
+// notify constant.EventChangesChannel; DeleteEvent and PurgeEvent publish
+// the events they delete themselves. Saving an event whose
+// CompletionChanged bumps the version of the events waiting on it and
+// publishes them updated too.
 type EventRepository interface {
-	GetEventList(page, limit int) ([]*models.Events, int64, error)
-	GetEventByID(id uint64) (*models.Events, error)
+	GetEventList(actor Actor, filter *EventFilter) ([]*models.Events, int64, error)
+	SearchEvents(actor Actor, filter *EventFilter) ([]*models.EventSearchResult, int64, error)
+	GetEventByID(actor Actor, id uint64) (*models.Events, error)
+	GetEventByUID(actor Actor, uid string) (*models.Events, error)
+	// GetEventsByIDs returns those of the events ids actor can see.
+	GetEventsByIDs(actor Actor, ids []uint64) ([]*models.Events, error)
+	// GetChangedEvents is GetEventsByIDs with trashed events included.
+	GetChangedEvents(actor Actor, ids []uint64) ([]*models.Events, error)
+	// GetEventsAfter returns up to limit of the events actor can see with
+	// IDs above after, in order.
+	GetEventsAfter(actor Actor, after uint64, limit int) ([]*models.Events, error)
+	// CreateEvent and UpdateEvent save event.Tags by name, creating the
+	// tags the owner doesn't have yet, the IDs of event.BlockedBy and
+	// event.Reminders; PatchEvent does when columns holds "tags",
+	// "blocked_by" or "reminders".
 	CreateEvent(event *models.Events) error
+	// Writes only apply while the stored version still matches (event.Version
+	// for updates), bump it, and return ErrEventVersionMismatch otherwise.
 	UpdateEvent(event *models.Events) error
-	DeleteEvent(id uint64) error
+	PatchEvent(event *models.Events, columns []string) error
+	DeleteEvent(actor Actor, id, version uint64) error
+	GetEventExceptions(actor Actor, eventIDs []uint64) ([]*models.EventExceptions, error)
+	// SaveEventException upserts exception and bumps the event version.
+	SaveEventException(event *models.Events, exception *models.EventExceptions) error
+	// SplitEventSeries saves event, whose series was cut short, drops its
+	// exceptions past the new RecurrenceEnd and creates next, the series
+	// taking over, if any. It is atomic.
+	SplitEventSeries(event *models.Events, next *models.Events) error
+	// SaveEventItems creates or updates items, which are new or changed
+	// items of the checklist of event, deletes removed and saves the
+	// completion and status of event, atomically.
+	SaveEventItems(event *models.Events, items, removed []*models.EventItems) error
+	// NextEventPosition is the position past the last event, other than
+	// event, in the workflow column of event: its state in its calendar, or
+	// among its owner's events outside calendars.
+	NextEventPosition(event *models.Events) (int, error)
+	// MoveEvent saves the status, position and completion of event, shifts
+	// the events past the position it leaves up and then those at or past
+	// the one it takes down, and publishes them updated, atomically.
+	MoveEvent(event *models.Events) error
+	// GetEventDependencies returns the dependencies from or to any of the
+	// events ids, trashed events included.
+	GetEventDependencies(actor Actor, ids []uint64) ([]*models.EventDependencies, error)
+
+	// Trash. Deleted events are soft deleted until restored or purged.
+	GetDeletedEventList(actor Actor, filter *EventFilter) ([]*models.Events, int64, error)
+	GetDeletedEventByID(actor Actor, id uint64) (*models.Events, error)
+	// RestoreEvent undeletes event while its version still matches.
+	RestoreEvent(event *models.Events) error
+	// PurgeEvent removes an event, deleted or not, and its exceptions for good.
+	PurgeEvent(actor Actor, id, version uint64) error
+	PurgeDeletedEvents(deletedBefore time.Time) (int64, error)
+	// ProcessDueReminders claims up to limit reminders due by now of events
+	// not in the trash, of every user, skipping the ones another process
+	// holds, by leasing them for constant.ReminderLease. It then hands each
+	// to process with its Event and User, if any, loaded, outside any
+	// transaction, and saves its schedule unless it changed meanwhile.
+	ProcessDueReminders(now time.Time, limit int, process func(reminder *models.Reminders) error) error
+	// GetEventChanges returns up to limit changes made after the change
+	// after, in order, to the events actor could see when they were made or
+	// that left a calendar shared with them, and the deletions made for
+	// actor alone when they lost a calendar.
+	GetEventChanges(actor Actor, after uint64, limit int) ([]*models.OutboxEvents, error)
+	// LastEventChangeAt is when the latest change to the events actor can
+	// see was made, deletions included; nil when there was none.
+	LastEventChangeAt(actor Actor) (*time.Time, error)
+	// LastEventChangeID is the ID of the latest change to any event.
+	LastEventChangeID() (uint64, error)
+	// SubscribeEventChanges returns a channel that receives after events
+	// change, on any replica, and a func to stop receiving.
+	SubscribeEventChanges() (<-chan struct{}, func())
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+var ErrFeedNotFound = NewNotFoundError("feed not found")
+
+type FeedUsecase interface {
+	GetFeedList(actor Actor) ([]*response.FeedResponse, error)
+	CreateFeed(actor Actor, req *request.FeedRequest) (*response.FeedResponse, error)
+	// RotateFeedToken issues a new token; the previous one stops working.
+	RotateFeedToken(actor Actor, id uint64) (*response.FeedResponse, error)
+	// DeleteFeed revokes the feed and its token.
+	DeleteFeed(actor Actor, id uint64) error
+	// GetFeedCalendar is public: the token stands in for the owner.
+	GetFeedCalendar(token string) (*response.EventExportResponse, error)
+}
+
+type FeedRepository interface {
+	GetFeedList(actor Actor) ([]*models.Feeds, error)
+	GetFeedByID(actor Actor, id uint64) (*models.Feeds, error)
+	GetFeedByTokenHash(tokenHash string) (*models.Feeds, error)
+	CreateFeed(feed *models.Feeds) error
+	UpdateFeedToken(feed *models.Feeds) error
+	DeleteFeed(actor Actor, id uint64) error
+}
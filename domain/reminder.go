@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// Notifier delivers reminders, e.g. by email.
+type Notifier interface {
+	Notify(ctx context.Context, notification *response.ReminderNotification) error
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+var (
+	ErrTagNotFound = NewNotFoundError("tag not found")
+	// ErrTagExists is returned for renames too; merging the tags is the way
+	// to bring them together.
+	ErrTagExists = NewConflictError("a tag with this name already exists")
+)
+
+// TagUsecase manages the actor's own tags. Events are tagged through the
+// event usecase, which creates tags as they are first used.
+type TagUsecase interface {
+	GetTagList(actor Actor) ([]*response.TagResponse, error)
+	CreateTag(actor Actor, req *request.TagRequest) (*response.TagResponse, error)
+	// UpdateTag renames a tag on every event carrying it.
+	UpdateTag(actor Actor, id uint64, req *request.TagRequest) (*response.TagResponse, error)
+	// DeleteTag removes a tag from every event carrying it.
+	DeleteTag(actor Actor, id uint64) error
+	// MergeTag moves the events of the tag id to the target tag and deletes it.
+	MergeTag(actor Actor, id uint64, req *request.TagMergeRequest) (*response.TagResponse, error)
+}
+
+// TagRepository sees the tags the actor owns. Writes that change what the
+// events of a tag show bump the version of those events and add their
+// update to the outbox, like the EventRepository writes do.
+type TagRepository interface {
+	GetTagList(actor Actor) ([]*models.Tags, error)
+	GetTagByID(actor Actor, id uint64) (*models.Tags, error)
+	GetTagByName(actor Actor, name string) (*models.Tags, error)
+	// CountTagEvents maps the ids of tags to how many events carry them.
+	CountTagEvents(actor Actor, ids []uint64) (map[uint64]int64, error)
+	CreateTag(tag *models.Tags) error
+	UpdateTag(tag *models.Tags) error
+	DeleteTag(tag *models.Tags) error
+	// MergeTag moves the events of source to target and deletes source,
+	// atomically.
+	MergeTag(source, target *models.Tags) error
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"slices"
+
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// Actor is the authenticated caller a usecase acts on behalf of. Every
+// read and write is scoped to what the actor may see in WorkspaceID.
+type Actor struct {
+	UserID      uint64
+	WorkspaceID uint64 // constant.PersonalWorkspaceID outside of workspaces
+	// APIKeyID is set when the caller authenticated with an API key, which
+	// only grants Scopes. Login sessions may do anything.
+	APIKeyID uint64
+	Scopes   []string
+}
+
+func (a Actor) HasScope(scope string) bool {
+	return a.APIKeyID == 0 || slices.Contains(a.Scopes, scope)
+}
+
+var (
+	ErrUserNotFound = NewNotFoundError("user not found")
+	ErrEmailTaken   = NewConflictError("email is already registered")
+	// ErrInvalidCredentials does not tell unknown emails from wrong passwords.
+	ErrInvalidCredentials = NewUnauthorizedError("invalid email or password")
+	ErrInvalidToken       = NewUnauthorizedError("token is invalid or expired")
+)
+
+type UserUsecase interface {
+	Register(req *request.RegisterRequest) (*response.AuthResponse, error)
+	Login(req *request.LoginRequest) (*response.AuthResponse, error)
+	// RefreshToken exchanges a refresh token for a new pair of tokens.
+	RefreshToken(req *request.RefreshTokenRequest) (*response.AuthResponse, error)
+	Logout(req *request.RefreshTokenRequest) error
+	GetMe(actor Actor) (*response.UserResponse, error)
+	// Authenticate resolves the bearer credential of a request to its actor.
+	Authenticate(token string) (Actor, error)
+}
+
+type UserRepository interface {
+	GetUserByID(id uint64) (*models.Users, error)
+	GetUserByEmail(email string) (*models.Users, error)
+	CreateUser(user *models.Users) error
+	CreateRefreshToken(token *models.RefreshTokens) error
+	GetRefreshTokenByHash(tokenHash string) (*models.RefreshTokens, error)
+	// RevokeRefreshToken fails with ErrInvalidToken if the token was already
+	// revoked, so a token can only be spent once even under concurrency.
+	RevokeRefreshToken(id uint64) error
+	RevokeUserRefreshTokens(userID uint64) error
+}
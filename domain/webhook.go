@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+var (
+	ErrWebhookNotFound         = NewNotFoundError("webhook not found")
+	ErrWebhookDeliveryNotFound = NewNotFoundError("webhook delivery not found")
+)
+
+type WebhookUsecase interface {
+	GetWebhookList(actor Actor) ([]*response.WebhookResponse, error)
+	GetWebhookByID(actor Actor, id uint64) (*response.WebhookResponse, error)
+	// CreateWebhook returns the signing secret, which is never shown again.
+	CreateWebhook(actor Actor, req *request.WebhookRequest) (*response.WebhookResponse, error)
+	UpdateWebhook(actor Actor, id uint64, req *request.WebhookRequest) (*response.WebhookResponse, error)
+	DeleteWebhook(actor Actor, id uint64) error
+	// GetWebhookDeliveryList is the delivery log of the webhook id, newest
+	// first.
+	GetWebhookDeliveryList(actor Actor, id uint64, req *request.WebhookDeliveryListRequest) (*response.PaginatedResponse[*response.WebhookDeliveryResponse], error)
+	// RedeliverWebhookDelivery sends a delivery again, dead-lettered or
+	// not, with a fresh set of attempts.
+	RedeliverWebhookDelivery(actor Actor, id, deliveryID uint64) (*response.WebhookDeliveryResponse, error)
+	// DispatchWebhooks fans the changes in the outbox out to the webhooks
+	// subscribed to them, then sends the deliveries that are due, of every
+	// user, and returns how many were delivered.
+	DispatchWebhooks(ctx context.Context) (int, error)
+}
+
+type WebhookDeliveryFilter struct {
+	Page   int
+	Limit  int
+	Status string // any when empty
+}
+
+// WebhookRepository only sees the webhooks the actor it is given owns.
+type WebhookRepository interface {
+	GetWebhookList(actor Actor) ([]*models.Webhooks, error)
+	GetWebhookByID(actor Actor, id uint64) (*models.Webhooks, error)
+	CreateWebhook(webhook *models.Webhooks) error
+	UpdateWebhook(webhook *models.Webhooks) error
+	DeleteWebhook(actor Actor, id uint64) error
+	GetWebhookDeliveryList(webhook *models.Webhooks, filter *WebhookDeliveryFilter) ([]*models.WebhookDeliveries, int64, error)
+	GetWebhookDeliveryByID(webhook *models.Webhooks, id uint64) (*models.WebhookDeliveries, error)
+	// SaveWebhookDelivery saves the state and schedule of delivery.
+	SaveWebhookDelivery(delivery *models.WebhookDeliveries) error
+	// ProcessOutbox locks up to limit changes not fanned out yet, of every
+	// workspace, skipping the ones another process holds. It hands each to
+	// process with the webhooks of its workspace owned by the users who
+	// can see its event, creates the deliveries process returns and marks
+	// the change dispatched, all in one transaction.
+	ProcessOutbox(limit int, process func(change *models.OutboxEvents, webhooks []*models.Webhooks) ([]*models.WebhookDeliveries, error)) error
+	// ProcessDueDeliveries claims up to limit pending deliveries due by now
+	// of active webhooks, of every workspace, skipping the ones another
+	// process holds, by leasing them for constant.WebhookLease. It then
+	// hands each to process with its Webhook loaded, outside any
+	// transaction, and saves it unless it changed meanwhile.
+	ProcessDueDeliveries(now time.Time, limit int, process func(delivery *models.WebhookDeliveries) error) error
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+var (
+	// ErrWorkspaceNotFound is also returned for workspaces the actor is not
+	// a member of, so their existence is not given away.
+	ErrWorkspaceNotFound       = NewNotFoundError("workspace not found")
+	ErrWorkspaceNotAdmin       = NewForbiddenError("only an admin of this workspace may do this")
+	ErrWorkspaceMismatch       = NewForbiddenError("the api key belongs to another workspace")
+	ErrWorkspaceMemberNotFound = NewNotFoundError("workspace member not found")
+	ErrWorkspaceMemberExists   = NewConflictError("the user is already a member of this workspace")
+	ErrWorkspaceLastAdmin      = NewConflictError("a workspace needs at least one admin")
+)
+
+type WorkspaceUsecase interface {
+	GetWorkspaceList(actor Actor) ([]*response.WorkspaceResponse, error)
+	GetWorkspaceByID(actor Actor, id uint64) (*response.WorkspaceResponse, error)
+	// CreateWorkspace makes the actor its first admin.
+	CreateWorkspace(actor Actor, req *request.WorkspaceRequest) (*response.WorkspaceResponse, error)
+	UpdateWorkspace(actor Actor, id uint64, req *request.WorkspaceRequest) (*response.WorkspaceResponse, error)
+	DeleteWorkspace(actor Actor, id uint64) error
+
+	GetWorkspaceMemberList(actor Actor, id uint64) ([]*response.WorkspaceMemberResponse, error)
+	AddWorkspaceMember(actor Actor, id uint64, req *request.WorkspaceMemberRequest) (*response.WorkspaceMemberResponse, error)
+	// RemoveWorkspaceMember is for admins, or for members leaving.
+	RemoveWorkspaceMember(actor Actor, id, userID uint64) error
+
+	// ResolveWorkspace checks that actor may act in the workspace id and
+	// returns them acting in it.
+	ResolveWorkspace(actor Actor, id uint64) (Actor, error)
+}
+
+// WorkspaceRepository does not scope by actor; the usecase checks
+// membership. Members are returned with their Workspace and User.
+type WorkspaceRepository interface {
+	GetUserWorkspaceList(userID uint64) ([]*models.WorkspaceMembers, error)
+	GetWorkspaceMember(workspaceID, userID uint64) (*models.WorkspaceMembers, error)
+	GetWorkspaceMemberList(workspaceID uint64) ([]*models.WorkspaceMembers, error)
+	// CreateWorkspace creates workspace and its first member, admin, atomically.
+	CreateWorkspace(workspace *models.Workspaces, admin *models.WorkspaceMembers) error
+	UpdateWorkspace(workspace *models.Workspaces) error
+	// DeleteWorkspace deletes a workspace and its members. Its data stays
+	// behind, out of reach.
+	DeleteWorkspace(id uint64) error
+	CreateWorkspaceMember(member *models.WorkspaceMembers) error
+	DeleteWorkspaceMember(workspaceID, userID uint64) error
+}
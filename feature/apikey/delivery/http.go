@@ -0,0 +1,128 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type apiKeyHandler struct {
+	apiKeyUsecase domain.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(apiKeyUsecase domain.APIKeyUsecase) *apiKeyHandler {
+	return &apiKeyHandler{apiKeyUsecase: apiKeyUsecase}
+}
+
+func (h *apiKeyHandler) GetAPIKeyList(c *gin.Context) {
+	apiKeys, err := h.apiKeyUsecase.GetAPIKeyList(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[APIKeyHandler.GetAPIKeyList]: Error getting api key list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.APIKeyResponse]{
+		Status:  constant.Success,
+		Message: "List api keys successfully",
+		Data:    apiKeys,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *apiKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req request.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[APIKeyHandler.CreateAPIKey]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	apiKey, err := h.apiKeyUsecase.CreateAPIKey(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[APIKeyHandler.CreateAPIKey]: Error creating api key")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.APIKeyResponse]{
+		Status:  constant.Success,
+		Message: "API key created successfully",
+		Data:    apiKey,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *apiKeyHandler) RevokeAPIKey(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[APIKeyHandler.RevokeAPIKey]: Error parsing api key ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.apiKeyUsecase.RevokeAPIKey(middlewares.GetActor(c), id); err != nil {
+		err = errors.Wrap(err, "[APIKeyHandler.RevokeAPIKey]: Error revoking api key")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "API key revoked successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) domain.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) GetAPIKeyList(actor domain.Actor) ([]*models.APIKeys, error) {
+	var apiKeys []*models.APIKeys
+	if err := r.db.Where("user_id = ?", actor.UserID).Order("id").Find(&apiKeys).Error; err != nil {
+		return nil, errors.Wrap(err, "[APIKeyRepository.GetAPIKeyList]: Error getting api keys")
+	}
+	return apiKeys, nil
+}
+
+func (r *apiKeyRepository) GetAPIKeyByHash(keyHash string) (*models.APIKeys, error) {
+	var apiKey models.APIKeys
+	if err := r.db.Where("key_hash = ?", keyHash).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrAPIKeyNotFound, "[APIKeyRepository.GetAPIKeyByHash]: Error getting api key")
+		}
+		return nil, errors.Wrap(err, "[APIKeyRepository.GetAPIKeyByHash]: Error getting api key")
+	}
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) CreateAPIKey(apiKey *models.APIKeys) error {
+	now := time.Now()
+	apiKey.CreatedAt = &now
+
+	if err := r.db.Create(apiKey).Error; err != nil {
+		return errors.Wrap(err, "[APIKeyRepository.CreateAPIKey]: Error creating api key")
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchAPIKey(id uint64, usedAt time.Time) error {
+	if err := r.db.Model(&models.APIKeys{}).Where("id = ?", id).Update("last_used_at", usedAt).Error; err != nil {
+		return errors.Wrap(err, "[APIKeyRepository.TouchAPIKey]: Error updating last used")
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) DeleteAPIKey(actor domain.Actor, id uint64) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, actor.UserID).Delete(&models.APIKeys{})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[APIKeyRepository.DeleteAPIKey]: Error revoking api key")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrAPIKeyNotFound, "[APIKeyRepository.DeleteAPIKey]: Error revoking api key")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// keyPrefixLength is how much of a key is kept in the clear.
+	keyPrefixLength = len(constant.APIKeyPrefix) + 8
+	// lastUsedResolution limits how often using a key is written down.
+	lastUsedResolution = time.Minute
+)
+
+type apiKeyUsecase struct {
+	apiKeyRepository domain.APIKeyRepository
+}
+
+func NewAPIKeyUsecase(apiKeyRepository domain.APIKeyRepository) domain.APIKeyUsecase {
+	return &apiKeyUsecase{apiKeyRepository: apiKeyRepository}
+}
+
+func (u *apiKeyUsecase) GetAPIKeyList(actor domain.Actor) ([]*response.APIKeyResponse, error) {
+	apiKeys, err := u.apiKeyRepository.GetAPIKeyList(actor)
+	if err != nil {
+		return nil, errors.Wrap(err, "[APIKeyUsecase.GetAPIKeyList]: Error getting api keys")
+	}
+
+	apiKeyResponses := make([]*response.APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		apiKeyResponses = append(apiKeyResponses, newAPIKeyResponse(apiKey))
+	}
+	return apiKeyResponses, nil
+}
+
+func (u *apiKeyUsecase) CreateAPIKey(actor domain.Actor, req *request.APIKeyRequest) (*response.APIKeyResponse, error) {
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "[APIKeyUsecase.CreateAPIKey]: Error generating key")
+	}
+
+	scopes := slices.Clone(req.Scopes)
+	slices.Sort(scopes)
+	// Keys act in the workspace they are created in
+	apiKey := &models.APIKeys{
+		UserID:      actor.UserID,
+		WorkspaceID: actor.WorkspaceID,
+		Name:        req.Name,
+		Prefix:      key[:keyPrefixLength],
+		KeyHash:     hashAPIKey(key),
+		Scopes:      slices.Compact(scopes),
+	}
+	if err := u.apiKeyRepository.CreateAPIKey(apiKey); err != nil {
+		return nil, errors.Wrap(err, "[APIKeyUsecase.CreateAPIKey]: Error creating api key")
+	}
+
+	apiKeyResponse := newAPIKeyResponse(apiKey)
+	apiKeyResponse.Key = &key
+	return apiKeyResponse, nil
+}
+
+func (u *apiKeyUsecase) RevokeAPIKey(actor domain.Actor, id uint64) error {
+	if err := u.apiKeyRepository.DeleteAPIKey(actor, id); err != nil {
+		return errors.Wrap(err, "[APIKeyUsecase.RevokeAPIKey]: Error revoking api key")
+	}
+	return nil
+}
+
+func (u *apiKeyUsecase) Authenticate(key string) (domain.Actor, error) {
+	apiKey, err := u.apiKeyRepository.GetAPIKeyByHash(hashAPIKey(key))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return domain.Actor{}, errors.Wrap(domain.ErrInvalidToken, "[APIKeyUsecase.Authenticate]: Unknown api key")
+	}
+	if err != nil {
+		return domain.Actor{}, errors.Wrap(err, "[APIKeyUsecase.Authenticate]: Error getting api key")
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		// Failing to record use is no reason to turn the caller away
+		if err := u.apiKeyRepository.TouchAPIKey(apiKey.ID, now); err != nil {
+			log.Warn(errors.Wrap(err, "[APIKeyUsecase.Authenticate]: Error recording use"))
+		}
+	}
+
+	return domain.Actor{UserID: apiKey.UserID, WorkspaceID: apiKey.WorkspaceID, APIKeyID: apiKey.ID, Scopes: apiKey.Scopes}, nil
+}
+
+// newAPIKey returns a random key carrying constant.APIKeyPrefix.
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return constant.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAPIKeyResponse(apiKey *models.APIKeys) *response.APIKeyResponse {
+	return &response.APIKeyResponse{
+		ID:          apiKey.ID,
+		WorkspaceID: apiKey.WorkspaceID,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		Scopes:      apiKey.Scopes,
+		LastUsedAt:  apiKey.LastUsedAt,
+		CreatedAt:   apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+// Mock repository for testing
+type mockAPIKeyRepository struct {
+	apiKeys []*models.APIKeys
+	touched int
+}
+
+func (m *mockAPIKeyRepository) GetAPIKeyList(actor domain.Actor) ([]*models.APIKeys, error) {
+	var apiKeys []*models.APIKeys
+	for _, apiKey := range m.apiKeys {
+		if apiKey.UserID == actor.UserID {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	return apiKeys, nil
+}
+
+func (m *mockAPIKeyRepository) GetAPIKeyByHash(keyHash string) (*models.APIKeys, error) {
+	for _, apiKey := range m.apiKeys {
+		if apiKey.KeyHash == keyHash {
+			return apiKey, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (m *mockAPIKeyRepository) CreateAPIKey(apiKey *models.APIKeys) error {
+	apiKey.ID = uint64(len(m.apiKeys) + 1)
+	now := time.Now()
+	apiKey.CreatedAt = &now
+	m.apiKeys = append(m.apiKeys, apiKey)
+	return nil
+}
+
+func (m *mockAPIKeyRepository) TouchAPIKey(id uint64, usedAt time.Time) error {
+	for _, apiKey := range m.apiKeys {
+		if apiKey.ID == id {
+			apiKey.LastUsedAt = &usedAt
+			m.touched++
+		}
+	}
+	return nil
+}
+
+func (m *mockAPIKeyRepository) DeleteAPIKey(actor domain.Actor, id uint64) error {
+	for i, apiKey := range m.apiKeys {
+		if apiKey.ID == id && apiKey.UserID == actor.UserID {
+			m.apiKeys = append(m.apiKeys[:i], m.apiKeys[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+var testActor = domain.Actor{UserID: 1}
+
+func TestAPIKeyUsecase_CreateAPIKey(t *testing.T) {
+	mockRepo := &mockAPIKeyRepository{}
+	usecase := NewAPIKeyUsecase(mockRepo)
+
+	apiKey, err := usecase.CreateAPIKey(testActor, &request.APIKeyRequest{
+		Name:   "CI",
+		Scopes: []string{constant.ScopeEventsWrite, constant.ScopeEventsRead, constant.ScopeEventsWrite},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if apiKey.Key == nil || !strings.HasPrefix(*apiKey.Key, constant.APIKeyPrefix) || len(*apiKey.Key) < 40 {
+		t.Fatalf("Expected a long prefixed key, got %v", apiKey.Key)
+	}
+	stored := mockRepo.apiKeys[0]
+	if stored.KeyHash == *apiKey.Key || stored.KeyHash != hashAPIKey(*apiKey.Key) {
+		t.Error("Expected only the key hash to be stored")
+	}
+	if !strings.HasPrefix(*apiKey.Key, stored.Prefix) || len(stored.Prefix) != keyPrefixLength {
+		t.Errorf("Expected the start of the key as its prefix, got %q", stored.Prefix)
+	}
+	if len(stored.Scopes) != 2 || stored.Scopes[0] != constant.ScopeEventsRead || stored.Scopes[1] != constant.ScopeEventsWrite {
+		t.Errorf("Expected sorted unique scopes, got %v", stored.Scopes)
+	}
+
+	apiKeys, err := usecase.GetAPIKeyList(testActor)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(apiKeys) != 1 || apiKeys[0].Key != nil {
+		t.Errorf("Expected one key listed without the key itself, got %+v", apiKeys)
+	}
+	if apiKeys, _ := usecase.GetAPIKeyList(domain.Actor{UserID: 2}); len(apiKeys) != 0 {
+		t.Errorf("Expected no keys for another user, got %+v", apiKeys)
+	}
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	mockRepo := &mockAPIKeyRepository{}
+	usecase := NewAPIKeyUsecase(mockRepo)
+
+	created, err := usecase.CreateAPIKey(testActor, &request.APIKeyRequest{Name: "CI", Scopes: []string{constant.ScopeEventsRead}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	actor, err := usecase.Authenticate(*created.Key)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if actor.UserID != testActor.UserID || actor.APIKeyID != created.ID {
+		t.Errorf("Expected the key's user and ID, got %+v", actor)
+	}
+	if !actor.HasScope(constant.ScopeEventsRead) || actor.HasScope(constant.ScopeEventsWrite) {
+		t.Errorf("Expected only the granted scopes, got %v", actor.Scopes)
+	}
+	if !testActor.HasScope(constant.ScopeEventsWrite) {
+		t.Error("Expected login sessions to hold every scope")
+	}
+
+	// Use within a minute is not written down again
+	if _, err := usecase.Authenticate(*created.Key); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockRepo.touched != 1 || mockRepo.apiKeys[0].LastUsedAt == nil {
+		t.Errorf("Expected last use recorded once, got %d times", mockRepo.touched)
+	}
+
+	if err := usecase.RevokeAPIKey(domain.Actor{UserID: 2}, created.ID); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("Expected another user's key to be not found, got: %v", err)
+	}
+	if err := usecase.RevokeAPIKey(testActor, created.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.Authenticate(*created.Key); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected a revoked key to be invalid, got: %v", err)
+	}
+
+	// Keys act in the workspace they were created in
+	inWorkspace, err := usecase.CreateAPIKey(domain.Actor{UserID: testActor.UserID, WorkspaceID: 4}, &request.APIKeyRequest{Name: "Class", Scopes: []string{constant.ScopeEventsRead}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if actor, err := usecase.Authenticate(*inWorkspace.Key); err != nil || actor.WorkspaceID != 4 || inWorkspace.WorkspaceID != 4 {
+		t.Errorf("Expected a key bound to workspace 4, got %+v (%v)", actor, err)
+	}
+}
@@ -0,0 +1,628 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type calendarHandler struct {
+	calendarUsecase domain.CalendarUsecase
+}
+
+func NewCalendarHandler(calendarUsecase domain.CalendarUsecase) *calendarHandler {
+	return &calendarHandler{calendarUsecase: calendarUsecase}
+}
+
+func (h *calendarHandler) GetCalendarList(c *gin.Context) {
+	calendars, err := h.calendarUsecase.GetCalendarList(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarList]: Error getting calendar list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.CalendarResponse]{
+		Status:  constant.Success,
+		Message: "List calendars successfully",
+		Data:    calendars,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) GetCalendarByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarByID]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	calendar, err := h.calendarUsecase.GetCalendarByID(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarByID]: Error getting calendar")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.CalendarResponse]{
+		Status:  constant.Success,
+		Message: "Get calendar successfully",
+		Data:    calendar,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) CreateCalendar(c *gin.Context) {
+	var req request.CalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.CreateCalendar]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	calendar, err := h.calendarUsecase.CreateCalendar(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.CreateCalendar]: Error creating calendar")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.CalendarResponse]{
+		Status:  constant.Success,
+		Message: "Calendar created successfully",
+		Data:    calendar,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *calendarHandler) UpdateCalendar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.UpdateCalendar]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.CalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.UpdateCalendar]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	calendar, err := h.calendarUsecase.UpdateCalendar(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.UpdateCalendar]: Error updating calendar")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.CalendarResponse]{
+		Status:  constant.Success,
+		Message: "Calendar updated successfully",
+		Data:    calendar,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteCalendar takes ?mode=cascade to trash the events of the calendar
+// along with it, or ?mode=move&moveTo={id} to keep them in another one.
+func (h *calendarHandler) DeleteCalendar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeleteCalendar]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.CalendarDeleteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeleteCalendar]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.calendarUsecase.DeleteCalendar(middlewares.GetActor(c), id, &req); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeleteCalendar]: Error deleting calendar")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Calendar deleted successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) GetCalendarEvents(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarEvents]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var listReq request.EventListRequest
+
+	// Set default values
+	listReq.Page = 1
+	listReq.Limit = 10
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&listReq); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarEvents]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	events, err := h.calendarUsecase.GetCalendarEvents(middlewares.GetActor(c), id, &listReq)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarEvents]: Error getting calendar events")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.PaginatedResponse[*response.EventResponse]{
+		Status:     constant.Success,
+		Message:    "List calendar events successfully",
+		Data:       events.Data,
+		Pagination: events.Pagination,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) GetCalendarShareList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarShareList]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	shares, err := h.calendarUsecase.GetCalendarShareList(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetCalendarShareList]: Error getting calendar shares")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.CalendarShareResponse]{
+		Status:  constant.Success,
+		Message: "List calendar shares successfully",
+		Data:    shares,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ShareCalendar invites a user to the calendar; the share grants access
+// once they accept it.
+func (h *calendarHandler) ShareCalendar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.ShareCalendar]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.CalendarShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.ShareCalendar]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	share, err := h.calendarUsecase.ShareCalendar(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.ShareCalendar]: Error sharing calendar")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.CalendarShareResponse]{
+		Status:  constant.Success,
+		Message: "Calendar shared successfully",
+		Data:    share,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *calendarHandler) UpdateCalendarShare(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.UpdateCalendarShare]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	shareIDStr := c.Param("shareId")
+	shareID, err := strconv.ParseUint(shareIDStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.UpdateCalendarShare]: Error parsing share ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.CalendarShareUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.UpdateCalendarShare]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	share, err := h.calendarUsecase.UpdateCalendarShare(middlewares.GetActor(c), id, shareID, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.UpdateCalendarShare]: Error updating calendar share")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.CalendarShareResponse]{
+		Status:  constant.Success,
+		Message: "Calendar share updated successfully",
+		Data:    share,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) DeleteCalendarShare(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeleteCalendarShare]: Error parsing calendar ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	shareIDStr := c.Param("shareId")
+	shareID, err := strconv.ParseUint(shareIDStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeleteCalendarShare]: Error parsing share ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.calendarUsecase.DeleteCalendarShare(middlewares.GetActor(c), id, shareID); err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeleteCalendarShare]: Error deleting calendar share")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Calendar share deleted successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) GetInvitationList(c *gin.Context) {
+	invitations, err := h.calendarUsecase.GetInvitationList(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.GetInvitationList]: Error getting invitations")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.CalendarShareResponse]{
+		Status:  constant.Success,
+		Message: "List invitations successfully",
+		Data:    invitations,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) AcceptInvitation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.AcceptInvitation]: Error parsing invitation ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	share, err := h.calendarUsecase.AcceptInvitation(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.AcceptInvitation]: Error accepting invitation")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.CalendarShareResponse]{
+		Status:  constant.Success,
+		Message: "Invitation accepted successfully",
+		Data:    share,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *calendarHandler) DeclineInvitation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeclineInvitation]: Error parsing invitation ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	share, err := h.calendarUsecase.DeclineInvitation(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[CalendarHandler.DeclineInvitation]: Error declining invitation")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.CalendarShareResponse]{
+		Status:  constant.Success,
+		Message: "Invitation declined successfully",
+		Data:    share,
+	}
+	c.JSON(http.StatusOK, resp)
+}
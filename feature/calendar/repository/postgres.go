@@ -0,0 +1,354 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type calendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) domain.CalendarRepository {
+	return &calendarRepository{db: db}
+}
+
+// scopeCalendars limits query to the calendars of actor's workspace that
+// actor owns or has accepted a share of.
+func scopeCalendars(query *gorm.DB, actor domain.Actor) *gorm.DB {
+	query = database.WithWorkspace(query, actor.WorkspaceID)
+	shared := query.Session(&gorm.Session{NewDB: true}).Model(&models.CalendarShares{}).
+		Select("calendar_id").
+		Where("user_id = ? AND status = ?", actor.UserID, constant.CalendarShareAccepted)
+	return query.Where("(calendars.owner_id = ? OR calendars.id IN (?))", actor.UserID, shared)
+}
+
+func (r *calendarRepository) GetCalendarList(actor domain.Actor) ([]*models.Calendars, error) {
+	var calendars []*models.Calendars
+	if err := scopeCalendars(r.db.Model(&models.Calendars{}), actor).Order("name").Order("id").Find(&calendars).Error; err != nil {
+		return nil, errors.Wrap(err, "[CalendarRepository.GetCalendarList]: Error getting calendars")
+	}
+	return calendars, nil
+}
+
+func (r *calendarRepository) GetCalendarByID(actor domain.Actor, id uint64) (*models.Calendars, error) {
+	var calendar models.Calendars
+	if err := scopeCalendars(r.db.Model(&models.Calendars{}), actor).Where("calendars.id = ?", id).First(&calendar).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrCalendarNotFound, "[CalendarRepository.GetCalendarByID]: Error getting calendar")
+		}
+		return nil, errors.Wrap(err, "[CalendarRepository.GetCalendarByID]: Error getting calendar")
+	}
+	return &calendar, nil
+}
+
+func (r *calendarRepository) CreateCalendar(calendar *models.Calendars) error {
+	now := time.Now()
+	calendar.CreatedAt = &now
+	calendar.UpdatedAt = &now
+
+	if err := database.WithWorkspace(r.db, calendar.WorkspaceID).Create(calendar).Error; err != nil {
+		return errors.Wrap(err, "[CalendarRepository.CreateCalendar]: Error creating calendar")
+	}
+	return nil
+}
+
+func (r *calendarRepository) UpdateCalendar(calendar *models.Calendars, restate func(events []*models.Events) []*models.Events) error {
+	now := time.Now()
+	calendar.UpdatedAt = &now
+
+	err := database.WithWorkspace(r.db, calendar.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(calendar).
+			Where("owner_id = ?", calendar.OwnerID).
+			Select("name", "color", "timezone", "workflow", "updated_at").
+			Updates(calendar)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrCalendarNotFound
+		}
+		if restate == nil {
+			return nil
+		}
+
+		// Every event in the calendar, whoever's, trashed ones included so
+		// restoring them puts them in a state that still exists
+		var events []*models.Events
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "owner_id", "calendar_id", "status", "position", "complete", "completed_at", "version", "delete_at").
+			Where("calendar_id = ?", calendar.ID).
+			Order("position").Order("id").
+			Find(&events).Error; err != nil {
+			return err
+		}
+
+		changed := restate(events)
+		for _, event := range changed {
+			event.UpdatedAt = &now
+			event.Version++
+			if err := tx.Unscoped().Model(event).
+				Select("status", "position", "complete", "completed_at", "version", "updated_at").
+				Updates(event).Error; err != nil {
+				return err
+			}
+		}
+		return database.SaveEventChanges(tx, changed...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[CalendarRepository.UpdateCalendar]: Error updating calendar")
+	}
+	return nil
+}
+
+func (r *calendarRepository) DeleteCalendar(actor domain.Actor, id uint64, moveTo *uint64) error {
+	err := database.WithWorkspace(r.db, actor.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND owner_id = ?", id, actor.UserID).Delete(&models.Calendars{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrCalendarNotFound
+		}
+
+		// The users the calendar was shared with lose its events
+		var userIDs []uint64
+		if err := tx.Model(&models.CalendarShares{}).
+			Where("calendar_id = ? AND status = ?", id, constant.CalendarShareAccepted).
+			Order("user_id").
+			Pluck("user_id", &userIDs).Error; err != nil {
+			return err
+		}
+		revoked, err := revokedChanges(tx, id, userIDs...)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("calendar_id = ?", id).Delete(&models.CalendarShares{}).Error; err != nil {
+			return err
+		}
+
+		if moveTo == nil {
+			// Trashed events keep pointing at the calendar; restoring them
+			// takes them out of it
+			var events []*models.Events
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "owner_id", "calendar_id", "version").
+				Where("owner_id = ? AND calendar_id = ?", actor.UserID, id).
+				Order("id").
+				Find(&events).Error; err != nil {
+				return err
+			}
+			if len(events) == 0 {
+				return database.CreateOutboxEvents(tx, revoked...)
+			}
+			if err := tx.Where("id IN ?", eventIDs(events)).Delete(&models.Events{}).Error; err != nil {
+				return err
+			}
+
+			changes := make([]*models.OutboxEvents, 0, len(events)+len(revoked))
+			for _, event := range events {
+				changes = append(changes, database.NewOutboxEvent(event, constant.WebhookEventDeleted))
+			}
+			return database.CreateOutboxEvents(tx, append(changes, revoked...)...)
+		}
+
+		// Trashed events move too, so restoring them puts them in moveTo
+		var events []*models.Events
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "owner_id", "calendar_id", "version", "delete_at").
+			Where("owner_id = ? AND calendar_id = ?", actor.UserID, id).
+			Order("id").
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return database.CreateOutboxEvents(tx, revoked...)
+		}
+		if err := tx.Unscoped().Model(&models.Events{}).
+			Where("id IN ?", eventIDs(events)).
+			Updates(map[string]interface{}{
+				"calendar_id": *moveTo,
+				"updated_at":  time.Now(),
+				"version":     gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return err
+		}
+
+		// Only the events not in the trash are published; the others were
+		// already published as deleted
+		var changes []*models.OutboxEvents
+		for _, event := range events {
+			if event.DeleteAt.Valid {
+				continue
+			}
+			event.CalendarID = moveTo
+			event.Version++
+			changes = append(changes, database.NewOutboxEvent(event, constant.WebhookEventUpdated))
+		}
+		return database.CreateOutboxEvents(tx, append(changes, revoked...)...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[CalendarRepository.DeleteCalendar]: Error deleting calendar")
+	}
+	return nil
+}
+
+// revokedChanges returns, for each of userIDs, a deletion of every event of
+// calendarID not in the trash meant for that user alone, so the users who
+// lose the calendar drop its events.
+func revokedChanges(tx *gorm.DB, calendarID uint64, userIDs ...uint64) ([]*models.OutboxEvents, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var events []*models.Events
+	if err := tx.Select("id", "owner_id", "calendar_id", "version").
+		Where("calendar_id = ?", calendarID).
+		Order("id").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	changes := make([]*models.OutboxEvents, 0, len(userIDs)*len(events))
+	for _, userID := range userIDs {
+		for _, event := range events {
+			change := database.NewOutboxEvent(event, constant.WebhookEventDeleted)
+			change.UserID = &userID
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// eventIDs returns the IDs of events.
+func eventIDs(events []*models.Events) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func (r *calendarRepository) GetCalendarShareList(actor domain.Actor, calendarID uint64) ([]*models.CalendarShares, error) {
+	var shares []*models.CalendarShares
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Preload("Calendar").Preload("User").
+		Where("calendar_id = ?", calendarID).Order("id").Find(&shares).Error; err != nil {
+		return nil, errors.Wrap(err, "[CalendarRepository.GetCalendarShareList]: Error getting calendar shares")
+	}
+	return shares, nil
+}
+
+func (r *calendarRepository) GetUserCalendarShares(actor domain.Actor, status string) ([]*models.CalendarShares, error) {
+	var shares []*models.CalendarShares
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Preload("Calendar").Preload("User").
+		Where("user_id = ? AND status = ?", actor.UserID, status).Order("id").Find(&shares).Error; err != nil {
+		return nil, errors.Wrap(err, "[CalendarRepository.GetUserCalendarShares]: Error getting calendar shares")
+	}
+	return shares, nil
+}
+
+func (r *calendarRepository) GetCalendarShareByID(actor domain.Actor, id uint64) (*models.CalendarShares, error) {
+	var share models.CalendarShares
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Preload("Calendar").Preload("User").Where("id = ?", id).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrCalendarShareNotFound, "[CalendarRepository.GetCalendarShareByID]: Error getting calendar share")
+		}
+		return nil, errors.Wrap(err, "[CalendarRepository.GetCalendarShareByID]: Error getting calendar share")
+	}
+	return &share, nil
+}
+
+func (r *calendarRepository) GetCalendarShare(actor domain.Actor, calendarID, userID uint64) (*models.CalendarShares, error) {
+	var share models.CalendarShares
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Preload("Calendar").Preload("User").
+		Where("calendar_id = ? AND user_id = ?", calendarID, userID).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrCalendarShareNotFound, "[CalendarRepository.GetCalendarShare]: Error getting calendar share")
+		}
+		return nil, errors.Wrap(err, "[CalendarRepository.GetCalendarShare]: Error getting calendar share")
+	}
+	return &share, nil
+}
+
+func (r *calendarRepository) CreateCalendarShare(share *models.CalendarShares) error {
+	now := time.Now()
+	share.CreatedAt = &now
+	share.UpdatedAt = &now
+
+	if err := database.WithWorkspace(r.db, share.WorkspaceID).Omit("Calendar", "User").Create(share).Error; err != nil {
+		return errors.Wrap(err, "[CalendarRepository.CreateCalendarShare]: Error creating calendar share")
+	}
+	return nil
+}
+
+func (r *calendarRepository) UpdateCalendarShare(share *models.CalendarShares) error {
+	now := time.Now()
+	share.UpdatedAt = &now
+
+	err := database.WithWorkspace(r.db, share.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		var previous models.CalendarShares
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			Where("id = ?", share.ID).
+			First(&previous).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrCalendarShareNotFound
+			}
+			return err
+		}
+		if err := tx.Model(share).Select("invited_by", "role", "status", "updated_at").Updates(share).Error; err != nil {
+			return err
+		}
+		if previous.Status != constant.CalendarShareAccepted || share.Status == constant.CalendarShareAccepted {
+			return nil
+		}
+
+		revoked, err := revokedChanges(tx, share.CalendarID, share.UserID)
+		if err != nil {
+			return err
+		}
+		return database.CreateOutboxEvents(tx, revoked...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[CalendarRepository.UpdateCalendarShare]: Error updating calendar share")
+	}
+	return nil
+}
+
+func (r *calendarRepository) DeleteCalendarShare(actor domain.Actor, id uint64) error {
+	err := database.WithWorkspace(r.db, actor.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		var share models.CalendarShares
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "calendar_id", "user_id", "status").
+			Where("id = ?", id).
+			First(&share).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrCalendarShareNotFound
+			}
+			return err
+		}
+		if err := tx.Where("id = ?", id).Delete(&models.CalendarShares{}).Error; err != nil {
+			return err
+		}
+		if share.Status != constant.CalendarShareAccepted {
+			return nil
+		}
+
+		revoked, err := revokedChanges(tx, share.CalendarID, share.UserID)
+		if err != nil {
+			return err
+		}
+		return database.CreateOutboxEvents(tx, revoked...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[CalendarRepository.DeleteCalendarShare]: Error deleting calendar share")
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+func (u *calendarUsecase) GetCalendarShareList(actor domain.Actor, id uint64) ([]*response.CalendarShareResponse, error) {
+	if _, err := u.getOwnedCalendar(actor, id); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarShareList]: Error getting calendar")
+	}
+
+	shares, err := u.calendarRepository.GetCalendarShareList(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarShareList]: Error getting calendar shares")
+	}
+	return newCalendarShareResponses(shares), nil
+}
+
+func (u *calendarUsecase) ShareCalendar(actor domain.Actor, id uint64, req *request.CalendarShareRequest) (*response.CalendarShareResponse, error) {
+	calendar, err := u.getOwnedCalendar(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.ShareCalendar]: Error getting calendar")
+	}
+
+	user, err := u.userRepository.GetUserByEmail(strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			err = domain.NewValidationError("user not found", response.FieldError{
+				Field: "email", Rule: "exists", Message: "email must belong to a registered user",
+			})
+		}
+		return nil, errors.Wrap(err, "[CalendarUsecase.ShareCalendar]: Error getting user")
+	}
+	if user.ID == calendar.OwnerID {
+		return nil, errors.Wrap(domain.NewValidationError("the calendar belongs to this user", response.FieldError{
+			Field: "email", Rule: "ne", Message: "email must not be the calendar owner's",
+		}), "[CalendarUsecase.ShareCalendar]: Invalid user")
+	}
+	// Calendars are only shared within their workspace
+	if _, err := u.workspaceUsecase.ResolveWorkspace(domain.Actor{UserID: user.ID}, actor.WorkspaceID); err != nil {
+		if errors.Is(err, domain.ErrWorkspaceNotFound) {
+			err = domain.NewValidationError("user is not a member of this workspace", response.FieldError{
+				Field: "email", Rule: "member", Message: "email must belong to a member of this workspace",
+			})
+		}
+		return nil, errors.Wrap(err, "[CalendarUsecase.ShareCalendar]: Invalid user")
+	}
+
+	// A declined invitation may be sent again; any other share stands
+	share, err := u.calendarRepository.GetCalendarShare(actor, id, user.ID)
+	if err != nil && !errors.Is(err, domain.ErrCalendarShareNotFound) {
+		return nil, errors.Wrap(err, "[CalendarUsecase.ShareCalendar]: Error checking calendar share")
+	}
+	if share != nil && share.Status != constant.CalendarShareDeclined {
+		return nil, errors.Wrap(domain.ErrCalendarShareExists, "[CalendarUsecase.ShareCalendar]: Duplicate share")
+	}
+
+	if share == nil {
+		share = &models.CalendarShares{
+			WorkspaceID: actor.WorkspaceID,
+			CalendarID:  id,
+			UserID:      user.ID,
+			InvitedBy:   actor.UserID,
+			Role:        req.Role,
+			Status:      constant.CalendarSharePending,
+		}
+		err = u.calendarRepository.CreateCalendarShare(share)
+	} else {
+		share.InvitedBy = actor.UserID
+		share.Role = req.Role
+		share.Status = constant.CalendarSharePending
+		err = u.calendarRepository.UpdateCalendarShare(share)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.ShareCalendar]: Error saving calendar share")
+	}
+
+	share.Calendar = calendar
+	share.User = user
+	return newCalendarShareResponse(share), nil
+}
+
+func (u *calendarUsecase) UpdateCalendarShare(actor domain.Actor, id, shareID uint64, req *request.CalendarShareUpdateRequest) (*response.CalendarShareResponse, error) {
+	if _, err := u.getOwnedCalendar(actor, id); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.UpdateCalendarShare]: Error getting calendar")
+	}
+
+	share, err := u.getCalendarShare(actor, id, shareID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.UpdateCalendarShare]: Error getting calendar share")
+	}
+
+	share.Role = req.Role
+	if err := u.calendarRepository.UpdateCalendarShare(share); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.UpdateCalendarShare]: Error updating calendar share")
+	}
+	return newCalendarShareResponse(share), nil
+}
+
+func (u *calendarUsecase) DeleteCalendarShare(actor domain.Actor, id, shareID uint64) error {
+	share, err := u.getCalendarShare(actor, id, shareID)
+	if err != nil {
+		return errors.Wrap(err, "[CalendarUsecase.DeleteCalendarShare]: Error getting calendar share")
+	}
+
+	// Anyone may leave a calendar shared with them
+	if share.UserID != actor.UserID {
+		if _, err := u.getOwnedCalendar(actor, id); err != nil {
+			return errors.Wrap(err, "[CalendarUsecase.DeleteCalendarShare]: Error getting calendar")
+		}
+	}
+
+	if err := u.calendarRepository.DeleteCalendarShare(actor, share.ID); err != nil {
+		return errors.Wrap(err, "[CalendarUsecase.DeleteCalendarShare]: Error deleting calendar share")
+	}
+	return nil
+}
+
+func (u *calendarUsecase) GetInvitationList(actor domain.Actor) ([]*response.CalendarShareResponse, error) {
+	shares, err := u.calendarRepository.GetUserCalendarShares(actor, constant.CalendarSharePending)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetInvitationList]: Error getting invitations")
+	}
+	return newCalendarShareResponses(shares), nil
+}
+
+func (u *calendarUsecase) AcceptInvitation(actor domain.Actor, shareID uint64) (*response.CalendarShareResponse, error) {
+	share, err := u.answerInvitation(actor, shareID, constant.CalendarShareAccepted)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.AcceptInvitation]: Error accepting invitation")
+	}
+	return newCalendarShareResponse(share), nil
+}
+
+func (u *calendarUsecase) DeclineInvitation(actor domain.Actor, shareID uint64) (*response.CalendarShareResponse, error) {
+	share, err := u.answerInvitation(actor, shareID, constant.CalendarShareDeclined)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.DeclineInvitation]: Error declining invitation")
+	}
+	return newCalendarShareResponse(share), nil
+}
+
+// answerInvitation moves a pending share of actor to status.
+func (u *calendarUsecase) answerInvitation(actor domain.Actor, shareID uint64, status string) (*models.CalendarShares, error) {
+	share, err := u.calendarRepository.GetCalendarShareByID(actor, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.UserID != actor.UserID || share.Status != constant.CalendarSharePending {
+		return nil, domain.ErrCalendarShareNotFound
+	}
+
+	share.Status = status
+	if err := u.calendarRepository.UpdateCalendarShare(share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// getOwnedCalendar returns the calendar id, which actor must be an owner of.
+func (u *calendarUsecase) getOwnedCalendar(actor domain.Actor, id uint64) (*models.Calendars, error) {
+	calendar, err := u.calendarRepository.GetCalendarByID(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := u.calendarAccess(actor, calendar)
+	if err != nil {
+		return nil, err
+	}
+	if access != constant.CalendarRoleOwner {
+		return nil, domain.ErrCalendarNotOwner
+	}
+	return calendar, nil
+}
+
+// getCalendarShare returns the share shareID of the calendar id.
+func (u *calendarUsecase) getCalendarShare(actor domain.Actor, id, shareID uint64) (*models.CalendarShares, error) {
+	share, err := u.calendarRepository.GetCalendarShareByID(actor, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.CalendarID != id {
+		return nil, domain.ErrCalendarShareNotFound
+	}
+	return share, nil
+}
+
+func newCalendarShareResponses(shares []*models.CalendarShares) []*response.CalendarShareResponse {
+	shareResponses := make([]*response.CalendarShareResponse, 0, len(shares))
+	for _, share := range shares {
+		shareResponses = append(shareResponses, newCalendarShareResponse(share))
+	}
+	return shareResponses
+}
+
+func newCalendarShareResponse(share *models.CalendarShares) *response.CalendarShareResponse {
+	shareResponse := &response.CalendarShareResponse{
+		ID:         share.ID,
+		CalendarID: share.CalendarID,
+		UserID:     share.UserID,
+		InvitedBy:  share.InvitedBy,
+		Role:       share.Role,
+		Status:     share.Status,
+		CreatedAt:  share.CreatedAt,
+		UpdatedAt:  share.UpdatedAt,
+	}
+	if share.Calendar != nil {
+		shareResponse.CalendarName = share.Calendar.Name
+	}
+	if share.User != nil {
+		shareResponse.Email = share.User.Email
+		shareResponse.Name = share.User.Name
+	}
+	return shareResponse
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+)
+
+type calendarUsecase struct {
+	calendarRepository domain.CalendarRepository
+	userRepository     domain.UserRepository
+	workspaceUsecase   domain.WorkspaceUsecase
+	eventUsecase       domain.EventUsecase
+}
+
+func NewCalendarUsecase(calendarRepository domain.CalendarRepository, userRepository domain.UserRepository, workspaceUsecase domain.WorkspaceUsecase, eventUsecase domain.EventUsecase) domain.CalendarUsecase {
+	return &calendarUsecase{
+		calendarRepository: calendarRepository,
+		userRepository:     userRepository,
+		workspaceUsecase:   workspaceUsecase,
+		eventUsecase:       eventUsecase,
+	}
+}
+
+func (u *calendarUsecase) GetCalendarList(actor domain.Actor) ([]*response.CalendarResponse, error) {
+	calendars, err := u.calendarRepository.GetCalendarList(actor)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarList]: Error getting calendars")
+	}
+
+	shares, err := u.calendarRepository.GetUserCalendarShares(actor, constant.CalendarShareAccepted)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarList]: Error getting calendar shares")
+	}
+	roles := make(map[uint64]string, len(shares))
+	for _, share := range shares {
+		roles[share.CalendarID] = share.Role
+	}
+
+	calendarResponses := make([]*response.CalendarResponse, 0, len(calendars))
+	for _, calendar := range calendars {
+		access := constant.CalendarRoleOwner
+		if calendar.OwnerID != actor.UserID {
+			access = roles[calendar.ID]
+		}
+		calendarResponses = append(calendarResponses, newCalendarResponse(calendar, access))
+	}
+	return calendarResponses, nil
+}
+
+func (u *calendarUsecase) GetCalendarByID(actor domain.Actor, id uint64) (*response.CalendarResponse, error) {
+	calendar, err := u.calendarRepository.GetCalendarByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarByID]: Error getting calendar")
+	}
+
+	access, err := u.calendarAccess(actor, calendar)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarByID]: Error getting access")
+	}
+	return newCalendarResponse(calendar, access), nil
+}
+
+func (u *calendarUsecase) CreateCalendar(actor domain.Actor, req *request.CalendarRequest) (*response.CalendarResponse, error) {
+	calendar := &models.Calendars{OwnerID: actor.UserID, WorkspaceID: actor.WorkspaceID}
+	if err := setCalendar(calendar, req); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.CreateCalendar]: Invalid calendar")
+	}
+
+	if err := u.calendarRepository.CreateCalendar(calendar); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.CreateCalendar]: Error creating calendar")
+	}
+	return newCalendarResponse(calendar, constant.CalendarRoleOwner), nil
+}
+
+func (u *calendarUsecase) UpdateCalendar(actor domain.Actor, id uint64, req *request.CalendarRequest) (*response.CalendarResponse, error) {
+	calendar, err := u.calendarRepository.GetCalendarByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.UpdateCalendar]: Error getting calendar")
+	}
+
+	access, err := u.calendarAccess(actor, calendar)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.UpdateCalendar]: Error getting access")
+	}
+	if access != constant.CalendarRoleOwner {
+		return nil, errors.Wrap(domain.ErrCalendarNotOwner, "[CalendarUsecase.UpdateCalendar]: Error updating calendar")
+	}
+
+	previous := calendarWorkflow(calendar)
+	if err := setCalendar(calendar, req); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.UpdateCalendar]: Invalid calendar")
+	}
+	var restate func(events []*models.Events) []*models.Events
+	if workflow := calendarWorkflow(calendar); !slices.Equal(workflow, previous) {
+		restate = restateEvents(workflow)
+	}
+	if err := u.calendarRepository.UpdateCalendar(calendar, restate); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.UpdateCalendar]: Error updating calendar")
+	}
+	return newCalendarResponse(calendar, access), nil
+}
+
+func (u *calendarUsecase) DeleteCalendar(actor domain.Actor, id uint64, req *request.CalendarDeleteRequest) error {
+	calendar, err := u.calendarRepository.GetCalendarByID(actor, id)
+	if err != nil {
+		return errors.Wrap(err, "[CalendarUsecase.DeleteCalendar]: Error getting calendar")
+	}
+
+	// Its events are the creator's, so sharing it does not hand them over
+	if calendar.OwnerID != actor.UserID {
+		return errors.Wrap(domain.ErrCalendarNotCreator, "[CalendarUsecase.DeleteCalendar]: Error deleting calendar")
+	}
+
+	var moveTo *uint64
+	if req.Mode == constant.CalendarDeleteMove {
+		if req.MoveTo == nil || *req.MoveTo == id {
+			return errors.Wrap(domain.NewValidationError("moveTo must be another calendar", response.FieldError{
+				Field: "moveTo", Rule: "ne", Message: "moveTo must be another calendar",
+			}), "[CalendarUsecase.DeleteCalendar]: Invalid target")
+		}
+		target, err := u.calendarRepository.GetCalendarByID(actor, *req.MoveTo)
+		if err != nil && !errors.Is(err, domain.ErrCalendarNotFound) {
+			return errors.Wrap(err, "[CalendarUsecase.DeleteCalendar]: Error getting target calendar")
+		}
+		if target == nil || target.OwnerID != actor.UserID {
+			return errors.Wrap(domain.NewValidationError("calendar to move events to not found", response.FieldError{
+				Field: "moveTo", Rule: "exists", Message: "moveTo must be one of your calendars",
+			}), "[CalendarUsecase.DeleteCalendar]: Invalid target")
+		}
+		moveTo = req.MoveTo
+	}
+
+	if err := u.calendarRepository.DeleteCalendar(actor, id, moveTo); err != nil {
+		return errors.Wrap(err, "[CalendarUsecase.DeleteCalendar]: Error deleting calendar")
+	}
+	return nil
+}
+
+func (u *calendarUsecase) GetCalendarEvents(actor domain.Actor, id uint64, req *request.EventListRequest) (*response.PaginatedResponse[*response.EventResponse], error) {
+	if _, err := u.calendarRepository.GetCalendarByID(actor, id); err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarEvents]: Error getting calendar")
+	}
+
+	req.CalendarID = &id
+	events, err := u.eventUsecase.GetEventList(actor, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CalendarUsecase.GetCalendarEvents]: Error getting events")
+	}
+	return events, nil
+}
+
+// calendarAccess is the role actor has on calendar, which they can see.
+func (u *calendarUsecase) calendarAccess(actor domain.Actor, calendar *models.Calendars) (string, error) {
+	if calendar.OwnerID == actor.UserID {
+		return constant.CalendarRoleOwner, nil
+	}
+
+	share, err := u.calendarRepository.GetCalendarShare(actor, calendar.ID, actor.UserID)
+	if err != nil {
+		return "", err
+	}
+	if share.Status != constant.CalendarShareAccepted {
+		return "", nil
+	}
+	return share.Role, nil
+}
+
+// setCalendar copies req onto calendar, filling in the defaults and
+// keeping its workflow when req has none.
+func setCalendar(calendar *models.Calendars, req *request.CalendarRequest) error {
+	calendar.Name = req.Name
+	calendar.Color = constant.DefaultCalendarColor
+	if req.Color != "" {
+		calendar.Color = req.Color
+	}
+	calendar.Timezone = constant.DefaultCalendarTimezone
+	if req.Timezone != "" {
+		calendar.Timezone = req.Timezone
+	}
+
+	if req.Workflow == nil {
+		return nil
+	}
+	workflow, err := newWorkflow(req.Workflow)
+	if err != nil {
+		return err
+	}
+	calendar.Workflow = workflow
+	return nil
+}
+
+// newWorkflow is the workflow of states, nil for the default one when there
+// are none. Names are normalized and must be unique, and there must be a
+// state events are complete in and one they aren't.
+func newWorkflow(states []*request.WorkflowStateRequest) ([]models.WorkflowState, error) {
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	workflow := make([]models.WorkflowState, 0, len(states))
+	var terminal, open bool
+	for _, state := range states {
+		name := utils.NormalizeStatus(state.Name)
+		if name == "" {
+			return nil, domain.NewValidationError("workflow state names must not be blank", response.FieldError{
+				Field: "workflow", Rule: "required", Message: "workflow state names must not be blank",
+			})
+		}
+		if slices.ContainsFunc(workflow, func(other models.WorkflowState) bool { return other.Name == name }) {
+			message := fmt.Sprintf("workflow state %q appears more than once", name)
+			return nil, domain.NewValidationError(message, response.FieldError{
+				Field: "workflow", Rule: "unique", Message: message,
+			})
+		}
+		workflow = append(workflow, models.WorkflowState{Name: name, Terminal: state.Terminal})
+		terminal = terminal || state.Terminal
+		open = open || !state.Terminal
+	}
+
+	if !terminal || !open {
+		return nil, domain.NewValidationError("workflow needs a terminal and a non-terminal state", response.FieldError{
+			Field: "workflow", Rule: "terminal", Message: "workflow must have both a terminal and a non-terminal state",
+		})
+	}
+	return workflow, nil
+}
+
+func newCalendarResponse(calendar *models.Calendars, access string) *response.CalendarResponse {
+	return &response.CalendarResponse{
+		ID:        calendar.ID,
+		OwnerID:   calendar.OwnerID,
+		Name:      calendar.Name,
+		Color:     calendar.Color,
+		Timezone:  calendar.Timezone,
+		Access:    access,
+		CreatedAt: calendar.CreatedAt,
+		UpdatedAt: calendar.UpdatedAt,
+		Workflow:  newWorkflowResponse(calendar.Workflow),
+	}
+}
+
+// newWorkflowResponse lists the states of workflow, the default ones when
+// nil.
+func newWorkflowResponse(workflow []models.WorkflowState) []*response.WorkflowStateResponse {
+	if workflow == nil {
+		workflow = models.DefaultWorkflow
+	}
+	states := make([]*response.WorkflowStateResponse, 0, len(workflow))
+	for _, state := range workflow {
+		states = append(states, &response.WorkflowStateResponse{Name: state.Name, Terminal: state.Terminal})
+	}
+	return states
+}
@@ -0,0 +1,589 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// Mock repository for testing
+type mockCalendarRepository struct {
+	calendars  []*models.Calendars
+	shares     []*models.CalendarShares
+	deletedID  uint64
+	lastMoveTo *uint64
+	events     []*models.Events // in the calendars
+	restated   []*models.Events
+}
+
+func (m *mockCalendarRepository) visible(actor domain.Actor, calendar *models.Calendars) bool {
+	if calendar.WorkspaceID != actor.WorkspaceID {
+		return false
+	}
+	if calendar.OwnerID == actor.UserID {
+		return true
+	}
+	share, err := m.GetCalendarShare(actor, calendar.ID, actor.UserID)
+	return err == nil && share.Status == constant.CalendarShareAccepted
+}
+
+func (m *mockCalendarRepository) GetCalendarList(actor domain.Actor) ([]*models.Calendars, error) {
+	var calendars []*models.Calendars
+	for _, calendar := range m.calendars {
+		if m.visible(actor, calendar) {
+			calendars = append(calendars, calendar)
+		}
+	}
+	return calendars, nil
+}
+
+func (m *mockCalendarRepository) GetCalendarByID(actor domain.Actor, id uint64) (*models.Calendars, error) {
+	for _, calendar := range m.calendars {
+		if calendar.ID == id && m.visible(actor, calendar) {
+			return calendar, nil
+		}
+	}
+	return nil, domain.ErrCalendarNotFound
+}
+
+func (m *mockCalendarRepository) CreateCalendar(calendar *models.Calendars) error {
+	calendar.ID = uint64(len(m.calendars) + 1)
+	now := time.Now()
+	calendar.CreatedAt = &now
+	calendar.UpdatedAt = &now
+	m.calendars = append(m.calendars, calendar)
+	return nil
+}
+
+func (m *mockCalendarRepository) UpdateCalendar(calendar *models.Calendars, restate func(events []*models.Events) []*models.Events) error {
+	now := time.Now()
+	calendar.UpdatedAt = &now
+	m.restated = nil
+	if restate == nil {
+		return nil
+	}
+
+	var events []*models.Events
+	for _, event := range m.events {
+		if event.CalendarID != nil && *event.CalendarID == calendar.ID {
+			events = append(events, event)
+		}
+	}
+	m.restated = restate(events)
+	for _, event := range m.restated {
+		event.Version++
+	}
+	return nil
+}
+
+func (m *mockCalendarRepository) DeleteCalendar(actor domain.Actor, id uint64, moveTo *uint64) error {
+	for i, calendar := range m.calendars {
+		if calendar.ID == id && calendar.OwnerID == actor.UserID && calendar.WorkspaceID == actor.WorkspaceID {
+			m.calendars = append(m.calendars[:i], m.calendars[i+1:]...)
+			m.deletedID = id
+			m.lastMoveTo = moveTo
+			return nil
+		}
+	}
+	return domain.ErrCalendarNotFound
+}
+
+func (m *mockCalendarRepository) GetCalendarShareList(actor domain.Actor, calendarID uint64) ([]*models.CalendarShares, error) {
+	var shares []*models.CalendarShares
+	for _, share := range m.shares {
+		if share.CalendarID == calendarID && share.WorkspaceID == actor.WorkspaceID {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+func (m *mockCalendarRepository) GetUserCalendarShares(actor domain.Actor, status string) ([]*models.CalendarShares, error) {
+	var shares []*models.CalendarShares
+	for _, share := range m.shares {
+		if share.UserID == actor.UserID && share.WorkspaceID == actor.WorkspaceID && share.Status == status {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+func (m *mockCalendarRepository) GetCalendarShareByID(actor domain.Actor, id uint64) (*models.CalendarShares, error) {
+	for _, share := range m.shares {
+		if share.ID == id && share.WorkspaceID == actor.WorkspaceID {
+			return share, nil
+		}
+	}
+	return nil, domain.ErrCalendarShareNotFound
+}
+
+func (m *mockCalendarRepository) GetCalendarShare(actor domain.Actor, calendarID, userID uint64) (*models.CalendarShares, error) {
+	for _, share := range m.shares {
+		if share.CalendarID == calendarID && share.UserID == userID && share.WorkspaceID == actor.WorkspaceID {
+			return share, nil
+		}
+	}
+	return nil, domain.ErrCalendarShareNotFound
+}
+
+func (m *mockCalendarRepository) CreateCalendarShare(share *models.CalendarShares) error {
+	share.ID = uint64(len(m.shares) + 1)
+	m.shares = append(m.shares, share)
+	return nil
+}
+
+func (m *mockCalendarRepository) UpdateCalendarShare(share *models.CalendarShares) error {
+	return nil
+}
+
+func (m *mockCalendarRepository) DeleteCalendarShare(actor domain.Actor, id uint64) error {
+	for i, share := range m.shares {
+		if share.ID == id && share.WorkspaceID == actor.WorkspaceID {
+			m.shares = append(m.shares[:i], m.shares[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrCalendarShareNotFound
+}
+
+// Mock user repository, only lookups by email are used by calendars
+type mockUserRepository struct {
+	domain.UserRepository
+	users []*models.Users
+}
+
+func (m *mockUserRepository) GetUserByEmail(email string) (*models.Users, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+// Mock workspace usecase, resolving members of workspaces to the users in
+// members
+type mockWorkspaceUsecase struct {
+	domain.WorkspaceUsecase
+	members map[uint64][]uint64
+}
+
+func (m *mockWorkspaceUsecase) ResolveWorkspace(actor domain.Actor, id uint64) (domain.Actor, error) {
+	if id != constant.PersonalWorkspaceID && !slices.Contains(m.members[id], actor.UserID) {
+		return domain.Actor{}, domain.ErrWorkspaceNotFound
+	}
+	actor.WorkspaceID = id
+	return actor, nil
+}
+
+// Mock event usecase, only listing is used by calendars
+type mockEventUsecase struct {
+	domain.EventUsecase
+	lastReq *request.EventListRequest
+}
+
+func (m *mockEventUsecase) GetEventList(actor domain.Actor, req *request.EventListRequest) (*response.PaginatedResponse[*response.EventResponse], error) {
+	m.lastReq = req
+	return &response.PaginatedResponse[*response.EventResponse]{}, nil
+}
+
+var testActor = domain.Actor{UserID: 1}
+
+func TestCalendarUsecase_CreateCalendar(t *testing.T) {
+	mockRepo := &mockCalendarRepository{}
+	usecase := NewCalendarUsecase(mockRepo, &mockUserRepository{}, &mockWorkspaceUsecase{}, &mockEventUsecase{})
+
+	calendar, err := usecase.CreateCalendar(testActor, &request.CalendarRequest{Name: "Work"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if calendar.Color != constant.DefaultCalendarColor || calendar.Timezone != constant.DefaultCalendarTimezone {
+		t.Errorf("Expected the default color and timezone, got %+v", calendar)
+	}
+	if mockRepo.calendars[0].OwnerID != testActor.UserID {
+		t.Errorf("Expected the calendar owned by the actor, got %d", mockRepo.calendars[0].OwnerID)
+	}
+
+	updated, err := usecase.UpdateCalendar(testActor, calendar.ID, &request.CalendarRequest{
+		Name: "School", Color: "#ff0000", Timezone: "Asia/Bangkok",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Name != "School" || updated.Color != "#ff0000" || updated.Timezone != "Asia/Bangkok" {
+		t.Errorf("Expected the updated calendar, got %+v", updated)
+	}
+
+	if _, err := usecase.GetCalendarByID(domain.Actor{UserID: 2}, calendar.ID); !errors.Is(err, domain.ErrCalendarNotFound) {
+		t.Errorf("Expected another user's calendar to be not found, got: %v", err)
+	}
+}
+
+func TestCalendarUsecase_CreateCalendar_Workflow(t *testing.T) {
+	tests := []struct {
+		name             string
+		workflow         []*request.WorkflowStateRequest
+		expectedWorkflow []string
+		expectedErrMsg   string
+	}{
+		{
+			name:             "default workflow",
+			expectedWorkflow: []string{"todo", "in progress", "review", "done"},
+		},
+		{
+			name: "own workflow",
+			workflow: []*request.WorkflowStateRequest{
+				{Name: " Backlog "}, {Name: "DOING"}, {Name: "Shipped", Terminal: true},
+			},
+			expectedWorkflow: []string{"backlog", "doing", "shipped"},
+		},
+		{
+			name: "duplicate state",
+			workflow: []*request.WorkflowStateRequest{
+				{Name: "todo"}, {Name: "Todo"}, {Name: "done", Terminal: true},
+			},
+			expectedErrMsg: "appears more than once",
+		},
+		{
+			name:           "no terminal state",
+			workflow:       []*request.WorkflowStateRequest{{Name: "todo"}, {Name: "doing"}},
+			expectedErrMsg: "needs a terminal and a non-terminal state",
+		},
+		{
+			name:           "only terminal states",
+			workflow:       []*request.WorkflowStateRequest{{Name: "done", Terminal: true}},
+			expectedErrMsg: "needs a terminal and a non-terminal state",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := NewCalendarUsecase(&mockCalendarRepository{}, &mockUserRepository{}, &mockWorkspaceUsecase{}, &mockEventUsecase{})
+			calendar, err := usecase.CreateCalendar(testActor, &request.CalendarRequest{Name: "Work", Workflow: tt.workflow})
+
+			if tt.expectedErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error containing '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var names []string
+			for _, state := range calendar.Workflow {
+				names = append(names, state.Name)
+			}
+			if !slices.Equal(names, tt.expectedWorkflow) || !calendar.Workflow[len(names)-1].Terminal {
+				t.Errorf("Expected workflow %v ending in a terminal state, got %+v", tt.expectedWorkflow, calendar.Workflow)
+			}
+		})
+	}
+}
+
+func TestCalendarUsecase_UpdateCalendar_Workflow(t *testing.T) {
+	work := uint64(1)
+	completedAt := time.Now().Add(-time.Hour)
+	newEvent := func(id uint64, status string, position int, complete bool) *models.Events {
+		event := &models.Events{ID: id, OwnerID: testActor.UserID, CalendarID: &work, Status: status, Position: position, Complete: complete, Version: 1}
+		if complete {
+			event.CompletedAt = &completedAt
+		}
+		return event
+	}
+	mockRepo := &mockCalendarRepository{
+		calendars: []*models.Calendars{{ID: work, OwnerID: testActor.UserID, Name: "Work"}},
+		events: []*models.Events{
+			newEvent(1, constant.WorkflowTodo, 0, false),
+			newEvent(2, constant.WorkflowInProgress, 0, false),
+			newEvent(3, constant.WorkflowReview, 0, false),
+			newEvent(4, constant.WorkflowReview, 1, false),
+			newEvent(5, constant.WorkflowDone, 0, true),
+		},
+	}
+	usecase := NewCalendarUsecase(mockRepo, &mockUserRepository{}, &mockWorkspaceUsecase{}, &mockEventUsecase{})
+
+	// Review turns terminal, in progress is dropped and done reopens
+	calendar, err := usecase.UpdateCalendar(testActor, work, &request.CalendarRequest{
+		Name: "Work",
+		Workflow: []*request.WorkflowStateRequest{
+			{Name: "todo"}, {Name: "review", Terminal: true}, {Name: "done"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(calendar.Workflow) != 3 {
+		t.Fatalf("Expected the new workflow, got %+v", calendar.Workflow)
+	}
+
+	expected := []struct {
+		status   string
+		position int
+		complete bool
+		change   string
+	}{
+		{status: constant.WorkflowTodo, position: 0, complete: false},
+		{status: constant.WorkflowTodo, position: 1, complete: false, change: constant.WebhookEventUpdated},
+		{status: constant.WorkflowReview, position: 0, complete: true, change: constant.WebhookEventCompleted},
+		{status: constant.WorkflowReview, position: 1, complete: true, change: constant.WebhookEventCompleted},
+		{status: constant.WorkflowDone, position: 0, complete: false, change: constant.WebhookEventUpdated},
+	}
+	for i, event := range mockRepo.events {
+		want := expected[i]
+		if event.Status != want.status || event.Position != want.position || event.Complete != want.complete ||
+			(event.CompletedAt != nil) != want.complete {
+			t.Errorf("Expected event %d %s at %d complete %v, got %s at %d complete %v since %v",
+				event.ID, want.status, want.position, want.complete, event.Status, event.Position, event.Complete, event.CompletedAt)
+		}
+		restated := slices.Contains(mockRepo.restated, event)
+		if restated != (want.change != "") || restated && !slices.Equal(event.Changes, []string{want.change}) {
+			t.Errorf("Expected event %d to publish %q, got %v (restated: %v)", event.ID, want.change, event.Changes, restated)
+		}
+		if (event.Version == 2) != restated {
+			t.Errorf("Expected event %d at a new version only when restated, got %d", event.ID, event.Version)
+		}
+	}
+
+	// Left out, the workflow stays and so do the events
+	calendar, err = usecase.UpdateCalendar(testActor, work, &request.CalendarRequest{Name: "Office"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(calendar.Workflow) != 3 || calendar.Workflow[1].Name != constant.WorkflowReview || mockRepo.restated != nil {
+		t.Errorf("Expected the workflow kept and no event restated, got %+v, restated %v", calendar.Workflow, mockRepo.restated)
+	}
+}
+
+func TestCalendarUsecase_DeleteCalendar(t *testing.T) {
+	home := uint64(2)
+	theirs := uint64(3)
+	same := uint64(1)
+
+	tests := []struct {
+		name           string
+		req            *request.CalendarDeleteRequest
+		expectedError  bool
+		expectedErrMsg string
+		expectedMoveTo *uint64
+	}{
+		{
+			name: "cascade",
+			req:  &request.CalendarDeleteRequest{Mode: constant.CalendarDeleteCascade},
+		},
+		{
+			name:           "move to another calendar",
+			req:            &request.CalendarDeleteRequest{Mode: constant.CalendarDeleteMove, MoveTo: &home},
+			expectedMoveTo: &home,
+		},
+		{
+			name:           "move to itself",
+			req:            &request.CalendarDeleteRequest{Mode: constant.CalendarDeleteMove, MoveTo: &same},
+			expectedError:  true,
+			expectedErrMsg: "moveTo must be another calendar",
+		},
+		{
+			name:           "move to another user's calendar",
+			req:            &request.CalendarDeleteRequest{Mode: constant.CalendarDeleteMove, MoveTo: &theirs},
+			expectedError:  true,
+			expectedErrMsg: "calendar to move events to not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockCalendarRepository{calendars: []*models.Calendars{
+				{ID: 1, OwnerID: testActor.UserID, Name: "Work"},
+				{ID: 2, OwnerID: testActor.UserID, Name: "Home"},
+				{ID: 3, OwnerID: 2, Name: "Theirs"},
+			}}
+			usecase := NewCalendarUsecase(mockRepo, &mockUserRepository{}, &mockWorkspaceUsecase{}, &mockEventUsecase{})
+
+			err := usecase.DeleteCalendar(testActor, 1, tt.req)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if mockRepo.deletedID != 1 {
+				t.Errorf("Expected calendar 1 deleted, got %d", mockRepo.deletedID)
+			}
+			if (mockRepo.lastMoveTo == nil) != (tt.expectedMoveTo == nil) ||
+				tt.expectedMoveTo != nil && *mockRepo.lastMoveTo != *tt.expectedMoveTo {
+				t.Errorf("Expected events moved to %v, got %v", tt.expectedMoveTo, mockRepo.lastMoveTo)
+			}
+		})
+	}
+}
+
+func TestCalendarUsecase_GetCalendarEvents(t *testing.T) {
+	mockRepo := &mockCalendarRepository{calendars: []*models.Calendars{{ID: 1, OwnerID: testActor.UserID, Name: "Work"}}}
+	mockEvents := &mockEventUsecase{}
+	usecase := NewCalendarUsecase(mockRepo, &mockUserRepository{}, &mockWorkspaceUsecase{}, mockEvents)
+
+	req := &request.EventListRequest{PaginationRequest: request.PaginationRequest{Page: 1, Limit: 10}}
+	if _, err := usecase.GetCalendarEvents(testActor, 1, req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockEvents.lastReq.CalendarID == nil || *mockEvents.lastReq.CalendarID != 1 {
+		t.Errorf("Expected the events of calendar 1, got %v", mockEvents.lastReq.CalendarID)
+	}
+
+	if _, err := usecase.GetCalendarEvents(domain.Actor{UserID: 2}, 1, req); !errors.Is(err, domain.ErrCalendarNotFound) {
+		t.Errorf("Expected another user's calendar to be not found, got: %v", err)
+	}
+}
+
+func TestCalendarUsecase_Sharing(t *testing.T) {
+	sam := &models.Users{ID: 2, Email: "sam@example.com", Name: "Sam"}
+	kim := &models.Users{ID: 3, Email: "kim@example.com", Name: "Kim"}
+	samActor := domain.Actor{UserID: sam.ID}
+	kimActor := domain.Actor{UserID: kim.ID}
+
+	mockRepo := &mockCalendarRepository{calendars: []*models.Calendars{{ID: 1, OwnerID: testActor.UserID, Name: "Work"}}}
+	users := &mockUserRepository{users: []*models.Users{{ID: 1, Email: "alex@example.com"}, sam, kim}}
+	usecase := NewCalendarUsecase(mockRepo, users, &mockWorkspaceUsecase{}, &mockEventUsecase{})
+
+	share, err := usecase.ShareCalendar(testActor, 1, &request.CalendarShareRequest{Email: " Sam@Example.com", Role: constant.CalendarRoleViewer})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if share.Status != constant.CalendarSharePending || share.Email != sam.Email || share.CalendarName != "Work" {
+		t.Errorf("Expected a pending invitation for Sam, got %+v", share)
+	}
+
+	if _, err := usecase.ShareCalendar(testActor, 1, &request.CalendarShareRequest{Email: sam.Email, Role: constant.CalendarRoleEditor}); !errors.Is(err, domain.ErrCalendarShareExists) {
+		t.Errorf("Expected a duplicate share to conflict, got: %v", err)
+	}
+	if _, err := usecase.ShareCalendar(testActor, 1, &request.CalendarShareRequest{Email: "alex@example.com", Role: constant.CalendarRoleEditor}); err == nil {
+		t.Error("Expected sharing with the owner to fail")
+	}
+	if _, err := usecase.ShareCalendar(testActor, 1, &request.CalendarShareRequest{Email: "nobody@example.com", Role: constant.CalendarRoleEditor}); err == nil {
+		t.Error("Expected sharing with an unknown email to fail")
+	}
+
+	// Until accepted, the calendar stays hidden
+	if _, err := usecase.GetCalendarByID(samActor, 1); !errors.Is(err, domain.ErrCalendarNotFound) {
+		t.Errorf("Expected the calendar to be not found before accepting, got: %v", err)
+	}
+	invitations, err := usecase.GetInvitationList(samActor)
+	if err != nil || len(invitations) != 1 {
+		t.Fatalf("Expected one invitation, got %+v (%v)", invitations, err)
+	}
+	if _, err := usecase.AcceptInvitation(kimActor, share.ID); !errors.Is(err, domain.ErrCalendarShareNotFound) {
+		t.Errorf("Expected another user's invitation to be not found, got: %v", err)
+	}
+	if _, err := usecase.AcceptInvitation(samActor, share.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	calendars, err := usecase.GetCalendarList(samActor)
+	if err != nil || len(calendars) != 1 || calendars[0].Access != constant.CalendarRoleViewer {
+		t.Fatalf("Expected the shared calendar listed for a viewer, got %+v (%v)", calendars, err)
+	}
+
+	// Viewers neither manage the calendar nor its shares
+	if _, err := usecase.UpdateCalendar(samActor, 1, &request.CalendarRequest{Name: "Mine"}); !errors.Is(err, domain.ErrCalendarNotOwner) {
+		t.Errorf("Expected a viewer's update to be forbidden, got: %v", err)
+	}
+	if _, err := usecase.ShareCalendar(samActor, 1, &request.CalendarShareRequest{Email: kim.Email, Role: constant.CalendarRoleViewer}); !errors.Is(err, domain.ErrCalendarNotOwner) {
+		t.Errorf("Expected a viewer's share to be forbidden, got: %v", err)
+	}
+
+	// Owners by share manage it, but only its creator deletes it
+	if _, err := usecase.UpdateCalendarShare(testActor, 1, share.ID, &request.CalendarShareUpdateRequest{Role: constant.CalendarRoleOwner}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	kimShare, err := usecase.ShareCalendar(samActor, 1, &request.CalendarShareRequest{Email: kim.Email, Role: constant.CalendarRoleEditor})
+	if err != nil {
+		t.Fatalf("Expected a shared owner to share, got: %v", err)
+	}
+	if err := usecase.DeleteCalendar(samActor, 1, &request.CalendarDeleteRequest{Mode: constant.CalendarDeleteCascade}); !errors.Is(err, domain.ErrCalendarNotCreator) {
+		t.Errorf("Expected only the creator to delete the calendar, got: %v", err)
+	}
+
+	// Declined invitations may be sent again
+	if _, err := usecase.DeclineInvitation(kimActor, kimShare.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.ShareCalendar(testActor, 1, &request.CalendarShareRequest{Email: kim.Email, Role: constant.CalendarRoleViewer}); err != nil {
+		t.Errorf("Expected a declined invitation to be sent again, got: %v", err)
+	}
+
+	// Anyone may leave
+	if err := usecase.DeleteCalendarShare(samActor, 1, share.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.GetCalendarByID(samActor, 1); !errors.Is(err, domain.ErrCalendarNotFound) {
+		t.Errorf("Expected the calendar gone after leaving, got: %v", err)
+	}
+}
+
+func TestCalendarUsecase_Workspaces(t *testing.T) {
+	sam := &models.Users{ID: 2, Email: "sam@example.com", Name: "Sam"}
+	kim := &models.Users{ID: 3, Email: "kim@example.com", Name: "Kim"}
+	class := domain.Actor{UserID: testActor.UserID, WorkspaceID: 5}
+
+	mockRepo := &mockCalendarRepository{calendars: []*models.Calendars{{ID: 1, OwnerID: testActor.UserID, Name: "Personal"}}}
+	users := &mockUserRepository{users: []*models.Users{sam, kim}}
+	workspaces := &mockWorkspaceUsecase{members: map[uint64][]uint64{5: {testActor.UserID, sam.ID}}}
+	usecase := NewCalendarUsecase(mockRepo, users, workspaces, &mockEventUsecase{})
+
+	lectures, err := usecase.CreateCalendar(class, &request.CalendarRequest{Name: "Lectures"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockRepo.calendars[1].WorkspaceID != class.WorkspaceID {
+		t.Errorf("Expected the calendar created in workspace %d, got %d", class.WorkspaceID, mockRepo.calendars[1].WorkspaceID)
+	}
+
+	if calendars, _ := usecase.GetCalendarList(class); len(calendars) != 1 || calendars[0].ID != lectures.ID {
+		t.Errorf("Expected only the workspace calendar in the workspace, got %+v", calendars)
+	}
+	if _, err := usecase.GetCalendarByID(testActor, lectures.ID); !errors.Is(err, domain.ErrCalendarNotFound) {
+		t.Errorf("Expected the workspace calendar to be not found in the personal space, got: %v", err)
+	}
+
+	// Calendars are only shared within their workspace
+	share, err := usecase.ShareCalendar(class, lectures.ID, &request.CalendarShareRequest{Email: sam.Email, Role: constant.CalendarRoleViewer})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockRepo.shares[0].WorkspaceID != class.WorkspaceID {
+		t.Errorf("Expected the share created in workspace %d, got %d", class.WorkspaceID, mockRepo.shares[0].WorkspaceID)
+	}
+	_, err = usecase.ShareCalendar(class, lectures.ID, &request.CalendarShareRequest{Email: kim.Email, Role: constant.CalendarRoleViewer})
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Code != constant.CodeValidationFailed {
+		t.Errorf("Expected sharing with a non-member to fail validation, got: %v", err)
+	}
+
+	if invitations, _ := usecase.GetInvitationList(domain.Actor{UserID: sam.ID}); len(invitations) != 0 {
+		t.Errorf("Expected no invitations in the personal space, got %+v", invitations)
+	}
+	if _, err := usecase.AcceptInvitation(domain.Actor{UserID: sam.ID}, share.ID); !errors.Is(err, domain.ErrCalendarShareNotFound) {
+		t.Errorf("Expected the invitation to be not found outside its workspace, got: %v", err)
+	}
+	if _, err := usecase.AcceptInvitation(domain.Actor{UserID: sam.ID, WorkspaceID: class.WorkspaceID}, share.ID); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/models"
+)
+
+// calendarWorkflow is the workflow of calendar, the default one when it
+// has none of its own.
+func calendarWorkflow(calendar *models.Calendars) []models.WorkflowState {
+	if calendar.Workflow == nil {
+		return models.DefaultWorkflow
+	}
+	return calendar.Workflow
+}
+
+// restateEvents puts the events of a calendar whose workflow became
+// workflow in its states, the way the event usecase would: an event in a
+// state workflow still has stays there, complete when the state is
+// terminal, and the others go to the end of the column of the first state
+// agreeing with whether they are complete, in the order they were in. It
+// returns the events it changes, which publish their change unless trashed.
+func restateEvents(workflow []models.WorkflowState) func(events []*models.Events) []*models.Events {
+	return func(events []*models.Events) []*models.Events {
+		// The position past the last event staying in each column
+		next := make(map[string]int)
+		for _, event := range events {
+			if state, ok := findState(workflow, event.Status); ok && !event.DeleteAt.Valid {
+				next[state.Name] = max(next[state.Name], event.Position+1)
+			}
+		}
+
+		var changed []*models.Events
+		for _, event := range events {
+			state, ok := findState(workflow, event.Status)
+			if ok && state.Terminal == event.Complete {
+				continue
+			}
+			if !ok {
+				state = firstState(workflow, event.Complete)
+				event.Status = state.Name
+				event.Position = next[state.Name]
+				next[state.Name]++
+			}
+
+			wasComplete := event.Complete
+			if state.Terminal && !wasComplete {
+				now := time.Now()
+				event.CompletedAt = &now
+			}
+			if !state.Terminal {
+				event.CompletedAt = nil
+			}
+			event.Complete = state.Terminal
+			if !event.DeleteAt.Valid {
+				publishChange(event, wasComplete)
+			}
+			changed = append(changed, event)
+		}
+		return changed
+	}
+}
+
+// findState looks the state name up in workflow.
+func findState(workflow []models.WorkflowState, name string) (models.WorkflowState, bool) {
+	for _, state := range workflow {
+		if state.Name == name {
+			return state, true
+		}
+	}
+	return models.WorkflowState{}, false
+}
+
+// firstState is the first state of workflow events are complete in when
+// complete, the first one they aren't otherwise; workflows have both.
+func firstState(workflow []models.WorkflowState, complete bool) models.WorkflowState {
+	for _, state := range workflow {
+		if state.Terminal == complete {
+			return state
+		}
+	}
+	return workflow[0]
+}
+
+// publishChange has the write saving event publish the change to it, as
+// the event usecase does.
+func publishChange(event *models.Events, wasComplete bool) {
+	change := constant.WebhookEventUpdated
+	if event.Complete && !wasComplete {
+		change = constant.WebhookEventCompleted
+	}
+	event.Changes = append(event.Changes, change)
+	event.CompletionChanged = event.Complete != wasComplete
+}
@@ -1,6 +1,7 @@
 package delivery
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/g12-todo-backend/constant"
 	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
 	"github.com/pubestpubest/g12-todo-backend/request"
 	"github.com/pubestpubest/g12-todo-backend/response"
 	"github.com/pubestpubest/g12-todo-backend/utils"
@@ -23,41 +25,94 @@ func NewEventHandler(eventUsecase domain.EventUsecase) *eventHandler {
 }
 
 func (h *eventHandler) GetEventList(c *gin.Context) {
-	var paginationReq request.PaginationRequest
+	var listReq request.EventListRequest
 
 	// Set default values
-	paginationReq.Page = 1
-	paginationReq.Limit = 10
+	listReq.Page = 1
+	listReq.Limit = 10
 
 	// Bind query parameters
-	if err := c.ShouldBindQuery(&paginationReq); err != nil {
+	if err := c.ShouldBindQuery(&listReq); err != nil {
 		err = errors.Wrap(err, "[EventHandler.GetEventList]: Error binding query parameters")
 		log.Warn(err)
 		resp := response.PaginatedResponse[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
 		c.JSON(http.StatusBadRequest, resp)
 		return
 	}
 
-	events, err := h.eventUsecase.GetEventList(paginationReq.Page, paginationReq.Limit)
+	events, err := h.eventUsecase.GetEventList(middlewares.GetActor(c), &listReq)
 	if err != nil {
 		err = errors.Wrap(err, "[EventHandler.GetEventList]: Error getting event list")
 		log.Error(err)
+		status, code := utils.HTTPError(err)
 		resp := response.PaginatedResponse[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
-		c.JSON(http.StatusInternalServerError, resp)
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.PaginatedResponse[*response.EventResponse]{
+		Status:           constant.Success,
+		Message:          "List events successfully",
+		Data:             events.Data,
+		Pagination:       events.Pagination,
+		CursorPagination: events.CursorPagination,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) SearchEvents(c *gin.Context) {
+	var searchReq request.EventSearchRequest
+
+	// Set default values
+	searchReq.Page = 1
+	searchReq.Limit = 10
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&searchReq); err != nil {
+		err = errors.Wrap(err, "[EventHandler.SearchEvents]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	events, err := h.eventUsecase.SearchEvents(middlewares.GetActor(c), &searchReq)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.SearchEvents]: Error searching events")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
 		return
 	}
 
 	resp := response.PaginatedResponse[*response.EventResponse]{
 		Status:     constant.Success,
-		Message:    "List events successfully",
+		Message:    "Search events successfully",
 		Data:       events.Data,
 		Pagination: events.Pagination,
 	}
@@ -73,22 +128,34 @@ func (h *eventHandler) GetEventByID(c *gin.Context) {
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
 		c.JSON(http.StatusBadRequest, resp)
 		return
 	}
 
-	event, err := h.eventUsecase.GetEventByID(id)
+	event, err := h.eventUsecase.GetEventByID(middlewares.GetActor(c), id)
 	if err != nil {
 		err = errors.Wrap(err, "[EventHandler.GetEventByID]: Error getting event")
 		log.Error(err)
+		status, code := utils.HTTPError(err)
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
-		c.JSON(http.StatusInternalServerError, resp)
+		c.JSON(status, resp)
+		return
+	}
+
+	etag := utils.ETag(event.Version)
+	c.Header("ETag", etag)
+	if utils.IfNoneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
@@ -108,25 +175,31 @@ func (h *eventHandler) CreateEvent(c *gin.Context) {
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
 		c.JSON(http.StatusBadRequest, resp)
 		return
 	}
 
-	event, err := h.eventUsecase.CreateEvent(&req)
+	event, err := h.eventUsecase.CreateEvent(middlewares.GetActor(c), &req)
 	if err != nil {
 		err = errors.Wrap(err, "[EventHandler.CreateEvent]: Error creating event")
 		log.Error(err)
+		status, code := utils.HTTPError(err)
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
-		c.JSON(http.StatusInternalServerError, resp)
+		c.JSON(status, resp)
 		return
 	}
 
+	c.Header("ETag", utils.ETag(event.Version))
 	resp := response.Response[*response.EventResponse]{
 		Status:  constant.Success,
 		Message: "Event created successfully",
@@ -144,12 +217,28 @@ func (h *eventHandler) UpdateEvent(c *gin.Context) {
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
 		c.JSON(http.StatusBadRequest, resp)
 		return
 	}
 
+	version, status, code, err := ifMatchVersion(c)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.UpdateEvent]: Error reading If-Match header")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
 	var req request.EventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		err = errors.Wrap(err, "[EventHandler.UpdateEvent]: Error binding request body")
@@ -157,25 +246,137 @@ func (h *eventHandler) UpdateEvent(c *gin.Context) {
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
 		c.JSON(http.StatusBadRequest, resp)
 		return
 	}
 
-	event, err := h.eventUsecase.UpdateEvent(id, &req)
+	var occurrenceReq request.OccurrenceRequest
+	if err := c.ShouldBindQuery(&occurrenceReq); err != nil {
+		err = errors.Wrap(err, "[EventHandler.UpdateEvent]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var event *response.EventResponse
+	if occurrenceReq.Scope == "" || occurrenceReq.Scope == "all" {
+		event, err = h.eventUsecase.UpdateEvent(middlewares.GetActor(c), id, version, &req)
+	} else {
+		event, err = h.eventUsecase.UpdateOccurrence(middlewares.GetActor(c), id, version, &occurrenceReq, &req)
+	}
 	if err != nil {
 		err = errors.Wrap(err, "[EventHandler.UpdateEvent]: Error updating event")
 		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	c.Header("ETag", utils.ETag(event.Version))
+	resp := response.Response[*response.EventResponse]{
+		Status:  constant.Success,
+		Message: "Event updated successfully",
+		Data:    event,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) PatchEvent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.PatchEvent]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	version, status, code, err := ifMatchVersion(c)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.PatchEvent]: Error reading If-Match header")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType != constant.MergePatchContentType && contentType != constant.JSONPatchContentType {
+		err := errors.Errorf("[EventHandler.PatchEvent]: Content-Type must be %s or %s",
+			constant.MergePatchContentType, constant.JSONPatchContentType)
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeUnsupportedMediaType,
+			Data:    nil,
+		}
+		c.JSON(http.StatusUnsupportedMediaType, resp)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.PatchEvent]: Error reading request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	event, err := h.eventUsecase.PatchEvent(middlewares.GetActor(c), id, version, contentType, patch)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.PatchEvent]: Error patching event")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
-		c.JSON(http.StatusInternalServerError, resp)
+		c.JSON(status, resp)
 		return
 	}
 
+	c.Header("ETag", utils.ETag(event.Version))
 	resp := response.Response[*response.EventResponse]{
 		Status:  constant.Success,
 		Message: "Event updated successfully",
@@ -193,21 +394,77 @@ func (h *eventHandler) DeleteEvent(c *gin.Context) {
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	version, status, code, err := ifMatchVersion(c)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.DeleteEvent]: Error reading If-Match header")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	var deleteReq request.EventDeleteRequest
+	if err := c.ShouldBindQuery(&deleteReq); err != nil {
+		err = errors.Wrap(err, "[EventHandler.DeleteEvent]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	wholeEvent := deleteReq.Scope == "" || deleteReq.Scope == "all"
+	if deleteReq.Permanent && !wholeEvent {
+		err := errors.New("[EventHandler.DeleteEvent]: permanent delete applies to whole events, scope must be all")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
 			Data:    nil,
 		}
 		c.JSON(http.StatusBadRequest, resp)
 		return
 	}
 
-	if err := h.eventUsecase.DeleteEvent(id); err != nil {
+	switch {
+	case deleteReq.Permanent:
+		err = h.eventUsecase.PurgeEvent(middlewares.GetActor(c), id, version)
+	case wholeEvent:
+		err = h.eventUsecase.DeleteEvent(middlewares.GetActor(c), id, version)
+	default:
+		err = h.eventUsecase.DeleteOccurrence(middlewares.GetActor(c), id, version, &deleteReq.OccurrenceRequest)
+	}
+	if err != nil {
 		err = errors.Wrap(err, "[EventHandler.DeleteEvent]: Error deleting event")
 		log.Error(err)
+		status, code := utils.HTTPError(err)
 		resp := response.Response[interface{}]{
 			Status:  constant.Failed,
 			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
 			Data:    nil,
 		}
-		c.JSON(http.StatusInternalServerError, resp)
+		c.JSON(status, resp)
 		return
 	}
 
@@ -218,3 +475,568 @@ func (h *eventHandler) DeleteEvent(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *eventHandler) GetDeletedEventList(c *gin.Context) {
+	var pageReq request.PaginationRequest
+
+	// Set default values
+	pageReq.Page = 1
+	pageReq.Limit = 10
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&pageReq); err != nil {
+		err = errors.Wrap(err, "[EventHandler.GetDeletedEventList]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	events, err := h.eventUsecase.GetDeletedEventList(middlewares.GetActor(c), &pageReq)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.GetDeletedEventList]: Error getting deleted event list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.PaginatedResponse[*response.EventResponse]{
+		Status:     constant.Success,
+		Message:    "List deleted events successfully",
+		Data:       events.Data,
+		Pagination: events.Pagination,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) RestoreEvent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.RestoreEvent]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	event, err := h.eventUsecase.RestoreEvent(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.RestoreEvent]: Error restoring event")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	c.Header("ETag", utils.ETag(event.Version))
+	resp := response.Response[*response.EventResponse]{
+		Status:  constant.Success,
+		Message: "Event restored successfully",
+		Data:    event,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) ExportEvents(c *gin.Context) {
+	var listReq request.EventListRequest
+
+	// Set default values, the export itself is not paginated
+	listReq.Page = 1
+	listReq.Limit = 10
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&listReq); err != nil {
+		err = errors.Wrap(err, "[EventHandler.ExportEvents]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	export, err := h.eventUsecase.ExportEvents(middlewares.GetActor(c), &listReq)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.ExportEvents]: Error exporting events")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	if export.LastModified != nil {
+		c.Header("Last-Modified", export.LastModified.UTC().Format(http.TimeFormat))
+	}
+	c.Header("Content-Disposition", `attachment; filename="events.ics"`)
+	c.Data(http.StatusOK, constant.ICalendarContentType+"; charset=utf-8", export.Calendar)
+}
+
+// ImportEvents accepts an .ics file either as the "file" field of a
+// multipart form or as a text/calendar body.
+func (h *eventHandler) ImportEvents(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constant.MaxImportSize)
+
+	var calendar io.Reader
+	switch c.ContentType() {
+	case gin.MIMEMultipartPOSTForm:
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			err = errors.Wrap(err, "[EventHandler.ImportEvents]: Error reading file")
+			log.Warn(err)
+			resp := response.Response[interface{}]{
+				Status:  constant.Failed,
+				Message: utils.StandardError(err),
+				Code:    constant.CodeBadRequest,
+				Data:    nil,
+			}
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			err = errors.Wrap(err, "[EventHandler.ImportEvents]: Error opening file")
+			log.Error(err)
+			resp := response.Response[interface{}]{
+				Status:  constant.Failed,
+				Message: utils.StandardError(err),
+				Code:    constant.CodeInternal,
+				Data:    nil,
+			}
+			c.JSON(http.StatusInternalServerError, resp)
+			return
+		}
+		defer file.Close()
+		calendar = file
+	case constant.ICalendarContentType:
+		calendar = c.Request.Body
+	default:
+		err := errors.Errorf("[EventHandler.ImportEvents]: Content-Type must be %s or %s",
+			gin.MIMEMultipartPOSTForm, constant.ICalendarContentType)
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeUnsupportedMediaType,
+			Data:    nil,
+		}
+		c.JSON(http.StatusUnsupportedMediaType, resp)
+		return
+	}
+
+	result, err := h.eventUsecase.ImportEvents(middlewares.GetActor(c), calendar)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.ImportEvents]: Error importing events")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.EventImportResponse]{
+		Status:  constant.Success,
+		Message: "Import events successfully",
+		Data:    result,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) GetEventItemList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.GetEventItemList]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	items, err := h.eventUsecase.GetEventItemList(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.GetEventItemList]: Error getting items")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.EventItemResponse]{
+		Status:  constant.Success,
+		Message: "Items retrieved successfully",
+		Data:    items,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) CreateEventItem(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.CreateEventItem]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.EventItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[EventHandler.CreateEventItem]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	item, err := h.eventUsecase.CreateEventItem(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.CreateEventItem]: Error creating item")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.EventItemResponse]{
+		Status:  constant.Success,
+		Message: "Item created successfully",
+		Data:    item,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *eventHandler) UpdateEventItem(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.UpdateEventItem]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	itemIDStr := c.Param("itemId")
+	itemID, err := strconv.ParseUint(itemIDStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.UpdateEventItem]: Error parsing item ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.EventItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[EventHandler.UpdateEventItem]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	item, err := h.eventUsecase.UpdateEventItem(middlewares.GetActor(c), id, itemID, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.UpdateEventItem]: Error updating item")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.EventItemResponse]{
+		Status:  constant.Success,
+		Message: "Item updated successfully",
+		Data:    item,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) DeleteEventItem(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.DeleteEventItem]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	itemIDStr := c.Param("itemId")
+	itemID, err := strconv.ParseUint(itemIDStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.DeleteEventItem]: Error parsing item ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = h.eventUsecase.DeleteEventItem(middlewares.GetActor(c), id, itemID)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.DeleteEventItem]: Error deleting item")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Item deleted successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) GetEventGraph(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.GetEventGraph]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	graph, err := h.eventUsecase.GetEventGraph(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.GetEventGraph]: Error getting event graph")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.EventGraphResponse]{
+		Status:  constant.Success,
+		Message: "Event graph retrieved successfully",
+		Data:    graph,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *eventHandler) MoveEvent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.MoveEvent]: Error parsing event ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	version, status, code, err := ifMatchVersion(c)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.MoveEvent]: Error reading If-Match header")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	var req request.EventMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[EventHandler.MoveEvent]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	event, err := h.eventUsecase.MoveEvent(middlewares.GetActor(c), id, version, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.MoveEvent]: Error moving event")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	c.Header("ETag", utils.ETag(event.Version))
+	resp := response.Response[*response.EventResponse]{
+		Status:  constant.Success,
+		Message: "Event moved successfully",
+		Data:    event,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ifMatchVersion reads the event version a write is conditioned on, along
+// with the status and error code to reply with when the header is missing or
+// malformed. Writes without If-Match are refused so concurrent edits can't
+// silently overwrite each other.
+func ifMatchVersion(c *gin.Context) (version uint64, status int, code string, err error) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return 0, http.StatusPreconditionRequired, constant.CodePreconditionRequired, errors.New("If-Match header is required")
+	}
+
+	version, err = utils.ParseIfMatch(header)
+	if err != nil {
+		return 0, http.StatusBadRequest, constant.CodeBadRequest, err
+	}
+	return version, 0, "", nil
+}
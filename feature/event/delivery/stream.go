@@ -0,0 +1,137 @@
+package delivery
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// StreamEvents sends the changes to events as Server-Sent Events: the id is
+// the change's, to resume with, the event name its type and the data the
+// event.
+func (h *eventHandler) StreamEvents(c *gin.Context) {
+	after, err := streamCursor(c)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.StreamEvents]: Error reading cursor")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keeps proxies such as nginx from holding changes back
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	err = h.eventUsecase.StreamEvents(c.Request.Context(), middlewares.GetActor(c), after, func(changes []*response.EventChangeResponse) error {
+		if len(changes) == 0 {
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return err
+			}
+		}
+		for _, change := range changes {
+			event := sse.Event{Id: strconv.FormatUint(change.ID, 10), Event: change.Type, Data: change.Event}
+			if err := sse.Encode(c.Writer, event); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		log.Warn(errors.Wrap(err, "[EventHandler.StreamEvents]: Error streaming events"))
+	}
+}
+
+// StreamEventsWebSocket sends the changes to events over a WebSocket, a
+// response.EventChangeResponse per text message, resuming like
+// StreamEvents. What the client sends is ignored.
+func (h *eventHandler) StreamEventsWebSocket(c *gin.Context) {
+	after, err := streamCursor(c)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.StreamEventsWebSocket]: Error reading cursor")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	actor := middlewares.GetActor(c)
+	// Origins aren't checked: credentials are never ambient, so a page of
+	// another origin can't stream someone else's events
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+		// Reading is how a closed connection is noticed
+		go func() {
+			io.Copy(io.Discard, conn)
+			cancel()
+		}()
+
+		err := h.eventUsecase.StreamEvents(ctx, actor, after, func(changes []*response.EventChangeResponse) error {
+			if len(changes) == 0 {
+				conn.PayloadType = websocket.PingFrame
+				_, err := conn.Write(nil)
+				conn.PayloadType = websocket.TextFrame
+				return err
+			}
+			for _, change := range changes {
+				if err := websocket.JSON.Send(conn, change); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Warn(errors.Wrap(err, "[EventHandler.StreamEventsWebSocket]: Error streaming events"))
+		}
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// streamCursor is the change a stream resumes after, from the Last-Event-ID
+// header or else the lastEventId query parameter; nil to start from now.
+func streamCursor(c *gin.Context) (*uint64, error) {
+	var req request.EventStreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+
+	if header := strings.TrimSpace(c.GetHeader(constant.HeaderLastEventID)); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s must be the id of a change", constant.HeaderLastEventID)
+		}
+		req.LastEventID = &id
+	}
+	return req.LastEventID, nil
+}
@@ -0,0 +1,101 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+func (h *eventHandler) SyncEvents(c *gin.Context) {
+	var req request.SyncRequest
+
+	// Set default values
+	req.Limit = constant.SyncBatchSize
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		err = errors.Wrap(err, "[EventHandler.SyncEvents]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	changes, err := h.eventUsecase.SyncEvents(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.SyncEvents]: Error syncing events")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.SyncResponse]{
+		Status:  constant.Success,
+		Message: "Sync events successfully",
+		Data:    changes,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ApplySyncMutations applies a batch of offline writes. The batch succeeds
+// as a whole even when some of its mutations conflict or fail; their
+// results tell.
+func (h *eventHandler) ApplySyncMutations(c *gin.Context) {
+	var req request.SyncMutationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[EventHandler.ApplySyncMutations]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	result, err := h.eventUsecase.ApplySyncMutations(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[EventHandler.ApplySyncMutations]: Error applying mutations")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.SyncMutationsResponse]{
+		Status:  constant.Success,
+		Message: "Mutations applied successfully",
+		Data:    result,
+	}
+	c.JSON(http.StatusOK, resp)
+}
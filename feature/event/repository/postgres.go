@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
 	"github.com/pubestpubest/g12-todo-backend/domain"
 	"github.com/pubestpubest/g12-todo-backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type eventRepository struct {
@@ -17,56 +22,1175 @@ func NewEventRepository(db *gorm.DB) domain.EventRepository {
 	return &eventRepository{db: db}
 }
 
-func (r *eventRepository) GetEventList(page, limit int) ([]*models.Events, int64, error) {
+// scopeEvents limits query to the events of actor's workspace that actor
+// owns or that are in a calendar shared with them.
+func scopeEvents(query *gorm.DB, actor domain.Actor) *gorm.DB {
+	query = database.WithWorkspace(query, actor.WorkspaceID)
+	return query.Where("(events.owner_id = ? OR events.calendar_id IN (?))", actor.UserID, sharedCalendars(query, actor))
+}
+
+// sharedCalendars selects the IDs of the calendars shared with actor.
+func sharedCalendars(query *gorm.DB, actor domain.Actor) *gorm.DB {
+	return query.Session(&gorm.Session{NewDB: true}).Model(&models.CalendarShares{}).
+		Select("calendar_id").
+		Where("user_id = ? AND status = ?", actor.UserID, constant.CalendarShareAccepted)
+}
+
+// scopeOwnedEvents limits query to the events of actor's workspace that
+// actor owns.
+func scopeOwnedEvents(query *gorm.DB, actor domain.Actor) *gorm.DB {
+	return database.WithWorkspace(query, actor.WorkspaceID).Where("events.owner_id = ?", actor.UserID)
+}
+
+func (r *eventRepository) GetEventList(actor domain.Actor, filter *domain.EventFilter) ([]*models.Events, int64, error) {
 	var events []*models.Events
 	var total int64
 
+	query := applyEventFilter(scopeEvents(r.db.Model(&models.Events{}), actor), filter)
+
+	if filter.Expand {
+		if err := applyEventSort(query, filter.Sort).Scopes(preloadEvent).Find(&events).Error; err != nil {
+			return nil, 0, errors.Wrap(err, "[EventRepository.GetEventList]: Error getting event list")
+		}
+		return events, int64(len(events)), nil
+	}
+
 	// Get total count
-	if err := r.db.Model(&models.Events{}).Count(&total).Error; err != nil {
-		return nil, 0, errors.Wrap(err, "[EventRepository.GetEventList]: Error counting events")
+	if !filter.Cursor || filter.WithTotal {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, errors.Wrap(err, "[EventRepository.GetEventList]: Error counting events")
+		}
+	}
+
+	if filter.Cursor {
+		if filter.Seek != nil {
+			query = applyEventSeek(query, filter.Sort, filter.Seek)
+		} else {
+			query = applyEventSort(query, filter.Sort)
+		}
+		if err := query.Limit(filter.Limit + 1).Scopes(preloadEvent).Find(&events).Error; err != nil {
+			return nil, 0, errors.Wrap(err, "[EventRepository.GetEventList]: Error getting event list")
+		}
+		return events, total, nil
 	}
 
 	// Get paginated results
-	offset := (page - 1) * limit
-	if err := r.db.Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+	offset := (filter.Page - 1) * filter.Limit
+	if err := applyEventSort(query, filter.Sort).Offset(offset).Limit(filter.Limit).Scopes(preloadEvent).Find(&events).Error; err != nil {
 		return nil, 0, errors.Wrap(err, "[EventRepository.GetEventList]: Error getting event list")
 	}
 
 	return events, total, nil
 }
 
-func (r *eventRepository) GetEventByID(id uint64) (*models.Events, error) {
+func (r *eventRepository) SearchEvents(actor domain.Actor, filter *domain.EventFilter) ([]*models.EventSearchResult, int64, error) {
+	var results []*models.EventSearchResult
+	var total int64
+
+	query := applyEventFilter(scopeEvents(r.db.Model(&models.Events{}), actor), filter).
+		Where("search_vector @@ websearch_to_tsquery('english', ?)", filter.Query)
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.SearchEvents]: Error counting events")
+	}
+
+	// Get ranked results
+	offset := (filter.Page - 1) * filter.Limit
+	query = query.Select(
+		"events.*, "+
+			"ts_rank_cd(search_vector, websearch_to_tsquery('english', ?)) AS rank, "+
+			"ts_headline('english', concat_ws(' ', title, location, description), websearch_to_tsquery('english', ?), "+
+			"'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS snippet",
+		filter.Query, filter.Query,
+	).Order("rank DESC")
+	if err := applyEventSort(query, filter.Sort).Offset(offset).Limit(filter.Limit).Scan(&results).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.SearchEvents]: Error searching events")
+	}
+
+	// Scan doesn't preload
+	events := make([]*models.Events, 0, len(results))
+	for _, result := range results {
+		events = append(events, &result.Events)
+	}
+	if err := loadEventTags(database.WithWorkspace(r.db, actor.WorkspaceID), events); err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.SearchEvents]: Error getting event tags")
+	}
+	if err := loadEventItems(database.WithWorkspace(r.db, actor.WorkspaceID), events); err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.SearchEvents]: Error getting event items")
+	}
+	if err := loadEventDependencies(database.WithWorkspace(r.db, actor.WorkspaceID), events); err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.SearchEvents]: Error getting event dependencies")
+	}
+	if err := loadEventReminders(database.WithWorkspace(r.db, actor.WorkspaceID), events); err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.SearchEvents]: Error getting event reminders")
+	}
+
+	return results, total, nil
+}
+
+// preloadEvent preloads what an event is shown with.
+func preloadEvent(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags", orderTags).
+		Preload("Items", orderItems).
+		Preload("BlockedBy", selectDependencies).
+		Preload("Blocks", selectDependencies).
+		Preload("Reminders", orderReminders)
+}
+
+// orderTags preloads tags by name.
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name")
+}
+
+// loadEventTags sets the tags of events the way preloading them would.
+func loadEventTags(db *gorm.DB, events []*models.Events) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint64]*models.Events, len(events))
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		event.Tags = nil
+		byID[event.ID] = event
+		ids = append(ids, event.ID)
+	}
+
+	var rows []struct {
+		EventID uint64
+		models.Tags
+	}
+	if err := db.Table("tags").Select("event_tags.event_id, tags.*").
+		Joins("JOIN event_tags ON event_tags.tag_id = tags.id").
+		Where("event_tags.event_id IN ?", ids).
+		Order("tags.name").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	for i := range rows {
+		event := byID[rows[i].EventID]
+		event.Tags = append(event.Tags, &rows[i].Tags)
+	}
+	return nil
+}
+
+// orderItems preloads checklist items by position.
+func orderItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position").Order("id")
+}
+
+// loadEventItems sets the checklist items of events the way preloading them
+// would.
+func loadEventItems(db *gorm.DB, events []*models.Events) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint64]*models.Events, len(events))
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		event.Items = nil
+		byID[event.ID] = event
+		ids = append(ids, event.ID)
+	}
+
+	var items []*models.EventItems
+	if err := orderItems(db.Where("event_id IN ?", ids)).Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		event := byID[item.EventID]
+		event.Items = append(event.Items, item)
+	}
+	return nil
+}
+
+// selectDependencies preloads the IDs and completion of events blocking or
+// blocked by others.
+func selectDependencies(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "complete").Order("id")
+}
+
+// loadEventDependencies sets the blockers and dependents of events the way
+// preloading them would.
+func loadEventDependencies(db *gorm.DB, events []*models.Events) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint64]*models.Events, len(events))
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		event.BlockedBy = nil
+		event.Blocks = nil
+		byID[event.ID] = event
+		ids = append(ids, event.ID)
+	}
+
+	var rows []struct {
+		models.EventDependencies
+		Complete bool
+	}
+	if err := db.Model(&models.Events{}).Select("event_dependencies.*, events.complete").
+		Joins("JOIN event_dependencies ON event_dependencies.blocker_id = events.id").
+		Where("event_dependencies.event_id IN ?", ids).
+		Order("events.id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		event := byID[row.EventID]
+		event.BlockedBy = append(event.BlockedBy, &models.Events{ID: row.BlockerID, Complete: row.Complete})
+	}
+
+	rows = nil
+	if err := db.Model(&models.Events{}).Select("event_dependencies.*, events.complete").
+		Joins("JOIN event_dependencies ON event_dependencies.event_id = events.id").
+		Where("event_dependencies.blocker_id IN ?", ids).
+		Order("events.id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		event := byID[row.BlockerID]
+		event.Blocks = append(event.Blocks, &models.Events{ID: row.EventID, Complete: row.Complete})
+	}
+	return nil
+}
+
+// saveEventDependencies replaces the blockers of event with
+// event.BlockedBy.
+func saveEventDependencies(tx *gorm.DB, event *models.Events) error {
+	if err := tx.Where("event_id = ?", event.ID).Delete(&models.EventDependencies{}).Error; err != nil {
+		return err
+	}
+	if len(event.BlockedBy) == 0 {
+		return nil
+	}
+
+	links := make([]*models.EventDependencies, 0, len(event.BlockedBy))
+	for _, blocker := range event.BlockedBy {
+		links = append(links, &models.EventDependencies{EventID: event.ID, BlockerID: blocker.ID})
+	}
+	return tx.Create(&links).Error
+}
+
+func (r *eventRepository) GetEventDependencies(actor domain.Actor, ids []uint64) ([]*models.EventDependencies, error) {
+	var dependencies []*models.EventDependencies
+	if len(ids) == 0 {
+		return dependencies, nil
+	}
+
+	// Dependencies only join events of one workspace, so ids of another
+	// one find none
+	inWorkspace := database.WithWorkspace(r.db.Unscoped(), actor.WorkspaceID).Model(&models.Events{}).Select("id").Where("id IN ?", ids)
+	if err := r.db.Where("event_id IN (?) OR blocker_id IN (?)", inWorkspace, inWorkspace).
+		Order("event_id").Order("blocker_id").
+		Find(&dependencies).Error; err != nil {
+		return nil, errors.Wrap(err, "[EventRepository.GetEventDependencies]: Error getting event dependencies")
+	}
+	return dependencies, nil
+}
+
+// orderReminders preloads reminders by how long before the event they are.
+func orderReminders(db *gorm.DB) *gorm.DB {
+	return db.Order("minutes_before")
+}
+
+// loadEventReminders sets the reminders of events the way preloading them
+// would.
+func loadEventReminders(db *gorm.DB, events []*models.Events) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint64]*models.Events, len(events))
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		event.Reminders = nil
+		byID[event.ID] = event
+		ids = append(ids, event.ID)
+	}
+
+	var reminders []*models.Reminders
+	if err := orderReminders(db.Where("event_id IN ?", ids)).Find(&reminders).Error; err != nil {
+		return err
+	}
+	for _, reminder := range reminders {
+		event := byID[reminder.EventID]
+		event.Reminders = append(event.Reminders, reminder)
+	}
+	return nil
+}
+
+// saveEventReminders replaces the reminders of event with event.Reminders,
+// keeping the rows of the ones it already had.
+func saveEventReminders(tx *gorm.DB, event *models.Events) error {
+	var kept []uint64
+	for _, reminder := range event.Reminders {
+		if reminder.ID != 0 {
+			kept = append(kept, reminder.ID)
+		}
+	}
+	query := tx.Where("event_id = ?", event.ID)
+	if len(kept) > 0 {
+		query = query.Where("id NOT IN ?", kept)
+	}
+	if err := query.Delete(&models.Reminders{}).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, reminder := range event.Reminders {
+		reminder.EventID = event.ID
+		reminder.UpdatedAt = &now
+		if reminder.ID == 0 {
+			reminder.CreatedAt = &now
+			if err := tx.Create(reminder).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Model(reminder).Where("event_id = ?", event.ID).
+			Select("remind_at", "attempts", "updated_at").
+			Updates(reminder).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveEventTags replaces the tags of event with event.Tags, matched by name
+// among the tags of its owner and created where they have none, and loads
+// the stored ones back onto it.
+func saveEventTags(tx *gorm.DB, event *models.Events) error {
+	if err := tx.Where("event_id = ?", event.ID).Delete(&models.EventTags{}).Error; err != nil {
+		return err
+	}
+	if len(event.Tags) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	names := make([]string, 0, len(event.Tags))
+	tags := make([]*models.Tags, 0, len(event.Tags))
+	for _, tag := range event.Tags {
+		names = append(names, tag.Name)
+		tags = append(tags, &models.Tags{
+			WorkspaceID: event.WorkspaceID,
+			OwnerID:     event.OwnerID,
+			Name:        tag.Name,
+			CreatedAt:   &now,
+			UpdatedAt:   &now,
+		})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return err
+	}
+
+	var stored []*models.Tags
+	if err := tx.Where("owner_id = ? AND name IN ?", event.OwnerID, names).Order("name").Find(&stored).Error; err != nil {
+		return err
+	}
+	event.Tags = stored
+	if len(stored) == 0 {
+		return nil
+	}
+	links := make([]*models.EventTags, 0, len(stored))
+	for _, tag := range stored {
+		links = append(links, &models.EventTags{EventID: event.ID, TagID: tag.ID})
+	}
+	return tx.Create(&links).Error
+}
+
+func (r *eventRepository) GetEventExceptions(actor domain.Actor, eventIDs []uint64) ([]*models.EventExceptions, error) {
+	var exceptions []*models.EventExceptions
+	if len(eventIDs) == 0 {
+		return exceptions, nil
+	}
+
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Where("event_id IN ?", eventIDs).Find(&exceptions).Error; err != nil {
+		return nil, errors.Wrap(err, "[EventRepository.GetEventExceptions]: Error getting event exceptions")
+	}
+	return exceptions, nil
+}
+
+func (r *eventRepository) SaveEventException(event *models.Events, exception *models.EventExceptions) error {
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		if err := updateEvent(tx, event); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		exception.EventID = event.ID
+		exception.CreatedAt = &now
+		exception.UpdatedAt = &now
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}, {Name: "occurrence_start"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "description", "location", "start_time", "end_time", "complete", "updated_at"}),
+		}).Create(exception).Error; err != nil {
+			return err
+		}
+		return database.SaveEventChanges(tx, event)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.SaveEventException]: Error saving event exception")
+	}
+	return nil
+}
+
+func (r *eventRepository) SplitEventSeries(event *models.Events, next *models.Events) error {
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		if err := updateEvent(tx, event); err != nil {
+			return err
+		}
+
+		if event.RecurrenceEnd != nil {
+			// updateEvent already checked that the series is event.OwnerID's
+			if err := tx.Where("event_id = ? AND occurrence_start > ?", event.ID, *event.RecurrenceEnd).
+				Delete(&models.EventExceptions{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			return database.SaveEventChanges(tx, event)
+		}
+		now := time.Now()
+		next.CreatedAt = &now
+		next.UpdatedAt = &now
+		next.Version = 1
+		if err := tx.Omit(clause.Associations).Create(next).Error; err != nil {
+			return err
+		}
+		if err := saveEventTags(tx, next); err != nil {
+			return err
+		}
+		if err := saveEventDependencies(tx, next); err != nil {
+			return err
+		}
+		if err := saveEventReminders(tx, next); err != nil {
+			return err
+		}
+		return database.SaveEventChanges(tx, event, next)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.SplitEventSeries]: Error splitting event series")
+	}
+	return nil
+}
+
+func applyEventFilter(query *gorm.DB, filter *domain.EventFilter) *gorm.DB {
+	// Occurrences cannot leave their calendar or series tags, so these hold
+	// for series too
+	if filter.CalendarID != nil {
+		query = query.Where("calendar_id = ?", *filter.CalendarID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if len(filter.Tags) > 0 {
+		// Built on tags rather than written out, so it is scoped to the
+		// workspace like the query
+		tagged := query.Session(&gorm.Session{NewDB: true}).Model(&models.Tags{}).
+			Select("event_tags.event_id").
+			Joins("JOIN event_tags ON event_tags.tag_id = tags.id").
+			Where("tags.name IN ?", filter.Tags)
+		if filter.AllTags {
+			tagged = tagged.Group("event_tags.event_id").Having("COUNT(DISTINCT tags.name) = ?", len(filter.Tags))
+		}
+		query = query.Where("events.id IN (?)", tagged)
+	}
+
+	var conditions []string
+	var args []interface{}
+	if filter.Complete != nil {
+		conditions = append(conditions, "complete = ?")
+		args = append(args, *filter.Complete)
+	}
+	if filter.Location != "" {
+		conditions = append(conditions, "location ILIKE ?")
+		args = append(args, "%"+filter.Location+"%")
+	}
+	if len(conditions) > 0 {
+		attributes := strings.Join(conditions, " AND ")
+		if filter.Expand {
+			// Occurrences may override these, so they are checked after expansion
+			attributes = "rrule IS NOT NULL OR (" + attributes + ")"
+		}
+		query = query.Where("("+attributes+")", args...)
+	}
+
+	// An event matches a window when it overlaps it at all; a recurring one
+	// when its series does. Its last occurrence ends at recurrence_end plus
+	// the event's duration. Tasks without a window are placed at their due
+	// date, and those without one either are in no window.
+	if filter.From != nil {
+		query = query.Where("((rrule IS NULL AND COALESCE(end_time, due_at) > ?) OR "+
+			"(rrule IS NOT NULL AND (recurrence_end IS NULL OR recurrence_end + (end_time - start_time) > ?)))",
+			*filter.From, *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("COALESCE(start_time, due_at) < ?", *filter.To)
+	}
+	return query
+}
+
+func applyEventSort(query *gorm.DB, sort []domain.SortField) *gorm.DB {
+	for _, field := range sort {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: field.Desc})
+	}
+	// Tie-break on id so pages stay stable
+	return query.Order("id")
+}
+
+// applyEventSeek limits query to the events past seek in the order sort
+// and then id give them, or before it when seek.Backward, and orders them
+// from seek on. Postgres puts NULLs last going up and first going down, so
+// reading backward is the exact reverse.
+func applyEventSeek(query *gorm.DB, sort []domain.SortField, seek *domain.EventSeek) *gorm.DB {
+	// Past seek is past it on a column and level with it on the ones before
+	var past, level []string
+	var pastArgs, levelArgs []interface{}
+	for i, field := range sort {
+		column := "events." + field.Column
+		value := seek.Values[i]
+		desc := field.Desc != seek.Backward
+
+		var beyond string
+		switch {
+		case value == nil && desc:
+			beyond = column + " IS NOT NULL"
+		case value == nil:
+			// Nothing comes after NULLs
+		case desc:
+			beyond = column + " < ?"
+		default:
+			beyond = "(" + column + " > ? OR " + column + " IS NULL)"
+		}
+		if beyond != "" {
+			past = append(past, "("+strings.Join(append(slices.Clone(level), beyond), " AND ")+")")
+			pastArgs = append(pastArgs, levelArgs...)
+			if value != nil {
+				pastArgs = append(pastArgs, value)
+			}
+		}
+
+		if value == nil {
+			level = append(level, column+" IS NULL")
+		} else {
+			level = append(level, column+" = ?")
+			levelArgs = append(levelArgs, value)
+		}
+
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: desc})
+	}
+
+	beyond := "events.id >"
+	if seek.Backward {
+		beyond = "events.id <"
+	}
+	if seek.Inclusive {
+		beyond += "="
+	}
+	beyond += " ?"
+	past = append(past, "("+strings.Join(append(level, beyond), " AND ")+")")
+	pastArgs = append(append(pastArgs, levelArgs...), seek.ID)
+
+	return query.Where("("+strings.Join(past, " OR ")+")", pastArgs...).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: seek.Backward})
+}
+
+func (r *eventRepository) GetEventByID(actor domain.Actor, id uint64) (*models.Events, error) {
 	var event models.Events
-	if err := r.db.Where("id = ?", id).First(&event).Error; err != nil {
+	if err := scopeEvents(r.db, actor).Where("id = ?", id).Scopes(preloadEvent).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrEventNotFound, "[EventRepository.GetEventByID]: Error getting event")
+		}
 		return nil, errors.Wrap(err, "[EventRepository.GetEventByID]: Error getting event")
 	}
 	return &event, nil
 }
 
+func (r *eventRepository) GetEventByUID(actor domain.Actor, uid string) (*models.Events, error) {
+	var event models.Events
+	if err := scopeOwnedEvents(r.db, actor).Where("uid = ?", uid).Scopes(preloadEvent).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrEventNotFound, "[EventRepository.GetEventByUID]: Error getting event")
+		}
+		return nil, errors.Wrap(err, "[EventRepository.GetEventByUID]: Error getting event")
+	}
+	return &event, nil
+}
+
+func (r *eventRepository) GetEventsByIDs(actor domain.Actor, ids []uint64) ([]*models.Events, error) {
+	var events []*models.Events
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	if err := scopeEvents(r.db, actor).Where("id IN ?", ids).Order("id").Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "[EventRepository.GetEventsByIDs]: Error getting events")
+	}
+	return events, nil
+}
+
+func (r *eventRepository) GetChangedEvents(actor domain.Actor, ids []uint64) ([]*models.Events, error) {
+	var events []*models.Events
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	if err := scopeEvents(r.db.Unscoped(), actor).Where("id IN ?", ids).Order("id").Scopes(preloadEvent).Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "[EventRepository.GetChangedEvents]: Error getting events")
+	}
+	return events, nil
+}
+
+func (r *eventRepository) GetEventsAfter(actor domain.Actor, after uint64, limit int) ([]*models.Events, error) {
+	var events []*models.Events
+	if err := scopeEvents(r.db, actor).Where("id > ?", after).Order("id").Limit(limit).Scopes(preloadEvent).Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "[EventRepository.GetEventsAfter]: Error getting events")
+	}
+	return events, nil
+}
+
 func (r *eventRepository) CreateEvent(event *models.Events) error {
 	now := time.Now()
 	event.CreatedAt = &now
 	event.UpdatedAt = &now
+	event.Version = 1
 
-	if err := r.db.Create(event).Error; err != nil {
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(event).Error; err != nil {
+			return err
+		}
+		if err := saveEventTags(tx, event); err != nil {
+			return err
+		}
+		if err := saveEventDependencies(tx, event); err != nil {
+			return err
+		}
+		if err := saveEventReminders(tx, event); err != nil {
+			return err
+		}
+		return database.SaveEventChanges(tx, event)
+	})
+	if err != nil {
 		return errors.Wrap(err, "[EventRepository.CreateEvent]: Error creating event")
 	}
 	return nil
 }
 
 func (r *eventRepository) UpdateEvent(event *models.Events) error {
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		if err := updateEvent(tx, event); err != nil {
+			return err
+		}
+		if err := saveEventTags(tx, event); err != nil {
+			return err
+		}
+		if err := saveEventDependencies(tx, event); err != nil {
+			return err
+		}
+		if err := saveEventReminders(tx, event); err != nil {
+			return err
+		}
+		return database.SaveEventChanges(tx, event)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.UpdateEvent]: Error updating event")
+	}
+	return nil
+}
+
+// updateEvent saves every column of event as long as its version is still
+// the stored one and it still belongs to its owner, and bumps the version.
+// Events never change workspace. Tags are saved separately.
+func updateEvent(db *gorm.DB, event *models.Events) error {
 	now := time.Now()
 	event.UpdatedAt = &now
+	event.Version++
 
-	if err := r.db.Save(event).Error; err != nil {
-		return errors.Wrap(err, "[EventRepository.UpdateEvent]: Error updating event")
+	result := db.Model(event).
+		Where("owner_id = ? AND version = ?", event.OwnerID, event.Version-1).
+		Select("*").Omit("id", "owner_id", "workspace_id", "created_at", clause.Associations).
+		Updates(event)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrEventVersionMismatch
+	}
+	return nil
+}
+
+// PatchEvent writes only the given columns of event, plus updated_at and
+// version; the pseudo-columns "tags", "blocked_by" and "reminders" save its
+// tags, blockers and reminders.
+func (r *eventRepository) PatchEvent(event *models.Events, columns []string) error {
+	tags := slices.Contains(columns, "tags")
+	blockers := slices.Contains(columns, "blocked_by")
+	reminders := slices.Contains(columns, "reminders")
+	columns = slices.DeleteFunc(slices.Clone(columns), func(column string) bool {
+		return column == "tags" || column == "blocked_by" || column == "reminders"
+	})
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		if err := patchEvent(tx, event, columns); err != nil {
+			return err
+		}
+		if tags {
+			if err := saveEventTags(tx, event); err != nil {
+				return err
+			}
+		}
+		if blockers {
+			if err := saveEventDependencies(tx, event); err != nil {
+				return err
+			}
+		}
+		if reminders {
+			if err := saveEventReminders(tx, event); err != nil {
+				return err
+			}
+		}
+		return database.SaveEventChanges(tx, event)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.PatchEvent]: Error patching event")
+	}
+	return nil
+}
+
+// patchEvent is updateEvent for only the given columns of event.
+func patchEvent(db *gorm.DB, event *models.Events, columns []string) error {
+	now := time.Now()
+	event.UpdatedAt = &now
+	event.Version++
+
+	result := db.Model(event).
+		Where("owner_id = ? AND version = ?", event.OwnerID, event.Version-1).
+		Select(append(slices.Clip(columns), "updated_at", "version")).
+		Updates(event)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrEventVersionMismatch
+	}
+	return nil
+}
+
+func (r *eventRepository) SaveEventItems(event *models.Events, items, removed []*models.EventItems) error {
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		if err := patchEvent(tx, event, []string{"complete", "completed_at", "status", "position"}); err != nil {
+			return err
+		}
+
+		if len(removed) > 0 {
+			ids := make([]uint64, 0, len(removed))
+			for _, item := range removed {
+				ids = append(ids, item.ID)
+			}
+			if err := tx.Where("event_id = ? AND id IN ?", event.ID, ids).Delete(&models.EventItems{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, item := range items {
+			item.EventID = event.ID
+			item.UpdatedAt = event.UpdatedAt
+			if item.ID == 0 {
+				item.CreatedAt = event.UpdatedAt
+				if err := tx.Create(item).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(item).Where("event_id = ?", event.ID).
+				Select("text", "done", "position", "updated_at").
+				Updates(item).Error; err != nil {
+				return err
+			}
+		}
+		return database.SaveEventChanges(tx, event)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.SaveEventItems]: Error saving event items")
+	}
+	return nil
+}
+
+// scopeColumn limits query to the events in the workflow column of event:
+// those in its state in its calendar, or among its owner's events outside
+// calendars.
+func scopeColumn(query *gorm.DB, event *models.Events) *gorm.DB {
+	query = query.Where("status = ?", event.Status)
+	if event.CalendarID == nil {
+		return query.Where("calendar_id IS NULL AND owner_id = ?", event.OwnerID)
+	}
+	return query.Where("calendar_id = ?", *event.CalendarID)
+}
+
+func (r *eventRepository) NextEventPosition(event *models.Events) (int, error) {
+	var position int
+	if err := scopeColumn(database.WithWorkspace(r.db, event.WorkspaceID).Model(&models.Events{}), event).
+		Where("id <> ?", event.ID).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Scan(&position).Error; err != nil {
+		return 0, errors.Wrap(err, "[EventRepository.NextEventPosition]: Error getting next position")
+	}
+	return position, nil
+}
+
+// MoveEvent closes the slot the event leaves before opening the one it
+// takes, so an event moved down its own column lands where asked. The
+// events it shifts get a new version and publish an update, since their
+// position is part of what they show.
+func (r *eventRepository) MoveEvent(event *models.Events) error {
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		var previous models.Events
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "owner_id", "calendar_id", "status", "position").
+			Where("id = ?", event.ID).First(&previous).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		closed, err := shiftColumn(tx, &previous, event.ID, "position > ?", previous.Position, -1, now)
+		if err != nil {
+			return err
+		}
+		opened, err := shiftColumn(tx, event, event.ID, "position >= ?", event.Position, 1, now)
+		if err != nil {
+			return err
+		}
+		if err := patchEvent(tx, event, []string{"status", "position", "complete", "completed_at"}); err != nil {
+			return err
+		}
+
+		// An event shifted twice publishes its last version once
+		events := []*models.Events{event}
+		indexes := make(map[uint64]int)
+		for _, sibling := range append(closed, opened...) {
+			sibling.Changes = []string{constant.WebhookEventUpdated}
+			if i, found := indexes[sibling.ID]; found {
+				events[i] = sibling
+				continue
+			}
+			indexes[sibling.ID] = len(events)
+			events = append(events, sibling)
+		}
+		return database.SaveEventChanges(tx, events...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.MoveEvent]: Error moving event")
 	}
 	return nil
 }
 
-func (r *eventRepository) DeleteEvent(id uint64) error {
-	if err := r.db.Delete(&models.Events{}, id).Error; err != nil {
+// shiftColumn moves the events of the workflow column of event matching
+// condition with arg, but the event id, by offset positions and bumps their
+// version. It returns them as they are now.
+func shiftColumn(tx *gorm.DB, event *models.Events, id uint64, condition string, arg interface{}, offset int, now time.Time) ([]*models.Events, error) {
+	var shifted []*models.Events
+	if err := scopeColumn(tx.Model(&shifted), event).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "owner_id"}, {Name: "calendar_id"}, {Name: "version"}}}).
+		Where("id <> ?", id).
+		Where(condition, arg).
+		UpdateColumns(map[string]interface{}{
+			"position":   gorm.Expr("position + ?", offset),
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		}).Error; err != nil {
+		return nil, err
+	}
+	return shifted, nil
+}
+
+func (r *eventRepository) DeleteEvent(actor domain.Actor, id, version uint64) error {
+	err := database.WithWorkspace(r.db, actor.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		query := scopeEvents(tx, actor).Where("id = ?", id)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+
+		var events []*models.Events
+		if err := query.Session(&gorm.Session{}).Clauses(clause.Locking{Strength: "UPDATE"}).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			if version != 0 {
+				return domain.ErrEventVersionMismatch
+			}
+			return nil
+		}
+		if err := query.Delete(&models.Events{}).Error; err != nil {
+			return err
+		}
+		return database.CreateOutboxEvents(tx, database.NewOutboxEvent(events[0], constant.WebhookEventDeleted))
+	})
+	if err != nil {
 		return errors.Wrap(err, "[EventRepository.DeleteEvent]: Error soft deleting event")
 	}
 	return nil
 }
+
+func (r *eventRepository) GetDeletedEventList(actor domain.Actor, filter *domain.EventFilter) ([]*models.Events, int64, error) {
+	var events []*models.Events
+	var total int64
+
+	query := scopeOwnedEvents(r.db.Unscoped().Model(&models.Events{}), actor).Where("delete_at IS NOT NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.GetDeletedEventList]: Error counting deleted events")
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	if err := query.Order("delete_at DESC").Order("id").Offset(offset).Limit(filter.Limit).Scopes(preloadEvent).Find(&events).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[EventRepository.GetDeletedEventList]: Error getting deleted event list")
+	}
+
+	return events, total, nil
+}
+
+func (r *eventRepository) GetDeletedEventByID(actor domain.Actor, id uint64) (*models.Events, error) {
+	var event models.Events
+	if err := scopeOwnedEvents(r.db.Unscoped(), actor).Where("id = ? AND delete_at IS NOT NULL", id).Scopes(preloadEvent).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrEventNotFound, "[EventRepository.GetDeletedEventByID]: Error getting deleted event")
+		}
+		return nil, errors.Wrap(err, "[EventRepository.GetDeletedEventByID]: Error getting deleted event")
+	}
+	return &event, nil
+}
+
+func (r *eventRepository) RestoreEvent(event *models.Events) error {
+	now := time.Now()
+	err := database.WithWorkspace(r.db, event.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.Events{}).
+			Where("id = ? AND owner_id = ? AND version = ? AND delete_at IS NOT NULL", event.ID, event.OwnerID, event.Version).
+			Updates(map[string]interface{}{
+				"delete_at":   nil,
+				"calendar_id": event.CalendarID,
+				"updated_at":  now,
+				"version":     event.Version + 1,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrEventVersionMismatch
+		}
+
+		event.DeleteAt = gorm.DeletedAt{}
+		event.UpdatedAt = &now
+		event.Version++
+		return database.SaveEventChanges(tx, event)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.RestoreEvent]: Error restoring event")
+	}
+	return nil
+}
+
+func (r *eventRepository) PurgeEvent(actor domain.Actor, id, version uint64) error {
+	err := database.WithWorkspace(r.db, actor.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		query := scopeOwnedEvents(tx.Unscoped(), actor).Where("id = ?", id)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+		var events []*models.Events
+		if err := query.Session(&gorm.Session{}).Clauses(clause.Locking{Strength: "UPDATE"}).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return domain.ErrEventVersionMismatch
+		}
+		// Tag links, items, reminders and dependencies reference the event; they stay
+		// unless it is deleted
+		if err := tx.Where("event_id = ?", id).Delete(&models.EventTags{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&models.EventItems{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&models.Reminders{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ? OR blocker_id = ?", id, id).Delete(&models.EventDependencies{}).Error; err != nil {
+			return err
+		}
+		if err := query.Delete(&models.Events{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&models.EventExceptions{}).Error; err != nil {
+			return err
+		}
+		// Trashed events were already published as deleted
+		if events[0].DeleteAt.Valid {
+			return nil
+		}
+		return database.CreateOutboxEvents(tx, database.NewOutboxEvent(events[0], constant.WebhookEventDeleted))
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.PurgeEvent]: Error purging event")
+	}
+	return nil
+}
+
+func (r *eventRepository) PurgeDeletedEvents(deletedBefore time.Time) (int64, error) {
+	// The retention period is the same in every workspace
+	var purged int64
+	err := database.AllWorkspaces(r.db).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&models.Events{}).Select("id").Where("delete_at < ?", deletedBefore)
+		if err := tx.Where("event_id IN (?)", expired).Delete(&models.EventExceptions{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id IN (?)", expired).Delete(&models.EventTags{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id IN (?)", expired).Delete(&models.EventItems{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id IN (?)", expired).Delete(&models.Reminders{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id IN (?) OR blocker_id IN (?)", expired, expired).Delete(&models.EventDependencies{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("delete_at < ?", deletedBefore).Delete(&models.Events{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "[EventRepository.PurgeDeletedEvents]: Error purging deleted events")
+	}
+	return purged, nil
+}
+
+func (r *eventRepository) ProcessDueReminders(now time.Time, limit int, process func(reminder *models.Reminders) error) error {
+	// The scheduler serves every workspace
+	db := database.AllWorkspaces(r.db)
+
+	// Claimed reminders are due again once their lease runs out, so they
+	// are sent without holding locks and retried if this process dies
+	// sending them. Postgres keeps microseconds.
+	leasedUntil := now.Add(constant.ReminderLease).Truncate(time.Microsecond)
+	var reminders []*models.Reminders
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Joins("JOIN events ON events.id = reminders.event_id AND events.delete_at IS NULL").
+			Where("reminders.remind_at <= ?", now).
+			Order("reminders.remind_at").Order("reminders.id").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "reminders"}, Options: "SKIP LOCKED"}).
+			Find(&reminders).Error; err != nil {
+			return err
+		}
+		if len(reminders) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, 0, len(reminders))
+		for _, reminder := range reminders {
+			ids = append(ids, reminder.ID)
+		}
+		return tx.Model(&models.Reminders{}).Where("id IN ?", ids).Update("remind_at", leasedUntil).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "[EventRepository.ProcessDueReminders]: Error claiming due reminders")
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	eventIDs := make([]uint64, 0, len(reminders))
+	userIDs := make([]uint64, 0, len(reminders))
+	for _, reminder := range reminders {
+		eventIDs = append(eventIDs, reminder.EventID)
+		userIDs = append(userIDs, reminder.UserID)
+	}
+	var events []*models.Events
+	if err := db.Where("id IN ?", eventIDs).Find(&events).Error; err != nil {
+		return errors.Wrap(err, "[EventRepository.ProcessDueReminders]: Error getting events")
+	}
+	var users []*models.Users
+	if err := db.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return errors.Wrap(err, "[EventRepository.ProcessDueReminders]: Error getting users")
+	}
+	for _, reminder := range reminders {
+		for _, event := range events {
+			if event.ID == reminder.EventID {
+				reminder.Event = event
+			}
+		}
+		for _, user := range users {
+			if user.ID == reminder.UserID {
+				reminder.User = user
+			}
+		}
+	}
+
+	for _, reminder := range reminders {
+		if err := process(reminder); err != nil {
+			return errors.Wrap(err, "[EventRepository.ProcessDueReminders]: Error processing due reminders")
+		}
+		// Unless the event rescheduled it meanwhile
+		reminder.UpdatedAt = &now
+		if err := db.Model(reminder).
+			Where("remind_at = ?", leasedUntil).
+			Select("remind_at", "sent_at", "late", "attempts", "updated_at").
+			Updates(reminder).Error; err != nil {
+			return errors.Wrap(err, "[EventRepository.ProcessDueReminders]: Error saving reminder")
+		}
+	}
+	return nil
+}
+
+// scopeEventChanges limits query to the changes to the events actor could
+// see when they were made: their own, those in or leaving a calendar shared
+// with them, and the ones made for them alone.
+func scopeEventChanges(query *gorm.DB, actor domain.Actor) *gorm.DB {
+	shared := sharedCalendars(query, actor)
+	return query.Where("(user_id IS NULL AND (owner_id = ? OR calendar_id IN (?) OR previous_calendar_id IN (?))) OR user_id = ?",
+		actor.UserID, shared, shared, actor.UserID)
+}
+
+func (r *eventRepository) GetEventChanges(actor domain.Actor, after uint64, limit int) ([]*models.OutboxEvents, error) {
+	var changes []*models.OutboxEvents
+	if err := scopeEventChanges(database.WithWorkspace(r.db, actor.WorkspaceID), actor).
+		Where("id > ?", after).
+		Order("id").Limit(limit).Find(&changes).Error; err != nil {
+		return nil, errors.Wrap(err, "[EventRepository.GetEventChanges]: Error getting event changes")
+	}
+	return changes, nil
+}
+
+func (r *eventRepository) LastEventChangeAt(actor domain.Actor) (*time.Time, error) {
+	var row struct {
+		ChangedAt *time.Time
+	}
+	if err := scopeEventChanges(database.WithWorkspace(r.db, actor.WorkspaceID).Model(&models.OutboxEvents{}), actor).
+		Select("MAX(created_at) AS changed_at").
+		Scan(&row).Error; err != nil {
+		return nil, errors.Wrap(err, "[EventRepository.LastEventChangeAt]: Error getting last event change")
+	}
+	return row.ChangedAt, nil
+}
+
+func (r *eventRepository) LastEventChangeID() (uint64, error) {
+	var id uint64
+	if err := database.AllWorkspaces(r.db).Model(&models.OutboxEvents{}).
+		Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, errors.Wrap(err, "[EventRepository.LastEventChangeID]: Error getting last event change")
+	}
+	return id, nil
+}
+
+func (r *eventRepository) SubscribeEventChanges() (<-chan struct{}, func()) {
+	return database.EventChanges.Subscribe()
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunRepository returns a repository on a database with the tenant
+// scope that only builds statements, and the SQL of the queries it builds.
+// Subqueries are built first, so a query comes after its subqueries.
+func newDryRunRepository(t *testing.T) (*eventRepository, *[]string) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := database.RegisterTenantScope(db); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var statements []string
+	if err := db.Callback().Query().After("gorm:query").Register("test:record", func(db *gorm.DB) {
+		statements = append(statements, db.Statement.SQL.String())
+	}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return &eventRepository{db: db}, &statements
+}
+
+func TestEventRepository_GetEventList_Workspace(t *testing.T) {
+	actor := domain.Actor{UserID: 1, WorkspaceID: 3}
+
+	tests := []struct {
+		name     string
+		filter   *domain.EventFilter
+		expected []string
+	}{
+		{
+			name:   "any tag",
+			filter: &domain.EventFilter{Page: 1, Limit: 10, Tags: []string{"exam", "math"}},
+			expected: []string{
+				`"events"."workspace_id" = $`,
+				`"calendar_shares"."workspace_id" = $`,
+				`FROM "tags" JOIN event_tags ON event_tags.tag_id = tags.id WHERE tags.name IN ($`,
+				`"tags"."workspace_id" = $`,
+			},
+		},
+		{
+			name:   "all tags",
+			filter: &domain.EventFilter{Page: 1, Limit: 10, Tags: []string{"exam", "math"}, AllTags: true},
+			expected: []string{
+				`"tags"."workspace_id" = $`,
+				`GROUP BY "event_tags"."event_id" HAVING COUNT(DISTINCT tags.name) = $`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, statements := newDryRunRepository(t)
+			if _, _, err := repo.GetEventList(actor, tt.filter); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			statement := lastStatement(t, statements)
+			for _, expected := range tt.expected {
+				if !strings.Contains(statement, expected) {
+					t.Errorf("Expected %q in %s", expected, statement)
+				}
+			}
+		})
+	}
+}
+
+func TestEventRepository_GetEventList_Location(t *testing.T) {
+	actor := domain.Actor{UserID: 1}
+
+	repo, statements := newDryRunRepository(t)
+	if _, _, err := repo.GetEventList(actor, &domain.EventFilter{Page: 1, Limit: 10}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// An empty location filters nothing, so events without one are listed
+	if statement := lastStatement(t, statements); strings.Contains(statement, "location") {
+		t.Errorf("Expected no location condition, got %s", statement)
+	}
+
+	repo, statements = newDryRunRepository(t)
+	if _, _, err := repo.GetEventList(actor, &domain.EventFilter{Page: 1, Limit: 10, Location: "room"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if statement := lastStatement(t, statements); !strings.Contains(statement, "location ILIKE $") {
+		t.Errorf("Expected a location condition, got %s", statement)
+	}
+}
+
+func TestEventRepository_GetEventByID_Workspace(t *testing.T) {
+	repo, statements := newDryRunRepository(t)
+	if _, err := repo.GetEventByID(domain.Actor{UserID: 1, WorkspaceID: 3}, 7); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	statement := lastStatement(t, statements)
+	for _, expected := range []string{
+		`"events"."workspace_id" = $`,
+		`events.calendar_id IN (SELECT "calendar_id" FROM "calendar_shares" WHERE (user_id = $`,
+		`"calendar_shares"."workspace_id" = $`,
+	} {
+		if !strings.Contains(statement, expected) {
+			t.Errorf("Expected %q in %s", expected, statement)
+		}
+	}
+}
+
+func TestEventRepository_GetEventDependencies_Workspace(t *testing.T) {
+	repo, statements := newDryRunRepository(t)
+	if _, err := repo.GetEventDependencies(domain.Actor{UserID: 1, WorkspaceID: 3}, []uint64{7, 8}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Both sides of the join only reach events of the workspace, trashed
+	// ones included
+	statement := lastStatement(t, statements)
+	if strings.Count(statement, `"events"."workspace_id" = $`) != 2 || strings.Contains(statement, "delete_at") {
+		t.Errorf("Expected the dependencies limited to events of the workspace, got %s", statement)
+	}
+}
+
+func TestEventRepository_GetEventChanges_Scope(t *testing.T) {
+	repo, statements := newDryRunRepository(t)
+	if _, err := repo.GetEventChanges(domain.Actor{UserID: 1, WorkspaceID: 3}, 5, 10); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The changes to the events actor sees or that left a calendar shared
+	// with them, and the ones made for them alone
+	statement := lastStatement(t, statements)
+	for _, expected := range []string{
+		`"outbox_events"."workspace_id" = $`,
+		`user_id IS NULL AND (owner_id = $`,
+		`previous_calendar_id IN (SELECT "calendar_id" FROM "calendar_shares"`,
+		`OR user_id = $`,
+	} {
+		if !strings.Contains(statement, expected) {
+			t.Errorf("Expected %q in %s", expected, statement)
+		}
+	}
+}
+
+func lastStatement(t *testing.T, statements *[]string) string {
+	if len(*statements) == 0 {
+		t.Fatal("Expected a statement")
+	}
+	return (*statements)[len(*statements)-1]
+}
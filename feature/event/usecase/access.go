@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// sharedRoles maps the calendars shared with actor to the role they have.
+func (u *eventUsecase) sharedRoles(actor domain.Actor) (map[uint64]string, error) {
+	shares, err := u.calendarRepository.GetUserCalendarShares(actor, constant.CalendarShareAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[uint64]string, len(shares))
+	for _, share := range shares {
+		roles[share.CalendarID] = share.Role
+	}
+	return roles, nil
+}
+
+// eventAccess is the role actor has on an event owned by ownerID in the
+// calendar calendarID, "" when they have none.
+func eventAccess(actor domain.Actor, roles map[uint64]string, ownerID uint64, calendarID *uint64) string {
+	if ownerID == actor.UserID {
+		return constant.CalendarRoleOwner
+	}
+	if calendarID == nil {
+		return ""
+	}
+	return roles[*calendarID]
+}
+
+// setAccess marks each of events with the role actor has on it.
+func (u *eventUsecase) setAccess(actor domain.Actor, events ...*response.EventResponse) error {
+	roles, err := u.sharedRoles(actor)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		event.Access = eventAccess(actor, roles, event.OwnerID, event.CalendarID)
+	}
+	return nil
+}
+
+// checkEditAccess fails with ErrEventReadOnly unless actor may write event.
+func (u *eventUsecase) checkEditAccess(actor domain.Actor, event *models.Events) error {
+	if event.OwnerID == actor.UserID {
+		return nil
+	}
+
+	roles, err := u.sharedRoles(actor)
+	if err != nil {
+		return err
+	}
+	switch eventAccess(actor, roles, event.OwnerID, event.CalendarID) {
+	case constant.CalendarRoleEditor, constant.CalendarRoleOwner:
+		return nil
+	default:
+		return domain.ErrEventReadOnly
+	}
+}
+
+// calendarOwner checks that actor may put events in the calendar
+// calendarID and returns who owns the events in it: the owner of the
+// calendar, or actor when there is no calendar.
+func (u *eventUsecase) calendarOwner(actor domain.Actor, calendarID *uint64) (uint64, error) {
+	if calendarID == nil {
+		return actor.UserID, nil
+	}
+
+	calendar, err := u.calendarRepository.GetCalendarByID(actor, *calendarID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarNotFound) {
+			return 0, domain.NewValidationError("calendar not found", response.FieldError{
+				Field: "calendarId", Rule: "exists", Message: "calendarId must be one of your calendars",
+			})
+		}
+		return 0, err
+	}
+	if calendar.OwnerID == actor.UserID {
+		return actor.UserID, nil
+	}
+
+	roles, err := u.sharedRoles(actor)
+	if err != nil {
+		return 0, err
+	}
+	if role := roles[calendar.ID]; role != constant.CalendarRoleEditor && role != constant.CalendarRoleOwner {
+		return 0, domain.ErrCalendarReadOnly
+	}
+	return calendar.OwnerID, nil
+}
+
+// validateCalendar checks that actor may move an event owned by ownerID to
+// the calendar calendarID. Events stay with their owner, so it must be one
+// of theirs; only they may take an event out of every calendar.
+func (u *eventUsecase) validateCalendar(actor domain.Actor, calendarID *uint64, ownerID uint64) error {
+	owner, err := u.calendarOwner(actor, calendarID)
+	if err != nil {
+		return err
+	}
+	if owner != ownerID {
+		return domain.NewValidationError("calendar belongs to another user", response.FieldError{
+			Field: "calendarId", Rule: "owner", Message: "calendarId must be a calendar of the event's owner",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+func (u *eventUsecase) GetEventItemList(actor domain.Actor, id uint64) ([]*response.EventItemResponse, error) {
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventItemList]: Error getting event")
+	}
+
+	if event == nil {
+		return nil, errors.Wrap(domain.ErrEventNotFound, "[EventUsecase.GetEventItemList]: Error getting event")
+	}
+	return newEventItemResponses(event.Items), nil
+}
+
+func (u *eventUsecase) CreateEventItem(actor domain.Actor, id uint64, req *request.EventItemRequest) (*response.EventItemResponse, error) {
+	event, err := u.getEditableEvent(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEventItem]: Error getting event")
+	}
+
+	if len(event.Items) >= constant.MaxEventItems {
+		message := fmt.Sprintf("an event may have at most %d checklist items", constant.MaxEventItems)
+		return nil, errors.Wrap(domain.NewValidationError(message, response.FieldError{
+			Field: "items", Rule: "max", Message: message,
+		}), "[EventUsecase.CreateEventItem]: Checklist is full")
+	}
+	text, err := itemText(req.Text)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEventItem]: Invalid item")
+	}
+
+	item := &models.EventItems{
+		WorkspaceID: event.WorkspaceID,
+		Text:        text,
+		Done:        req.Done,
+	}
+	items := slices.Insert(slices.Clone(event.Items), itemPosition(req.Position, len(event.Items)), item)
+	if err := u.saveEventItems(actor, event, items, nil, item); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEventItem]: Error creating item")
+	}
+	return newEventItemResponse(item), nil
+}
+
+func (u *eventUsecase) UpdateEventItem(actor domain.Actor, id, itemID uint64, req *request.EventItemRequest) (*response.EventItemResponse, error) {
+	event, err := u.getEditableEvent(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEventItem]: Error getting event")
+	}
+
+	index := slices.IndexFunc(event.Items, func(item *models.EventItems) bool { return item.ID == itemID })
+	if index < 0 {
+		return nil, errors.Wrap(domain.ErrEventItemNotFound, "[EventUsecase.UpdateEventItem]: Error getting item")
+	}
+	text, err := itemText(req.Text)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEventItem]: Invalid item")
+	}
+
+	item := event.Items[index]
+	item.Text = text
+	item.Done = req.Done
+	items := slices.Clone(event.Items)
+	if req.Position != nil {
+		items = slices.Delete(items, index, index+1)
+		items = slices.Insert(items, itemPosition(req.Position, len(items)), item)
+	}
+	if err := u.saveEventItems(actor, event, items, nil, item); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEventItem]: Error updating item")
+	}
+	return newEventItemResponse(item), nil
+}
+
+func (u *eventUsecase) DeleteEventItem(actor domain.Actor, id, itemID uint64) error {
+	event, err := u.getEditableEvent(actor, id)
+	if err != nil {
+		return errors.Wrap(err, "[EventUsecase.DeleteEventItem]: Error getting event")
+	}
+
+	index := slices.IndexFunc(event.Items, func(item *models.EventItems) bool { return item.ID == itemID })
+	if index < 0 {
+		return errors.Wrap(domain.ErrEventItemNotFound, "[EventUsecase.DeleteEventItem]: Error getting item")
+	}
+
+	removed := event.Items[index]
+	items := slices.Delete(slices.Clone(event.Items), index, index+1)
+	if err := u.saveEventItems(actor, event, items, []*models.EventItems{removed}); err != nil {
+		return errors.Wrap(err, "[EventUsecase.DeleteEventItem]: Error deleting item")
+	}
+	return nil
+}
+
+// getEditableEvent returns the event id, which actor must be allowed to edit.
+func (u *eventUsecase) getEditableEvent(actor domain.Actor, id uint64) (*models.Events, error) {
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, domain.ErrEventNotFound
+	}
+	if err := u.checkEditAccess(actor, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// saveEventItems makes items, in order, the checklist of event, saving the
+// items whose position moved along with changed ones and completing event
+// if its checklist drives it, unless it is blocked, which moves it to the
+// state agreeing.
+func (u *eventUsecase) saveEventItems(actor domain.Actor, event *models.Events, items, removed []*models.EventItems, changed ...*models.EventItems) error {
+	for i, item := range items {
+		if item.Position != i && !slices.Contains(changed, item) {
+			changed = append(changed, item)
+		}
+		item.Position = i
+	}
+
+	event.Items = items
+	wasComplete := event.Complete
+	if event.AutoComplete && len(items) > 0 {
+		if done := checklistDone(items); !done || event.AllowBlockedCompletion || !blocked(event) {
+			setComplete(event, done)
+		}
+	}
+	if err := u.setEventStatus(actor, event, "", false); err != nil {
+		return err
+	}
+	publishChange(event, wasComplete)
+	return u.eventRepository.SaveEventItems(event, changed, removed)
+}
+
+// itemText is the trimmed text of a checklist item, which must not be blank.
+func itemText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", domain.NewValidationError("text must not be blank", response.FieldError{
+			Field: "text", Rule: "required", Message: "text must not be blank",
+		})
+	}
+	return text, nil
+}
+
+// itemPosition is where an item goes in a checklist of count other items:
+// position clamped to its end, or the end when nil.
+func itemPosition(position *int, count int) int {
+	if position == nil {
+		return count
+	}
+	return min(*position, count)
+}
+
+func checklistDone(items []*models.EventItems) bool {
+	for _, item := range items {
+		if !item.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// eventProgress counts the done items of the checklist of event, nil when
+// it has none.
+func eventProgress(event *models.Events) *response.EventProgress {
+	if len(event.Items) == 0 {
+		return nil
+	}
+
+	progress := &response.EventProgress{Total: len(event.Items)}
+	for _, item := range event.Items {
+		if item.Done {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+func newEventItemResponses(items []*models.EventItems) []*response.EventItemResponse {
+	itemResponses := make([]*response.EventItemResponse, 0, len(items))
+	for _, item := range items {
+		itemResponses = append(itemResponses, newEventItemResponse(item))
+	}
+	return itemResponses
+}
+
+func newEventItemResponse(item *models.EventItems) *response.EventItemResponse {
+	return &response.EventItemResponse{
+		ID:        item.ID,
+		EventID:   item.EventID,
+		Text:      item.Text,
+		Done:      item.Done,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+// Helper function to create an event with a checklist of the given items,
+// done or not
+func createTestChecklistEvent(done ...bool) *models.Events {
+	startTime, endTime := getTestTimes()
+	event := createTestEvent(1, "Packing", "Description", "Home", false, startTime, endTime)
+	for i, itemDone := range done {
+		event.Items = append(event.Items, &models.EventItems{
+			ID:       uint64(i + 1),
+			EventID:  event.ID,
+			Text:     "Item " + string(rune('A'+i)),
+			Done:     itemDone,
+			Position: i,
+		})
+	}
+	return event
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// itemOrder lists the ids of items in order, checking their positions
+func itemOrder(t *testing.T, items []*models.EventItems) []uint64 {
+	t.Helper()
+	ids := make([]uint64, 0, len(items))
+	for i, item := range items {
+		if item.Position != i {
+			t.Errorf("Expected item %d at position %d, got %d", item.ID, i, item.Position)
+		}
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestEventUsecase_CreateEventItem(t *testing.T) {
+	tests := []struct {
+		name           string
+		items          int
+		request        *request.EventItemRequest
+		expectedOrder  []uint64
+		expectedSaved  int
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name:          "appended by default",
+			items:         2,
+			request:       &request.EventItemRequest{Text: "  Passport "},
+			expectedOrder: []uint64{1, 2, 3},
+			expectedSaved: 1,
+		},
+		{
+			name:          "inserted at a position",
+			items:         2,
+			request:       &request.EventItemRequest{Text: "Passport", Position: intPtr(0)},
+			expectedOrder: []uint64{3, 1, 2},
+			expectedSaved: 3,
+		},
+		{
+			name:          "position past the end",
+			items:         2,
+			request:       &request.EventItemRequest{Text: "Passport", Position: intPtr(10)},
+			expectedOrder: []uint64{1, 2, 3},
+			expectedSaved: 1,
+		},
+		{
+			name:           "blank text",
+			items:          2,
+			request:        &request.EventItemRequest{Text: "   "},
+			expectedError:  true,
+			expectedErrMsg: "text must not be blank",
+		},
+		{
+			name:           "checklist full",
+			items:          constant.MaxEventItems,
+			request:        &request.EventItemRequest{Text: "Passport"},
+			expectedError:  true,
+			expectedErrMsg: "at most",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := createTestChecklistEvent(make([]bool, tt.items)...)
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{event}
+			mockRepo.lastItemID = uint64(tt.items)
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.CreateEventItem(testActor, 1, tt.request)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.ID != 3 || result.Text != "Passport" || result.EventID != 1 {
+				t.Errorf("Expected item 3 'Passport' of event 1, got %+v", result)
+			}
+			if order := itemOrder(t, event.Items); !slices.Equal(order, tt.expectedOrder) {
+				t.Errorf("Expected order %v, got %v", tt.expectedOrder, order)
+			}
+			if len(mockRepo.savedItems) != tt.expectedSaved {
+				t.Errorf("Expected %d items saved, got %d", tt.expectedSaved, len(mockRepo.savedItems))
+			}
+			if event.Version != 2 {
+				t.Errorf("Expected the event version bumped to 2, got %d", event.Version)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_UpdateEventItem(t *testing.T) {
+	tests := []struct {
+		name          string
+		itemID        uint64
+		request       *request.EventItemRequest
+		expectedOrder []uint64
+		expectedSaved int
+		expectedError error
+	}{
+		{
+			name:          "checked off in place",
+			itemID:        2,
+			request:       &request.EventItemRequest{Text: "Item B", Done: true},
+			expectedOrder: []uint64{1, 2, 3},
+			expectedSaved: 1,
+		},
+		{
+			name:          "moved to the front",
+			itemID:        3,
+			request:       &request.EventItemRequest{Text: "Item C", Position: intPtr(0)},
+			expectedOrder: []uint64{3, 1, 2},
+			expectedSaved: 3,
+		},
+		{
+			name:          "moved to the end",
+			itemID:        1,
+			request:       &request.EventItemRequest{Text: "Item A", Position: intPtr(5)},
+			expectedOrder: []uint64{2, 3, 1},
+			expectedSaved: 3,
+		},
+		{
+			name:          "item not found",
+			itemID:        9,
+			request:       &request.EventItemRequest{Text: "Item"},
+			expectedError: domain.ErrEventItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := createTestChecklistEvent(false, false, false)
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{event}
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.UpdateEventItem(testActor, 1, tt.itemID, tt.request)
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected error %v, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.ID != tt.itemID || result.Done != tt.request.Done {
+				t.Errorf("Expected item %d done %v, got %+v", tt.itemID, tt.request.Done, result)
+			}
+			if order := itemOrder(t, event.Items); !slices.Equal(order, tt.expectedOrder) {
+				t.Errorf("Expected order %v, got %v", tt.expectedOrder, order)
+			}
+			if len(mockRepo.savedItems) != tt.expectedSaved {
+				t.Errorf("Expected %d items saved, got %d", tt.expectedSaved, len(mockRepo.savedItems))
+			}
+		})
+	}
+}
+
+func TestEventUsecase_DeleteEventItem(t *testing.T) {
+	event := createTestChecklistEvent(false, true, false)
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{event}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	if err := usecase.DeleteEventItem(testActor, 1, 1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if order := itemOrder(t, event.Items); !slices.Equal(order, []uint64{2, 3}) {
+		t.Errorf("Expected order [2 3], got %v", order)
+	}
+	if len(mockRepo.removedItems) != 1 || mockRepo.removedItems[0].ID != 1 {
+		t.Errorf("Expected item 1 removed, got %+v", mockRepo.removedItems)
+	}
+	if len(mockRepo.savedItems) != 2 {
+		t.Errorf("Expected the 2 shifted items saved, got %d", len(mockRepo.savedItems))
+	}
+
+	if err := usecase.DeleteEventItem(testActor, 1, 1); !errors.Is(err, domain.ErrEventItemNotFound) {
+		t.Errorf("Expected item not found deleting it again, got: %v", err)
+	}
+}
+
+func TestEventUsecase_EventItems_AutoComplete(t *testing.T) {
+	tests := []struct {
+		name             string
+		autoComplete     bool
+		expectedComplete bool
+	}{
+		{
+			name:             "completes the event",
+			autoComplete:     true,
+			expectedComplete: true,
+		},
+		{
+			name:             "leaves the event alone when off",
+			autoComplete:     false,
+			expectedComplete: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := createTestChecklistEvent(true, false)
+			event.AutoComplete = tt.autoComplete
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{event}
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			if _, err := usecase.UpdateEventItem(testActor, 1, 2, &request.EventItemRequest{Text: "Item B", Done: true}); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if event.Complete != tt.expectedComplete || (event.CompletedAt != nil) != tt.expectedComplete {
+				t.Errorf("Expected complete %v, got %v completed at %v", tt.expectedComplete, event.Complete, event.CompletedAt)
+			}
+
+			// Unchecking an item reopens an auto-completed event
+			if _, err := usecase.UpdateEventItem(testActor, 1, 1, &request.EventItemRequest{Text: "Item A"}); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if event.Complete || event.CompletedAt != nil {
+				t.Errorf("Expected the event open again, got complete %v completed at %v", event.Complete, event.CompletedAt)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_EventProgress(t *testing.T) {
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{createTestChecklistEvent(true, true, false, true, false)}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.GetEventByID(testActor, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Progress == nil || result.Progress.Done != 3 || result.Progress.Total != 5 {
+		t.Errorf("Expected progress 3/5, got %+v", result.Progress)
+	}
+
+	items, err := usecase.GetEventItemList(testActor, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(items) != 5 || items[0].Position != 0 || items[4].Position != 4 {
+		t.Errorf("Expected the 5 items in order, got %+v", items)
+	}
+
+	mockRepo.events[0].Items = nil
+	result, err = usecase.GetEventByID(testActor, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Progress != nil {
+		t.Errorf("Expected no progress without a checklist, got %+v", result.Progress)
+	}
+}
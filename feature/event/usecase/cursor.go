@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+)
+
+// eventCursor is what an event list cursor holds: the sort values and ID of
+// the event its page starts after, or ends before when Backward. Sort is
+// the sort of the list it was made for, as a cursor means nothing in
+// another order.
+type eventCursor struct {
+	Sort      string            `json:"s"`
+	Values    []json.RawMessage `json:"v"`
+	ID        uint64            `json:"i"`
+	Backward  bool              `json:"b,omitempty"`
+	Inclusive bool              `json:"n,omitempty"` // the page takes the event in too
+}
+
+// getCursorEventList pages the event list from cursor, from the start when
+// it is empty. Pages read one event past the limit to tell whether the list
+// goes on.
+func (u *eventUsecase) getCursorEventList(actor domain.Actor, filter *domain.EventFilter, cursor string) (*response.PaginatedResponse[*response.EventResponse], error) {
+	filter.Cursor = true
+	if cursor != "" {
+		seek, err := parseEventCursor(cursor, filter.Sort)
+		if err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Invalid cursor")
+		}
+		filter.Seek = seek
+	}
+
+	events, total, err := u.eventRepository.GetEventList(actor, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error getting event list")
+	}
+
+	more := len(events) > filter.Limit
+	events = events[:min(len(events), filter.Limit)]
+	backward := filter.Seek != nil && filter.Seek.Backward
+	if backward {
+		slices.Reverse(events)
+	}
+
+	pagination := &response.CursorPagination{Limit: filter.Limit}
+	if filter.WithTotal {
+		count := int(total)
+		pagination.Total = &count
+	}
+	if backward {
+		if more {
+			pagination.PrevCursor = newEventCursor(filter.Sort, events[0], true)
+		}
+		pagination.NextCursor = boundaryCursor(filter.Sort, events, filter.Seek, false)
+	} else {
+		if more {
+			pagination.NextCursor = newEventCursor(filter.Sort, events[len(events)-1], false)
+		}
+		if filter.Seek != nil {
+			pagination.PrevCursor = boundaryCursor(filter.Sort, events, filter.Seek, true)
+		}
+	}
+
+	eventResponses := make([]*response.EventResponse, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, newEventResponse(event))
+	}
+	if err := u.setAccess(actor, eventResponses...); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error getting access")
+	}
+
+	return &response.PaginatedResponse[*response.EventResponse]{
+		Data:             eventResponses,
+		CursorPagination: pagination,
+	}, nil
+}
+
+// boundaryCursor leads from a page reached by seek back the way it came:
+// from its first event going backward, or its last going forward, or from
+// the event seek started from, taking it in, when the page is empty.
+func boundaryCursor(sort []domain.SortField, events []*models.Events, seek *domain.EventSeek, backward bool) *string {
+	switch {
+	case len(events) == 0:
+		return encodeEventCursor(sort, seek.Values, seek.ID, backward, !seek.Inclusive)
+	case backward:
+		return newEventCursor(sort, events[0], true)
+	default:
+		return newEventCursor(sort, events[len(events)-1], false)
+	}
+}
+
+// newEventCursor is the cursor of the page after event in the order sort
+// gives, or before it when backward.
+func newEventCursor(sort []domain.SortField, event *models.Events, backward bool) *string {
+	values := make([]any, 0, len(sort))
+	for _, field := range sort {
+		values = append(values, eventSortValue(event, field.Column))
+	}
+	return encodeEventCursor(sort, values, event.ID, backward, false)
+}
+
+func encodeEventCursor(sort []domain.SortField, values []any, id uint64, backward, inclusive bool) *string {
+	cursor := eventCursor{Sort: sortKey(sort), ID: id, Backward: backward, Inclusive: inclusive}
+	for _, value := range values {
+		// Sort values are strings, numbers, booleans and times
+		raw, _ := json.Marshal(value)
+		cursor.Values = append(cursor.Values, raw)
+	}
+	token := utils.EncodeCursor(cursor)
+	return &token
+}
+
+// parseEventCursor reads where a cursor made for a list sorted by sort
+// seeks to.
+func parseEventCursor(token string, sort []domain.SortField) (*domain.EventSeek, error) {
+	var cursor eventCursor
+	if err := utils.DecodeCursor(token, &cursor); err != nil {
+		return nil, domain.ErrEventCursorInvalid
+	}
+	if cursor.Sort != sortKey(sort) || len(cursor.Values) != len(sort) {
+		return nil, domain.ErrEventCursorInvalid
+	}
+
+	seek := &domain.EventSeek{ID: cursor.ID, Backward: cursor.Backward, Inclusive: cursor.Inclusive}
+	for i, field := range sort {
+		value, err := parseSortValue(field.Column, cursor.Values[i])
+		if err != nil {
+			return nil, domain.ErrEventCursorInvalid
+		}
+		seek.Values = append(seek.Values, value)
+	}
+	return seek, nil
+}
+
+// sortKey spells sort out the way the sort parameter does, by column.
+func sortKey(sort []domain.SortField) string {
+	keys := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Desc {
+			keys = append(keys, "-"+field.Column)
+		} else {
+			keys = append(keys, field.Column)
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// eventSortValue is the value event has in a column of eventSortColumns,
+// nil for NULL.
+func eventSortValue(event *models.Events, column string) any {
+	switch column {
+	case "title":
+		return event.Title
+	case "location":
+		return event.Location
+	case "complete":
+		return event.Complete
+	case "status":
+		return event.Status
+	case "position":
+		return event.Position
+	case "priority":
+		return event.Priority
+	}
+
+	var value *time.Time
+	switch column {
+	case "start_time":
+		value = event.StartTime
+	case "end_time":
+		value = event.EndTime
+	case "due_at":
+		value = event.DueAt
+	case "created_at":
+		value = event.CreatedAt
+	case "updated_at":
+		value = event.UpdatedAt
+	}
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// parseSortValue reads a value eventSortValue gave for column back.
+func parseSortValue(column string, raw json.RawMessage) (any, error) {
+	switch column {
+	case "title", "location", "status":
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case "complete":
+		var value bool
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case "position", "priority":
+		var value int
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	default:
+		var value *time.Time
+		if err := json.Unmarshal(raw, &value); err != nil || value == nil {
+			return nil, err
+		}
+		return *value, nil
+	}
+}
+
+// errCursorUnsupported refuses a cursor for what is only paged by page.
+func errCursorUnsupported(what string) error {
+	message := "cursor paging is not available for " + what
+	return domain.NewValidationError(message, response.FieldError{
+		Field: "cursor", Rule: "excluded", Message: message,
+	})
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// cursorTestEvents are events to page through: two at the same time, told
+// apart by ID, and a task without a window, which sorts last.
+func cursorTestEvents() []*models.Events {
+	startTime, endTime := getTestTimes()
+	events := []*models.Events{
+		createTestEvent(1, "Meeting", "Description", "Office", false, startTime.Add(2*time.Hour), endTime.Add(2*time.Hour)),
+		createTestEvent(2, "Lunch", "Description", "Cafe", false, startTime, endTime),
+		createTestEvent(3, "Standup", "Description", "Office", false, startTime.Add(time.Hour), endTime.Add(time.Hour)),
+		createTestEvent(4, "Review", "Description", "Office", false, startTime, endTime),
+		createTestEvent(5, "Groceries", "Description", "Market", false, startTime, endTime),
+	}
+	events[4].Type = constant.EventTypeTask
+	events[4].StartTime, events[4].EndTime = nil, nil
+	events[0].Priority, events[2].Priority = 3, 3
+	dueAt := startTime.Add(24 * time.Hour)
+	events[2].DueAt = &dueAt
+	return events
+}
+
+func listEventsByCursor(t *testing.T, usecase domain.EventUsecase, req request.EventListRequest, cursor string) *response.PaginatedResponse[*response.EventResponse] {
+	t.Helper()
+	req.Cursor = &cursor
+	resp, err := usecase.GetEventList(testActor, &req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.CursorPagination == nil {
+		t.Fatal("Expected cursor pagination")
+	}
+	return resp
+}
+
+func pageIDs(resp *response.PaginatedResponse[*response.EventResponse]) []uint64 {
+	ids := make([]uint64, 0, len(resp.Data))
+	for _, event := range resp.Data {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestEventUsecase_GetEventList_Cursor(t *testing.T) {
+	tests := []struct {
+		name     string
+		sort     string
+		expected [][]uint64 // pages in order
+	}{
+		{
+			name:     "by start time",
+			expected: [][]uint64{{2, 4}, {3, 1}, {5}},
+		},
+		{
+			name:     "by priority, then due date",
+			sort:     "-priority,dueAt",
+			expected: [][]uint64{{3, 1}, {2, 4}, {5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockEventRepository()
+			mockRepo.events = cursorTestEvents()
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			req := request.EventListRequest{PaginationRequest: request.PaginationRequest{Page: 1, Limit: 2}, Sort: tt.sort}
+
+			// Forward to the end
+			var pages []*response.PaginatedResponse[*response.EventResponse]
+			cursor := ""
+			for {
+				page := listEventsByCursor(t, usecase, req, cursor)
+				pages = append(pages, page)
+				if page.CursorPagination.NextCursor == nil {
+					break
+				}
+				cursor = *page.CursorPagination.NextCursor
+				if len(pages) > len(tt.expected) {
+					t.Fatal("Expected the pages to end")
+				}
+			}
+			var ids [][]uint64
+			for _, page := range pages {
+				ids = append(ids, pageIDs(page))
+			}
+			if !slices.EqualFunc(ids, tt.expected, slices.Equal) {
+				t.Fatalf("Expected pages %v, got %v", tt.expected, ids)
+			}
+			if pages[0].CursorPagination.PrevCursor != nil {
+				t.Error("Expected no cursor before the first page")
+			}
+			if pages[0].CursorPagination.Total != nil {
+				t.Error("Expected no total unless asked for")
+			}
+
+			// And back to the start
+			last := pages[len(pages)-1]
+			for i := len(pages) - 2; i >= 0; i-- {
+				if last.CursorPagination.PrevCursor == nil {
+					t.Fatalf("Expected a cursor back to page %d", i+1)
+				}
+				last = listEventsByCursor(t, usecase, req, *last.CursorPagination.PrevCursor)
+				if ids := pageIDs(last); !slices.Equal(ids, tt.expected[i]) {
+					t.Errorf("Expected page %d to be %v going back, got %v", i+1, tt.expected[i], ids)
+				}
+				if last.CursorPagination.NextCursor == nil {
+					t.Errorf("Expected a cursor forward from page %d", i+1)
+				}
+			}
+			if last.CursorPagination.PrevCursor != nil {
+				t.Error("Expected no cursor before the first page, reached going back")
+			}
+		})
+	}
+}
+
+func TestEventUsecase_GetEventList_CursorWhileInserting(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	mockRepo.events = cursorTestEvents()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	req := request.EventListRequest{PaginationRequest: request.PaginationRequest{Page: 1, Limit: 2}, WithTotal: true}
+
+	first := listEventsByCursor(t, usecase, req, "")
+	if total := first.CursorPagination.Total; total == nil || *total != 5 {
+		t.Errorf("Expected the total when asked for, got %v", total)
+	}
+
+	// One sorting before the page read, one after
+	mockRepo.events = append(mockRepo.events,
+		createTestEvent(6, "Breakfast", "Description", "Home", false, startTime.Add(-time.Hour), endTime.Add(-time.Hour)),
+		createTestEvent(7, "Dinner", "Description", "Home", false, startTime.Add(5*time.Hour), endTime.Add(5*time.Hour)))
+
+	var ids []uint64
+	for page := first; ; {
+		ids = append(ids, pageIDs(page)...)
+		if page.CursorPagination.NextCursor == nil {
+			break
+		}
+		page = listEventsByCursor(t, usecase, req, *page.CursorPagination.NextCursor)
+	}
+	if expected := []uint64{2, 4, 3, 1, 7, 5}; !slices.Equal(ids, expected) {
+		t.Errorf("Expected %v, without skips or repeats, got %v", expected, ids)
+	}
+}
+
+func TestEventUsecase_GetEventList_CursorPastTheEnd(t *testing.T) {
+	mockRepo := newMockEventRepository()
+	mockRepo.events = cursorTestEvents()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	req := request.EventListRequest{PaginationRequest: request.PaginationRequest{Page: 1, Limit: 4}}
+
+	first := listEventsByCursor(t, usecase, req, "")
+	// The rest is deleted before it is read
+	mockRepo.events = mockRepo.events[:4]
+	empty := listEventsByCursor(t, usecase, req, *first.CursorPagination.NextCursor)
+	if len(empty.Data) != 0 || empty.CursorPagination.NextCursor != nil || empty.CursorPagination.PrevCursor == nil {
+		t.Fatalf("Expected an empty last page leading back, got %v", pageIDs(empty))
+	}
+
+	back := listEventsByCursor(t, usecase, req, *empty.CursorPagination.PrevCursor)
+	if ids := pageIDs(back); !slices.Equal(ids, pageIDs(first)) {
+		t.Errorf("Expected the first page again, %v, got %v", pageIDs(first), ids)
+	}
+}
+
+func TestEventUsecase_GetEventList_CursorErrors(t *testing.T) {
+	mockRepo := newMockEventRepository()
+	mockRepo.events = cursorTestEvents()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	page := request.PaginationRequest{Page: 1, Limit: 2}
+	cursor := *listEventsByCursor(t, usecase, request.EventListRequest{PaginationRequest: page}, "").CursorPagination.NextCursor
+	garbage := "not a cursor"
+
+	from, to := getTestTimes()
+	tests := []struct {
+		name    string
+		req     request.EventListRequest
+		invalid bool // the cursor itself
+	}{
+		{name: "malformed", req: request.EventListRequest{PaginationRequest: page, Cursor: &garbage}, invalid: true},
+		{name: "another sort", req: request.EventListRequest{PaginationRequest: page, Cursor: &cursor, Sort: "-startTime"}, invalid: true},
+		{name: "occurrences", req: request.EventListRequest{PaginationRequest: page, Cursor: &cursor, From: &from, To: &to}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := usecase.GetEventList(testActor, &tt.req)
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || domainErr.Code != constant.CodeValidationFailed {
+				t.Fatalf("Expected a validation error, got: %v", err)
+			}
+			if tt.invalid && !errors.Is(err, domain.ErrEventCursorInvalid) {
+				t.Errorf("Expected ErrEventCursorInvalid, got: %v", err)
+			}
+		})
+	}
+
+	search := &request.EventSearchRequest{EventListRequest: request.EventListRequest{PaginationRequest: page, Cursor: &cursor}, Query: "meeting"}
+	if _, err := usecase.SearchEvents(testActor, search); err == nil {
+		t.Error("Expected search to refuse cursors")
+	}
+}
+
+func TestEventUsecase_GetEventList_PageMode(t *testing.T) {
+	mockRepo := newMockEventRepository()
+	mockRepo.events = cursorTestEvents()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	resp, err := usecase.GetEventList(testActor, &request.EventListRequest{PaginationRequest: request.PaginationRequest{Page: 2, Limit: 2}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.CursorPagination != nil || mockRepo.lastFilter.Cursor {
+		t.Error("Expected pages without a cursor")
+	}
+	if resp.Pagination.Page != 2 || resp.Pagination.Total != 5 || resp.Pagination.TotalPages != 3 {
+		t.Errorf("Expected page 2 of 3 with the total, got %+v", resp.Pagination)
+	}
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+func (u *eventUsecase) GetEventGraph(actor domain.Actor, id uint64) (*response.EventGraphResponse, error) {
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventGraph]: Error getting event")
+	}
+
+	if event == nil {
+		return nil, errors.Wrap(domain.ErrEventNotFound, "[EventUsecase.GetEventGraph]: Error getting event")
+	}
+
+	blockers, err := u.dependencyEdges(actor, []uint64{id}, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventGraph]: Error getting blockers")
+	}
+	dependents, err := u.dependencyEdges(actor, []uint64{id}, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventGraph]: Error getting dependents")
+	}
+
+	edges := append(blockers, dependents...)
+	ids := []uint64{id}
+	for _, edge := range edges {
+		ids = append(ids, edge.EventID, edge.BlockerID)
+	}
+	slices.Sort(ids)
+	events, err := u.eventRepository.GetEventsByIDs(actor, slices.Compact(ids))
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventGraph]: Error getting events")
+	}
+
+	// Events actor can't see, trashed ones included, are left out
+	graph := &response.EventGraphResponse{
+		Nodes: make([]*response.EventGraphNode, 0, len(events)),
+		Edges: make([]*response.EventGraphEdge, 0, len(edges)),
+	}
+	visible := make(map[uint64]bool, len(events))
+	for _, event := range events {
+		visible[event.ID] = true
+		graph.Nodes = append(graph.Nodes, &response.EventGraphNode{
+			ID:       event.ID,
+			Type:     event.Type,
+			Title:    event.Title,
+			Complete: event.Complete,
+		})
+	}
+	for _, edge := range edges {
+		if visible[edge.EventID] && visible[edge.BlockerID] {
+			graph.Edges = append(graph.Edges, &response.EventGraphEdge{EventID: edge.EventID, BlockerID: edge.BlockerID})
+		}
+	}
+	return graph, nil
+}
+
+// setBlockers makes the events ids the blockers of event. They must be
+// events actor can see other than event, and none may already wait on it.
+func (u *eventUsecase) setBlockers(actor domain.Actor, event *models.Events, ids []uint64) error {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if slices.Equal(ids, blockerIDs(event)) {
+		return nil
+	}
+	if event.ID != 0 && slices.Contains(ids, event.ID) {
+		return domain.NewValidationError("an event cannot block itself", response.FieldError{
+			Field: "blockedBy", Rule: "ne", Message: "blockedBy must not contain the event itself",
+		})
+	}
+
+	blockers, err := u.eventRepository.GetEventsByIDs(actor, ids)
+	if err != nil {
+		return err
+	}
+	if len(blockers) != len(ids) {
+		return domain.NewValidationError("blocking event not found", response.FieldError{
+			Field: "blockedBy", Rule: "exists", Message: "blockedBy must be events you can see",
+		})
+	}
+
+	// A new event has nothing waiting on it yet
+	if event.ID != 0 {
+		added := slices.DeleteFunc(slices.Clone(ids), func(id uint64) bool {
+			return slices.Contains(blockerIDs(event), id)
+		})
+		if err := u.checkDependencyCycle(actor, event.ID, added); err != nil {
+			return err
+		}
+	}
+
+	event.BlockedBy = blockers
+	return nil
+}
+
+// checkDependencyCycle fails when event id being blocked by the events
+// blockers would close a cycle, that is when one of them already waits on
+// it, naming the events along the way.
+func (u *eventUsecase) checkDependencyCycle(actor domain.Actor, id uint64, blockers []uint64) error {
+	edges, err := u.dependencyEdges(actor, blockers, true)
+	if err != nil {
+		return err
+	}
+
+	// dependent maps each event reached to the one it was reached from
+	dependent := make(map[uint64]uint64, len(edges))
+	for _, edge := range edges {
+		if _, seen := dependent[edge.BlockerID]; !seen && !slices.Contains(blockers, edge.BlockerID) {
+			dependent[edge.BlockerID] = edge.EventID
+		}
+	}
+	if _, found := dependent[id]; !found {
+		return nil
+	}
+
+	path := []string{fmt.Sprint(id)}
+	for next := dependent[id]; ; next = dependent[next] {
+		path = append(path, fmt.Sprint(next))
+		if slices.Contains(blockers, next) {
+			break
+		}
+	}
+	path = append(path, fmt.Sprint(id))
+	slices.Reverse(path)
+	message := fmt.Sprintf("blockedBy would form a cycle: event %s is blocked by %s",
+		path[0], strings.Join(path[1:], ", which is blocked by "))
+	return domain.NewValidationError(message, response.FieldError{
+		Field: "blockedBy", Rule: "acyclic", Message: message,
+	})
+}
+
+// dependencyEdges walks the dependencies from the events ids towards their
+// blockers when upstream, towards the events waiting on them otherwise, and
+// returns every dependency it follows.
+func (u *eventUsecase) dependencyEdges(actor domain.Actor, ids []uint64, upstream bool) ([]*models.EventDependencies, error) {
+	seen := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	var edges []*models.EventDependencies
+	for frontier := ids; len(frontier) > 0; {
+		dependencies, err := u.eventRepository.GetEventDependencies(actor, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		current := frontier
+		frontier = nil
+		for _, dependency := range dependencies {
+			from, to := dependency.EventID, dependency.BlockerID
+			if !upstream {
+				from, to = to, from
+			}
+			if !slices.Contains(current, from) {
+				continue
+			}
+			edges = append(edges, dependency)
+			if !seen[to] {
+				seen[to] = true
+				frontier = append(frontier, to)
+			}
+		}
+	}
+	return edges, nil
+}
+
+// checkBlocked fails with ErrEventBlocked when event is being completed
+// while some of its blockers aren't, unless it allows that.
+func checkBlocked(event *models.Events, wasComplete bool) error {
+	if !event.Complete || wasComplete || event.AllowBlockedCompletion || !blocked(event) {
+		return nil
+	}
+	return domain.ErrEventBlocked
+}
+
+// blocked reports whether some of the blockers of event aren't complete.
+func blocked(event *models.Events) bool {
+	return slices.ContainsFunc(event.BlockedBy, func(blocker *models.Events) bool { return !blocker.Complete })
+}
+
+// blockerIDs are the IDs of the blockers of event, in order; never nil.
+func blockerIDs(event *models.Events) []uint64 {
+	return dependencyIDs(event.BlockedBy)
+}
+
+func dependencyIDs(events []*models.Events) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
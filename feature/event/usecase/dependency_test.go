@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+)
+
+// Helper function to create events 1 to count, event i blocked by the
+// events blockers[i]
+func createTestDependencyRepository(count int, blockers map[uint64][]uint64) *mockEventRepository {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	for id := uint64(1); id <= uint64(count); id++ {
+		mockRepo.events = append(mockRepo.events, createTestEvent(id, "Step", "Description", "Office", false, startTime, endTime))
+	}
+	for id, ids := range blockers {
+		for _, blockerID := range ids {
+			mockRepo.dependencies = append(mockRepo.dependencies, &models.EventDependencies{EventID: id, BlockerID: blockerID})
+			event := mockRepo.events[id-1]
+			event.BlockedBy = append(event.BlockedBy, mockRepo.events[blockerID-1])
+		}
+	}
+	return mockRepo
+}
+
+func TestEventUsecase_UpdateEvent_Blockers(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             uint64
+		blockedBy      []uint64
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name:      "blocked by other events",
+			id:        1,
+			blockedBy: []uint64{4, 2, 4},
+		},
+		{
+			name:           "blocked by itself",
+			id:             1,
+			blockedBy:      []uint64{1},
+			expectedError:  true,
+			expectedErrMsg: "an event cannot block itself",
+		},
+		{
+			name:           "blocker not found",
+			id:             1,
+			blockedBy:      []uint64{9},
+			expectedError:  true,
+			expectedErrMsg: "blocking event not found",
+		},
+		{
+			name:           "cycle",
+			id:             3,
+			blockedBy:      []uint64{1},
+			expectedError:  true,
+			expectedErrMsg: "event 3 is blocked by 1, which is blocked by 2, which is blocked by 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 1 waits on 2, which waits on 3
+			mockRepo := createTestDependencyRepository(4, map[uint64][]uint64{1: {2}, 2: {3}})
+
+			startTime, endTime := getTestTimes()
+			req := createTestEventRequest("Step", "Description", "Office", false, startTime, endTime)
+			req.BlockedBy = tt.blockedBy
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.UpdateEvent(testActor, tt.id, 0, req)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if !slices.Equal(result.BlockedBy, []uint64{2, 4}) || !result.Blocked {
+				t.Errorf("Expected event blocked by [2 4], got %v blocked %v", result.BlockedBy, result.Blocked)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_UpdateEvent_CompleteBlocked(t *testing.T) {
+	tests := []struct {
+		name            string
+		blockerComplete bool
+		allow           bool
+		expectedError   error
+	}{
+		{
+			name:          "open blocker",
+			expectedError: domain.ErrEventBlocked,
+		},
+		{
+			name:            "complete blocker",
+			blockerComplete: true,
+		},
+		{
+			name:  "allowed while blocked",
+			allow: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := createTestDependencyRepository(2, map[uint64][]uint64{1: {2}})
+			mockRepo.events[1].Complete = tt.blockerComplete
+
+			startTime, endTime := getTestTimes()
+			req := createTestEventRequest("Step", "Description", "Office", true, startTime, endTime)
+			req.BlockedBy = []uint64{2}
+			req.AllowBlockedCompletion = tt.allow
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.UpdateEvent(testActor, 1, 0, req)
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected error %v, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+			if !*result.Complete {
+				t.Error("Expected the event complete")
+			}
+		})
+	}
+}
+
+func TestEventUsecase_PatchEvent_Blockers(t *testing.T) {
+	mockRepo := createTestDependencyRepository(3, map[uint64][]uint64{1: {2}})
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.PatchEvent(testActor, 1, 0, "application/merge-patch+json", []byte(`{"blockedBy":[3,2]}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !slices.Equal(result.BlockedBy, []uint64{2, 3}) || !slices.Equal(mockRepo.lastColumns, []string{"blocked_by"}) {
+		t.Errorf("Expected blockers [2 3] saved, got %v with columns %v", result.BlockedBy, mockRepo.lastColumns)
+	}
+
+	_, err = usecase.PatchEvent(testActor, 1, 0, "application/merge-patch+json", []byte(`{"complete":true}`))
+	if !errors.Is(err, domain.ErrEventBlocked) {
+		t.Errorf("Expected completing a blocked event to fail, got: %v", err)
+	}
+}
+
+func TestEventUsecase_GetEventGraph(t *testing.T) {
+	// 2 waits on 1, 3 on 2, 4 on 3 and 5 on 1
+	mockRepo := createTestDependencyRepository(5, map[uint64][]uint64{2: {1}, 3: {2}, 4: {3}, 5: {1}})
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	graph, err := usecase.GetEventGraph(testActor, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var nodes []uint64
+	for _, node := range graph.Nodes {
+		nodes = append(nodes, node.ID)
+	}
+	if !slices.Equal(nodes, []uint64{1, 2, 3, 4}) {
+		t.Errorf("Expected nodes [1 2 3 4], got %v", nodes)
+	}
+	if len(graph.Edges) != 3 {
+		t.Errorf("Expected 3 edges, got %d", len(graph.Edges))
+	}
+	for _, edge := range graph.Edges {
+		if edge.EventID == 5 {
+			t.Errorf("Expected event 5, which doesn't depend on 3, left out, got %+v", edge)
+		}
+	}
+
+	if _, err := usecase.GetEventGraph(testActor, 9); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("Expected event not found, got: %v", err)
+	}
+}
+
+func TestEventUsecase_PatchEvent_CompleteBlocker(t *testing.T) {
+	mockRepo := createTestDependencyRepository(3, map[uint64][]uint64{1: {2}})
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	before, err := usecase.GetEventByID(testActor, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	for _, patch := range []string{`{"complete":true}`, `{"complete":false}`} {
+		if _, err := usecase.PatchEvent(testActor, 2, 0, "application/merge-patch+json", []byte(patch)); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		// The event waiting on it shows whether it is blocked, so it changes
+		// with it
+		after, err := usecase.GetEventByID(testActor, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if after.Version == before.Version || after.Blocked == before.Blocked {
+			t.Errorf("Expected the dependent changed by %s, got version %d blocked %v", patch, after.Version, after.Blocked)
+		}
+		last := mockRepo.outbox[len(mockRepo.outbox)-1]
+		if last.EventID != 1 || last.Type != constant.WebhookEventUpdated || last.Version != after.Version {
+			t.Errorf("Expected the dependent published at version %d, got %+v", after.Version, last)
+		}
+		before = after
+	}
+
+	// Other writes leave it alone
+	if _, err := usecase.PatchEvent(testActor, 2, 0, "application/merge-patch+json", []byte(`{"title":"Review"}`)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if last := mockRepo.outbox[len(mockRepo.outbox)-1]; last.EventID != 2 {
+		t.Errorf("Expected only the blocker published, got %+v", last)
+	}
+}
@@ -0,0 +1,547 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	log "github.com/sirupsen/logrus"
+	"github.com/teambition/rrule-go"
+)
+
+const (
+	icalProductName = "g12-todo"
+	// icalCompleteProperty carries Complete, which VEVENT has no property for
+	icalCompleteProperty = ics.ComponentProperty("X-G12-COMPLETE")
+	// Tasks are exported as VEVENTs too, marked by icalTypeProperty, with
+	// their due date in icalDueProperty since DUE is VTODO only
+	icalTypeProperty = ics.ComponentProperty("X-G12-TYPE")
+	icalDueProperty  = ics.ComponentProperty("X-G12-DUE")
+)
+
+// icalLocalTimeFormat is a DATE-TIME in the zone of its TZID.
+const icalLocalTimeFormat = "20060102T150405"
+
+// icalPriorities maps priority levels to PRIORITY values, where 1 is the
+// highest and 0 undefined.
+var icalPriorities = []int{0, 7, 5, 3, 1}
+
+func (u *eventUsecase) ExportEvents(actor domain.Actor, req *request.EventListRequest) (*response.EventExportResponse, error) {
+	filter, err := newEventFilter(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.ExportEvents]: Invalid filter")
+	}
+	// Recurring events are exported as their series, so fetch them unexpanded
+	filter.Expand = true
+
+	events, _, err := u.eventRepository.GetEventList(actor, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.ExportEvents]: Error getting events")
+	}
+
+	var recurringIDs []uint64
+	for _, event := range events {
+		if event.RRule != nil {
+			recurringIDs = append(recurringIDs, event.ID)
+		}
+	}
+	overrides := make(map[uint64][]*models.EventExceptions)
+	if len(recurringIDs) > 0 {
+		exceptions, err := u.eventRepository.GetEventExceptions(actor, recurringIDs)
+		if err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.ExportEvents]: Error getting event exceptions")
+		}
+		for _, exception := range exceptions {
+			overrides[exception.EventID] = append(overrides[exception.EventID], exception)
+		}
+	}
+
+	calendar := ics.NewCalendarFor(icalProductName)
+	calendar.SetMethod(ics.MethodPublish)
+	export := &response.EventExportResponse{}
+	for _, event := range events {
+		eventResponse := newEventResponse(event)
+		// The repository lets every series through the attribute filters
+		if event.RRule != nil && !matchesEventFilter(eventResponse, filter) {
+			continue
+		}
+		calendar.AddVEvent(newVEvent(event, eventResponse, event.UpdatedAt))
+		export.LastModified = latestTime(export.LastModified, event.UpdatedAt)
+		for _, exception := range overrides[event.ID] {
+			occurrence := newOccurrenceResponse(event, exception.OccurrenceStart, exception)
+			calendar.AddVEvent(newVEvent(event, occurrence, exception.UpdatedAt))
+			export.LastModified = latestTime(export.LastModified, exception.UpdatedAt)
+		}
+	}
+
+	// Deleted events are gone from the list but not from the outbox
+	changedAt, err := u.eventRepository.LastEventChangeAt(actor)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.ExportEvents]: Error getting last event change")
+	}
+	export.LastModified = latestTime(export.LastModified, changedAt)
+
+	export.Calendar = []byte(calendar.Serialize())
+	return export, nil
+}
+
+// newVEvent renders event, or one overridden occurrence of it, as a VEVENT.
+// DTSTAMP is the last change, rather than now, so unchanged events render
+// the same every time and feeds can be cached by content.
+func newVEvent(event *models.Events, eventResponse *response.EventResponse, modifiedAt *time.Time) *ics.VEvent {
+	vevent := ics.NewEvent(eventUID(event))
+	if modifiedAt == nil {
+		now := time.Now()
+		modifiedAt = &now
+	}
+	vevent.SetDtStampTime(*modifiedAt)
+	vevent.SetModifiedAt(*modifiedAt)
+	if event.CreatedAt != nil {
+		vevent.SetCreatedTime(*event.CreatedAt)
+	}
+	vevent.SetSequence(int(event.Version - 1))
+	if location := seriesLocation(event); eventResponse.StartTime != nil && event.RRule != nil &&
+		eventResponse.OccurrenceStart == nil && location != time.UTC {
+		// The series recurs in its zone, so its window is given in it
+		tzid := ics.WithTZID(location.String())
+		vevent.SetProperty(ics.ComponentPropertyDtStart, eventResponse.StartTime.In(location).Format(icalLocalTimeFormat), tzid)
+		vevent.SetProperty(ics.ComponentPropertyDtEnd, eventResponse.EndTime.In(location).Format(icalLocalTimeFormat), tzid)
+	} else if eventResponse.StartTime != nil {
+		vevent.SetStartAt(*eventResponse.StartTime)
+		vevent.SetEndAt(*eventResponse.EndTime)
+	}
+	vevent.SetSummary(eventResponse.Title)
+	if eventResponse.Description != nil && *eventResponse.Description != "" {
+		vevent.SetDescription(*eventResponse.Description)
+	}
+	if eventResponse.Location != "" {
+		vevent.SetLocation(eventResponse.Location)
+	}
+	for _, tag := range eventResponse.Tags {
+		vevent.AddCategory(tag)
+	}
+	vevent.SetProperty(icalCompleteProperty, strings.ToUpper(strconv.FormatBool(*eventResponse.Complete)))
+	if eventResponse.Type == constant.EventTypeTask {
+		vevent.SetProperty(icalTypeProperty, strings.ToUpper(constant.EventTypeTask))
+	}
+	if eventResponse.DueAt != nil {
+		vevent.SetProperty(icalDueProperty, formatICalTime(*eventResponse.DueAt))
+	}
+	if level := priorityLevel(eventResponse.Priority); level > 0 {
+		vevent.SetPriority(icalPriorities[level])
+	}
+
+	if eventResponse.OccurrenceStart != nil {
+		vevent.SetProperty(ics.ComponentPropertyRecurrenceId, formatICalTime(*eventResponse.OccurrenceStart))
+		return vevent
+	}
+	if event.RRule != nil {
+		vevent.AddRrule(exportRRule(event))
+		for _, exDate := range event.ExDates {
+			vevent.AddExdate(formatICalTime(exDate))
+		}
+	}
+	return vevent
+}
+
+// eventUID falls back to an id based UID for events created before UIDs
+// were stored.
+func eventUID(event *models.Events) string {
+	if event.UID != nil {
+		return *event.UID
+	}
+	return fmt.Sprintf("event-%d@%s", event.ID, icalProductName)
+}
+
+// exportRRule folds RecurrenceEnd, which may have been set apart from the
+// rule when the series was split, back into the rule as UNTIL.
+func exportRRule(event *models.Events) string {
+	option, err := rrule.StrToROption(*event.RRule)
+	if err != nil || event.RecurrenceEnd == nil {
+		return *event.RRule
+	}
+	if option.Until.IsZero() || event.RecurrenceEnd.Before(option.Until) {
+		option.Count = 0
+		option.Until = event.RecurrenceEnd.UTC()
+	}
+	return option.RRuleString()
+}
+
+func latestTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func (u *eventUsecase) ImportEvents(actor domain.Actor, r io.Reader) (*response.EventImportResponse, error) {
+	calendar, err := ics.ParseCalendar(r)
+	if err != nil {
+		message := "calendar is invalid: " + err.Error()
+		return nil, errors.Wrap(domain.NewValidationError(message, response.FieldError{
+			Field: "file", Rule: "ical", Message: message,
+		}), "[EventUsecase.ImportEvents]: Error parsing calendar")
+	}
+
+	vevents := calendar.Events()
+	results := make([]*response.EventImportResult, len(vevents))
+
+	// Series go first, since overrides of their occurrences may precede
+	// them in the file. series keeps the outcome of each UID, preferring
+	// the one that was created.
+	series := make(map[string]*response.EventImportResult)
+	for i, vevent := range vevents {
+		if vevent.HasProperty(ics.ComponentPropertyRecurrenceId) {
+			continue
+		}
+		results[i] = u.importEvent(actor, vevent, series[vevent.Id()])
+		results[i].Index = i + 1
+		if uid := results[i].UID; uid != "" && (series[uid] == nil || results[i].Status == constant.ImportCreated) {
+			series[uid] = results[i]
+		}
+	}
+	for i, vevent := range vevents {
+		if results[i] != nil {
+			continue
+		}
+		results[i] = u.importOccurrence(actor, vevent, series[vevent.Id()])
+		results[i].Index = i + 1
+	}
+
+	resp := &response.EventImportResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case constant.ImportCreated:
+			resp.Created++
+		case constant.ImportSkipped:
+			resp.Skipped++
+		default:
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+// importEvent creates the event of a VEVENT. previous is the outcome of an
+// earlier VEVENT with the same UID, if any.
+func (u *eventUsecase) importEvent(actor domain.Actor, vevent *ics.VEvent, previous *response.EventImportResult) *response.EventImportResult {
+	result := &response.EventImportResult{UID: vevent.Id()}
+	if previous != nil && previous.Status == constant.ImportCreated {
+		return skipImport(result, "duplicate uid in file")
+	}
+	if isCancelled(vevent) {
+		return skipImport(result, "event is cancelled")
+	}
+
+	req, err := newImportRequest(vevent)
+	if err != nil {
+		return failImport(result, err)
+	}
+	event, err := u.createEvent(actor, req, true)
+	if errors.Is(err, domain.ErrEventUIDConflict) {
+		return skipImport(result, "event already exists")
+	}
+	if err != nil {
+		return failImport(result, errors.Wrap(err, "[EventUsecase.ImportEvents]: Error creating event"))
+	}
+
+	result.Status = constant.ImportCreated
+	result.EventID = &event.ID
+	return result
+}
+
+// importOccurrence applies a VEVENT overriding one occurrence of a series
+// to the series created from the file.
+func (u *eventUsecase) importOccurrence(actor domain.Actor, vevent *ics.VEvent, series *response.EventImportResult) *response.EventImportResult {
+	result := &response.EventImportResult{UID: vevent.Id()}
+	if series == nil || series.Status != constant.ImportCreated {
+		message := "series of this occurrence was not imported"
+		if series != nil && series.Status == constant.ImportSkipped {
+			return skipImport(result, message)
+		}
+		return failImport(result, domain.NewValidationError(message))
+	}
+	result.EventID = series.EventID
+
+	occurrenceStart, _, err := parseICalTime(vevent.GetProperty(ics.ComponentPropertyRecurrenceId), "occurrence")
+	if err != nil {
+		return failImport(result, err)
+	}
+	occurrence := &request.OccurrenceRequest{Scope: "this", Occurrence: &occurrenceStart}
+
+	if isCancelled(vevent) {
+		if err := u.DeleteOccurrence(actor, *series.EventID, 0, occurrence); err != nil {
+			return failImport(result, errors.Wrap(err, "[EventUsecase.ImportEvents]: Error deleting occurrence"))
+		}
+		result.Status = constant.ImportCreated
+		result.Message = "occurrence cancelled"
+		return result
+	}
+
+	req, err := newImportRequest(vevent)
+	if err != nil {
+		return failImport(result, err)
+	}
+	req.RRule = ""
+	req.ExDates = nil
+	if _, err := u.updateOccurrence(actor, *series.EventID, 0, occurrence, req, true); err != nil {
+		return failImport(result, errors.Wrap(err, "[EventUsecase.ImportEvents]: Error updating occurrence"))
+	}
+	result.Status = constant.ImportCreated
+	return result
+}
+
+func skipImport(result *response.EventImportResult, message string) *response.EventImportResult {
+	result.Status = constant.ImportSkipped
+	result.Message = message
+	return result
+}
+
+// failImport records err on result. Only business errors are shown to the
+// client; anything else is logged and reported generically.
+func failImport(result *response.EventImportResult, err error) *response.EventImportResult {
+	result.Status = constant.ImportFailed
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		result.Message = domainErr.Message
+		result.Errors = domainErr.Fields
+		return result
+	}
+	log.Error(err)
+	result.Message = "internal error"
+	return result
+}
+
+func isCancelled(vevent *ics.VEvent) bool {
+	status := vevent.GetProperty(ics.ComponentPropertyStatus)
+	return status != nil && strings.EqualFold(status.Value, string(ics.ObjectStatusCancelled))
+}
+
+// newImportRequest maps a VEVENT onto an EventRequest and validates it
+// like the create endpoint does.
+func newImportRequest(vevent *ics.VEvent) (*request.EventRequest, error) {
+	complete := false
+	if property := vevent.GetProperty(icalCompleteProperty); property != nil {
+		complete = strings.EqualFold(property.Value, "TRUE")
+	}
+
+	req := &request.EventRequest{
+		Type:     constant.EventTypeEvent,
+		UID:      vevent.Id(),
+		Title:    icalText(vevent, ics.ComponentPropertySummary),
+		Location: icalText(vevent, ics.ComponentPropertyLocation),
+		Complete: &complete,
+		Priority: importPriority(vevent),
+
+		Description: icalText(vevent, ics.ComponentPropertyDescription),
+	}
+	if strings.EqualFold(icalText(vevent, icalTypeProperty), constant.EventTypeTask) {
+		req.Type = constant.EventTypeTask
+	}
+
+	// Tasks may have no window
+	if req.Type == constant.EventTypeEvent || vevent.HasProperty(ics.ComponentPropertyDtStart) {
+		startTime, endTime, err := parseICalWindow(vevent)
+		if err != nil {
+			return nil, err
+		}
+		req.StartTime, req.EndTime = &startTime, &endTime
+		// A series recurs in the zone of its start
+		if tzid := vevent.GetProperty(ics.ComponentPropertyDtStart).ICalParameters[string(ics.ParameterTzid)]; len(tzid) > 0 {
+			req.Timezone = tzid[0]
+		}
+	}
+	if property := vevent.GetProperty(icalDueProperty); property != nil {
+		dueAt, _, err := parseICalTime(property, "dueAt")
+		if err != nil {
+			return nil, err
+		}
+		req.DueAt = &dueAt
+	}
+
+	// Categories are the event's tags
+	for _, property := range vevent.GetProperties(ics.ComponentPropertyCategories) {
+		for _, category := range strings.Split(property.Value, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				req.Tags = append(req.Tags, category)
+			}
+		}
+	}
+
+	if vevent.HasProperty(ics.ComponentPropertyRdate) {
+		return nil, invalidICal("rrule", "RDATE is not supported")
+	}
+	if property := vevent.GetProperty(ics.ComponentPropertyRrule); property != nil {
+		req.RRule = property.Value
+	}
+	for _, property := range vevent.GetProperties(ics.ComponentPropertyExdate) {
+		location, err := icalLocation(property, "exdates")
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range strings.Split(property.Value, ",") {
+			exDate, _, err := parseICalTimeValue(value, location, "exdates")
+			if err != nil {
+				return nil, err
+			}
+			req.ExDates = append(req.ExDates, exDate)
+		}
+	}
+
+	if err := validateEventRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// parseICalWindow reads the start and end of a VEVENT.
+func parseICalWindow(vevent *ics.VEvent) (time.Time, time.Time, error) {
+	startTime, allDay, err := parseICalTime(vevent.GetProperty(ics.ComponentPropertyDtStart), "startTime")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	// Without DTEND or DURATION an event lasts a day if it is all-day and
+	// no time at all otherwise (RFC 5545 section 3.6.1).
+	endTime := startTime
+	if allDay {
+		endTime = startTime.AddDate(0, 0, 1)
+	}
+	if property := vevent.GetProperty(ics.ComponentPropertyDtEnd); property != nil {
+		if endTime, _, err = parseICalTime(property, "endTime"); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	} else if property := vevent.GetProperty(ics.ComponentPropertyDuration); property != nil {
+		duration, err := parseICalDuration(property.Value)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		endTime = startTime.Add(duration)
+	}
+
+	return startTime, endTime, nil
+}
+
+// importPriority maps the PRIORITY of vevent onto a priority name: 1-2 is
+// urgent, 3-4 high, 5 medium and 6-9 low.
+func importPriority(vevent *ics.VEvent) string {
+	value, err := strconv.Atoi(icalText(vevent, ics.ComponentPropertyPriority))
+	if err != nil || value <= 0 || value > 9 {
+		return ""
+	}
+	switch {
+	case value <= 2:
+		return "urgent"
+	case value <= 4:
+		return "high"
+	case value == 5:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func icalText(vevent *ics.VEvent, componentProperty ics.ComponentProperty) string {
+	if property := vevent.GetProperty(componentProperty); property != nil {
+		return strings.TrimSpace(property.Value)
+	}
+	return ""
+}
+
+func invalidICal(field, message string) error {
+	return domain.NewValidationError(message, response.FieldError{
+		Field: field, Rule: "ical", Message: message,
+	})
+}
+
+// parseICalTime reads a DATE or DATE-TIME property and reports whether it
+// was a DATE. Floating times, without a TZID, are taken as UTC.
+func parseICalTime(property *ics.IANAProperty, field string) (time.Time, bool, error) {
+	if property == nil {
+		return time.Time{}, false, invalidICal(field, field+" is required")
+	}
+	location, err := icalLocation(property, field)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return parseICalTimeValue(property.Value, location, field)
+}
+
+func icalLocation(property *ics.IANAProperty, field string) (*time.Location, error) {
+	tzid := property.ICalParameters[string(ics.ParameterTzid)]
+	if len(tzid) == 0 {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(tzid[0])
+	if err != nil {
+		return nil, invalidICal(field, fmt.Sprintf("%s has unknown time zone %q", field, tzid[0]))
+	}
+	return location, nil
+}
+
+func parseICalTimeValue(value string, location *time.Location, field string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	var (
+		t   time.Time
+		err error
+	)
+	switch len(value) {
+	case len("20060102T150405Z"):
+		t, err = time.Parse("20060102T150405Z", value)
+	case len(icalLocalTimeFormat):
+		t, err = time.ParseInLocation(icalLocalTimeFormat, value, location)
+	case len("20060102"):
+		t, err = time.ParseInLocation("20060102", value, location)
+		if err == nil {
+			return t, true, nil
+		}
+	default:
+		err = errors.New("unknown format")
+	}
+	if err != nil {
+		return time.Time{}, false, invalidICal(field, fmt.Sprintf("%s has invalid time %q", field, value))
+	}
+	return t, false, nil
+}
+
+var icalDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICalDuration parses an RFC 5545 DURATION such as "PT1H30M" or "P1D".
+// Days are taken as 24 hours.
+func parseICalDuration(value string) (time.Duration, error) {
+	match := icalDurationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, invalidICal("endTime", fmt.Sprintf("duration %q is invalid", value))
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, invalidICal("endTime", fmt.Sprintf("duration %q is invalid", value))
+		}
+		duration += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
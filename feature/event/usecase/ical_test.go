@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+func TestEventUsecase_ExportEvents(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	single := createTestEvent(1, "Meeting; weekly, sync", "Line one\nLine two", "Room A", true, startTime, endTime)
+	single.UID = stringPtr("meeting@example.com")
+
+	series := createTestSeries(2, "FREQ=WEEKLY;COUNT=10")
+	recurrenceEnd := time.Date(2024, 1, 22, 9, 0, 0, 0, time.UTC)
+	series.RecurrenceEnd = &recurrenceEnd
+	series.ExDates = []time.Time{time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)}
+
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{single, series}
+	mockRepo.exceptions = []*models.EventExceptions{{
+		EventID:         2,
+		OccurrenceStart: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		Title:           stringPtr("Planning"),
+	}}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	export, err := usecase.ExportEvents(testActor, &request.EventListRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !mockRepo.lastFilter.Expand {
+		t.Error("Expected the export to fetch series unexpanded")
+	}
+
+	if export.LastModified == nil || !export.LastModified.Equal(*series.UpdatedAt) {
+		t.Errorf("Expected last modified %v, got %v", series.UpdatedAt, export.LastModified)
+	}
+
+	output := strings.ReplaceAll(string(export.Calendar), "\r\n ", "")
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR",
+		"METHOD:PUBLISH",
+		"UID:meeting@example.com",
+		`SUMMARY:Meeting\; weekly\, sync`,
+		`DESCRIPTION:Line one\nLine two`,
+		"DTSTART:20240101T100000Z",
+		"DTEND:20240101T110000Z",
+		"X-G12-COMPLETE:TRUE",
+		"UID:event-2@g12-todo",
+		"RRULE:FREQ=WEEKLY;UNTIL=20240122T090000Z",
+		"EXDATE:20240108T090000Z",
+		"RECURRENCE-ID:20240115T090000Z",
+		"SUMMARY:Planning",
+		"END:VCALENDAR",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected calendar to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestEventUsecase_ExportEvents_Deleted(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	kept := createTestEvent(1, "Kept", "", "Office", false, startTime, endTime)
+	deleted := createTestEvent(2, "Deleted", "", "Office", false, startTime, endTime)
+
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{kept, deleted}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	before, err := usecase.ExportEvents(testActor, &request.EventListRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := usecase.DeleteEvent(testActor, 2, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// A poll after the deletion must not look unmodified
+	after, err := usecase.ExportEvents(testActor, &request.EventListRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Contains(string(after.Calendar), "SUMMARY:Deleted") {
+		t.Errorf("Expected the deleted event left out, got:\n%s", after.Calendar)
+	}
+	if after.LastModified == nil || !after.LastModified.After(*before.LastModified) {
+		t.Errorf("Expected the deletion to move last modified past %v, got %v", before.LastModified, after.LastModified)
+	}
+}
+
+func TestEventUsecase_ImportEvents(t *testing.T) {
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Google Inc//Google Calendar 70.9054//EN",
+		// Override listed before its series
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID:20240108T090000Z",
+		"DTSTART:20240108T100000Z",
+		"DTEND:20240108T101500Z",
+		"SUMMARY:Late standup",
+		"LOCATION:Office",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID:20240115T090000Z",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"DTSTART;TZID=Europe/London:20240101T090000",
+		"DURATION:PT15M",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"EXDATE:20240122T090000Z",
+		`SUMMARY:Standup\, daily`,
+		"LOCATION:Office",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"DTSTART:20240101T090000Z",
+		"DTEND:20240101T091500Z",
+		"SUMMARY:Standup again",
+		"LOCATION:Office",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:holiday@example.com",
+		"DTSTART;VALUE=DATE:20240101",
+		"SUMMARY:New Year",
+		"LOCATION:Home",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:existing@example.com",
+		"DTSTART:20240101T090000Z",
+		"DTEND:20240101T100000Z",
+		"SUMMARY:Existing",
+		"LOCATION:Office",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:nowhere@example.com",
+		"DTSTART:20240101T090000Z",
+		"DTEND:20240101T100000Z",
+		"SUMMARY:No location",
+		"END:VEVENT",
+		// Timed without DTEND or DURATION, so it ends as it starts
+		"BEGIN:VEVENT",
+		"UID:deadline@example.com",
+		"DTSTART:20240105T170000Z",
+		"SUMMARY:Deadline",
+		"LOCATION:Office",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:backwards@example.com",
+		"DTSTART:20240101T100000Z",
+		"DTEND:20240101T090000Z",
+		"SUMMARY:Backwards",
+		"LOCATION:Office",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	existing := createTestEvent(1, "Existing", "", "Office", false,
+		time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	existing.UID = stringPtr("existing@example.com")
+
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{existing}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.ImportEvents(testActor, strings.NewReader(calendar))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []struct {
+		status  string
+		message string
+		field   string
+	}{
+		{status: constant.ImportCreated},
+		{status: constant.ImportCreated, message: "occurrence cancelled"},
+		{status: constant.ImportCreated},
+		{status: constant.ImportSkipped, message: "duplicate uid in file"},
+		{status: constant.ImportCreated},
+		{status: constant.ImportSkipped, message: "event already exists"},
+		{status: constant.ImportCreated},
+		{status: constant.ImportCreated},
+		{status: constant.ImportFailed, field: "endTime"},
+	}
+	if len(result.Results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(result.Results))
+	}
+	for i, want := range expected {
+		got := result.Results[i]
+		if got.Index != i+1 || got.Status != want.status || got.Message != want.message && want.message != "" {
+			t.Errorf("Result %d: expected %s %q, got %+v", i+1, want.status, want.message, got)
+		}
+		if want.field != "" && (len(got.Errors) == 0 || got.Errors[0].Field != want.field) {
+			t.Errorf("Result %d: expected an error on %s, got %+v", i+1, want.field, got.Errors)
+		}
+	}
+	if result.Created != 6 || result.Skipped != 2 || result.Failed != 1 {
+		t.Errorf("Expected 6 created, 2 skipped and 1 failed, got %d, %d and %d", result.Created, result.Skipped, result.Failed)
+	}
+
+	series := mockRepo.events[1]
+	if series.Title != "Standup, daily" || series.UID == nil || *series.UID != "standup@example.com" {
+		t.Errorf("Expected the imported series, got %+v", series)
+	}
+	if !series.StartTime.Equal(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)) || series.EndTime.Sub(*series.StartTime) != 15*time.Minute {
+		t.Errorf("Expected the series to run 09:00-09:15 UTC, got %v-%v", series.StartTime, series.EndTime)
+	}
+	if series.Timezone == nil || *series.Timezone != "Europe/London" {
+		t.Errorf("Expected the series to recur in the zone of its DTSTART, got %v", series.Timezone)
+	}
+	if len(series.ExDates) != 2 {
+		t.Errorf("Expected the exdate and the cancelled occurrence excluded, got %v", series.ExDates)
+	}
+	if len(mockRepo.exceptions) != 1 || *mockRepo.exceptions[0].Title != "Late standup" {
+		t.Errorf("Expected the override saved as an exception, got %+v", mockRepo.exceptions)
+	}
+
+	holiday := mockRepo.events[2]
+	if holiday.EndTime.Sub(*holiday.StartTime) != 24*time.Hour {
+		t.Errorf("Expected an all-day event to last a day, got %v-%v", holiday.StartTime, holiday.EndTime)
+	}
+
+	if nowhere := mockRepo.events[3]; nowhere.Title != "No location" || nowhere.Location != "" {
+		t.Errorf("Expected an event without a location, got %+v", nowhere)
+	}
+	if deadline := mockRepo.events[4]; deadline.Title != "Deadline" || !deadline.EndTime.Equal(*deadline.StartTime) {
+		t.Errorf("Expected an event ending as it starts, got %v-%v", deadline.StartTime, deadline.EndTime)
+	}
+
+	// and is exported in it
+	export, err := usecase.ExportEvents(testActor, &request.EventListRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if output := string(export.Calendar); !strings.Contains(output, "DTSTART;TZID=Europe/London:20240101T090000") {
+		t.Errorf("Expected the series start in its zone, got:\n%s", output)
+	}
+}
+
+func TestEventUsecase_ImportEvents_InvalidCalendar(t *testing.T) {
+	usecase := NewEventUsecase(newMockEventRepository(), &mockCalendarRepository{})
+	_, err := usecase.ImportEvents(testActor, strings.NewReader("not a calendar"))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "calendar is invalid") {
+		t.Errorf("Expected error message to contain 'calendar is invalid', got: %v", err)
+	}
+}
+
+func TestParseICalDuration(t *testing.T) {
+	tests := []struct {
+		value         string
+		expected      time.Duration
+		expectedError bool
+	}{
+		{value: "PT15M", expected: 15 * time.Minute},
+		{value: "PT1H30M", expected: 90 * time.Minute},
+		{value: "P1DT2H", expected: 26 * time.Hour},
+		{value: "P1W", expected: 7 * 24 * time.Hour},
+		{value: "-PT5M", expected: -5 * time.Minute},
+		{value: "P", expectedError: true},
+		{value: "PT", expectedError: true},
+		{value: "1H", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			duration, err := parseICalDuration(tt.value)
+			if tt.expectedError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", duration)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+			if duration != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, duration)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_ICalTasks(t *testing.T) {
+	dueAt := time.Date(2024, 1, 5, 17, 0, 0, 0, time.UTC)
+	mockRepo := newMockEventRepository()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	if _, err := usecase.CreateEvent(testActor, createTestTaskRequest("Finish report", &dueAt, "high")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	export, err := usecase.ExportEvents(testActor, &request.EventListRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	output := string(export.Calendar)
+	for _, expected := range []string{"X-G12-TYPE:TASK", "X-G12-DUE:20240105T170000Z", "PRIORITY:3"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected calendar to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "DTSTART") {
+		t.Errorf("Expected a task without a window to have no DTSTART, got:\n%s", output)
+	}
+
+	// Importing the export brings the task back
+	imported := newMockEventRepository()
+	result, err := NewEventUsecase(imported, &mockCalendarRepository{}).ImportEvents(testActor, strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Created != 1 || len(imported.events) != 1 {
+		t.Fatalf("Expected the task imported, got %+v", result.Results[0])
+	}
+	task := imported.events[0]
+	if task.Type != constant.EventTypeTask || task.StartTime != nil || task.DueAt == nil || !task.DueAt.Equal(dueAt) || priorityName(task.Priority) != "high" {
+		t.Errorf("Expected the high priority task due %v, got %+v", dueAt, task)
+	}
+}
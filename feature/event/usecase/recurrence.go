@@ -0,0 +1,535 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/teambition/rrule-go"
+)
+
+// maxExpandedOccurrences bounds how many occurrences a single list request
+// may expand, so an hourly rule over a wide window can't exhaust memory.
+const maxExpandedOccurrences = 5000
+
+// newRecurrence validates an RRULE against the event start and returns the
+// values to store: the rule, nil for single events, and the start of the
+// last occurrence, nil while the series is unbounded. recurrenceEnd, the
+// COUNT and the UNTIL of the rule all bound the series, which recurs in
+// location.
+func newRecurrence(rule string, startTime time.Time, recurrenceEnd *time.Time, location *time.Location) (*string, *time.Time, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, nil, nil
+	}
+
+	invalid := func(message string) error {
+		return domain.NewValidationError(message, response.FieldError{
+			Field: "rrule", Rule: "rrule", Message: message,
+		})
+	}
+
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, nil, invalid("rrule is invalid: " + err.Error())
+	}
+	if !option.Dtstart.IsZero() {
+		return nil, nil, invalid("rrule must not contain DTSTART, startTime anchors the series")
+	}
+	if option.Count > maxExpandedOccurrences {
+		return nil, nil, invalid(fmt.Sprintf("rrule COUNT must be at most %d", maxExpandedOccurrences))
+	}
+
+	option.Dtstart = startTime.In(location)
+	recurrence, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, nil, invalid("rrule is invalid: " + err.Error())
+	}
+
+	end := recurrenceEnd
+	if !option.Until.IsZero() && (end == nil || option.Until.Before(*end)) {
+		until := option.Until
+		end = &until
+	}
+	if option.Count > 0 {
+		occurrences := recurrence.All()
+		if len(occurrences) > 0 {
+			last := occurrences[len(occurrences)-1].UTC()
+			if end == nil || last.Before(*end) {
+				end = &last
+			}
+		}
+	}
+
+	if end != nil && end.Before(startTime) {
+		return nil, nil, domain.NewValidationError("recurrenceEnd must not be before startTime", response.FieldError{
+			Field: "recurrenceEnd", Rule: "gtefield", Message: "recurrenceEnd must not be before startTime",
+		})
+	}
+
+	return &rule, end, nil
+}
+
+// setRecurrence validates the recurrence fields of req and stores them on
+// event, the series recurring in timezone. Single events keep no exception
+// dates or zone; a series needs a start to anchor it, so untimed tasks
+// don't recur.
+func setRecurrence(event *models.Events, req *request.EventRequest, timezone string) error {
+	if req.StartTime == nil {
+		if strings.TrimSpace(req.RRule) != "" {
+			return domain.NewValidationError("rrule requires startTime", response.FieldError{
+				Field: "rrule", Rule: "required_with", Message: "rrule requires startTime to anchor the series",
+			})
+		}
+		event.RRule, event.RecurrenceEnd, event.ExDates, event.Timezone = nil, nil, nil, nil
+		return nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		message := fmt.Sprintf("timezone %q is unknown", timezone)
+		return domain.NewValidationError(message, response.FieldError{
+			Field: "timezone", Rule: "timezone", Message: message,
+		})
+	}
+	rule, recurrenceEnd, err := newRecurrence(req.RRule, *req.StartTime, req.RecurrenceEnd, location)
+	if err != nil {
+		return err
+	}
+
+	event.RRule = rule
+	event.RecurrenceEnd = recurrenceEnd
+	event.ExDates = nil
+	event.Timezone = nil
+	if rule != nil {
+		event.ExDates = req.ExDates
+		if location != time.UTC {
+			name := location.String()
+			event.Timezone = &name
+		}
+	}
+	return nil
+}
+
+// recurrenceTimezone is the zone the series of req recurs in: the one it
+// names, or else the one of its calendar, or else UTC, as "".
+func (u *eventUsecase) recurrenceTimezone(actor domain.Actor, req *request.EventRequest) (string, error) {
+	if req.Timezone != "" || req.CalendarID == nil || strings.TrimSpace(req.RRule) == "" {
+		return req.Timezone, nil
+	}
+	calendar, err := u.calendarRepository.GetCalendarByID(actor, *req.CalendarID)
+	if err != nil {
+		return "", err
+	}
+	return calendar.Timezone, nil
+}
+
+// seriesLocation is the location the series of event recurs in.
+func seriesLocation(event *models.Events) *time.Location {
+	if event.Timezone == nil {
+		return time.UTC
+	}
+	// Stored zones were valid when they were saved
+	location, err := time.LoadLocation(*event.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// newRecurrenceSet builds the occurrence set of a recurring event, expanded
+// in its zone so BYDAY and the like fall on its local days and occurrences
+// keep their wall-clock time across DST.
+func newRecurrenceSet(event *models.Events) (*rrule.Set, error) {
+	option, err := rrule.StrToROption(*event.RRule)
+	if err != nil {
+		return nil, err
+	}
+	option.Dtstart = event.StartTime.In(seriesLocation(event))
+	if event.RecurrenceEnd != nil && (option.Until.IsZero() || event.RecurrenceEnd.Before(option.Until)) {
+		option.Until = *event.RecurrenceEnd
+	}
+
+	recurrence, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &rrule.Set{}
+	set.RRule(recurrence)
+	for _, exDate := range event.ExDates {
+		set.ExDate(exDate)
+	}
+	return set, nil
+}
+
+// isOccurrence reports whether the series of event has an occurrence
+// starting at start.
+func isOccurrence(event *models.Events, start time.Time) (bool, error) {
+	set, err := newRecurrenceSet(event)
+	if err != nil {
+		return false, err
+	}
+	return len(set.Between(start, start, true)) > 0, nil
+}
+
+// expandEvents turns recurring events into their occurrences overlapping
+// [from, to), applying exceptions. Single events are passed through.
+func expandEvents(events []*models.Events, exceptions []*models.EventExceptions, from, to time.Time) ([]*response.EventResponse, error) {
+	overrides := make(map[uint64]map[int64]*models.EventExceptions)
+	for _, exception := range exceptions {
+		if overrides[exception.EventID] == nil {
+			overrides[exception.EventID] = make(map[int64]*models.EventExceptions)
+		}
+		overrides[exception.EventID][exception.OccurrenceStart.Unix()] = exception
+	}
+
+	var eventResponses []*response.EventResponse
+	for _, event := range events {
+		if event.RRule == nil {
+			eventResponses = append(eventResponses, newEventResponse(event))
+			continue
+		}
+
+		set, err := newRecurrenceSet(event)
+		if err != nil {
+			return nil, err
+		}
+
+		// Occurrences starting up to one duration before the window still overlap it
+		duration := event.EndTime.Sub(*event.StartTime)
+		for _, start := range set.Between(from.Add(-duration), to, false) {
+			start = start.UTC()
+			occurrence := newOccurrenceResponse(event, start, overrides[event.ID][start.Unix()])
+			if !occurrence.EndTime.After(from) || !occurrence.StartTime.Before(to) {
+				continue
+			}
+			eventResponses = append(eventResponses, occurrence)
+			if len(eventResponses) > maxExpandedOccurrences {
+				message := fmt.Sprintf("the window holds more than %d occurrences, narrow from/to", maxExpandedOccurrences)
+				return nil, domain.NewValidationError(message, response.FieldError{
+					Field: "to", Rule: "max", Message: message,
+				})
+			}
+		}
+	}
+	return eventResponses, nil
+}
+
+// newOccurrenceResponse renders the occurrence of event starting at start,
+// with the fields of exception, if any, taking precedence.
+func newOccurrenceResponse(event *models.Events, start time.Time, exception *models.EventExceptions) *response.EventResponse {
+	occurrence := newEventResponse(event)
+	occurrenceStart := start
+	occurrence.OccurrenceStart = &occurrenceStart
+	startTime, endTime := start, start.Add(event.EndTime.Sub(*event.StartTime))
+	occurrence.StartTime = &startTime
+	occurrence.EndTime = &endTime
+
+	if exception == nil {
+		return occurrence
+	}
+	if exception.Title != nil {
+		occurrence.Title = *exception.Title
+	}
+	if exception.Description != nil {
+		occurrence.Description = exception.Description
+	}
+	if exception.Location != nil {
+		occurrence.Location = *exception.Location
+	}
+	if exception.StartTime != nil {
+		occurrence.StartTime = exception.StartTime
+	}
+	if exception.EndTime != nil {
+		occurrence.EndTime = exception.EndTime
+	}
+	if exception.Complete != nil {
+		occurrence.Complete = exception.Complete
+	}
+	return occurrence
+}
+
+// matchesEventFilter re-checks the attribute filters on an expanded
+// occurrence, since exceptions may have changed them.
+func matchesEventFilter(event *response.EventResponse, filter *domain.EventFilter) bool {
+	if filter.Complete != nil && *event.Complete != *filter.Complete {
+		return false
+	}
+	if filter.Location != "" && !strings.Contains(strings.ToLower(event.Location), strings.ToLower(filter.Location)) {
+		return false
+	}
+	return true
+}
+
+// sortEventResponses orders expanded events the way the repository orders
+// stored ones: by the filter's sort fields, then by id.
+func sortEventResponses(events []*response.EventResponse, fields []domain.SortField) {
+	sort.SliceStable(events, func(i, j int) bool {
+		for _, field := range fields {
+			order := compareEventResponses(events[i], events[j], field.Column)
+			if order == 0 {
+				continue
+			}
+			if field.Desc {
+				return order > 0
+			}
+			return order < 0
+		}
+		return events[i].ID < events[j].ID
+	})
+}
+
+func compareEventResponses(a, b *response.EventResponse, column string) int {
+	switch column {
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "location":
+		return strings.Compare(a.Location, b.Location)
+	case "complete":
+		return compareBools(*a.Complete, *b.Complete)
+	case "status":
+		return strings.Compare(a.Status, b.Status)
+	case "position":
+		return a.Position - b.Position
+	case "start_time":
+		return compareTimes(a.StartTime, b.StartTime)
+	case "end_time":
+		return compareTimes(a.EndTime, b.EndTime)
+	case "due_at":
+		return compareTimes(a.DueAt, b.DueAt)
+	case "priority":
+		return priorityLevel(a.Priority) - priorityLevel(b.Priority)
+	case "created_at":
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	case "updated_at":
+		return compareTimes(a.UpdatedAt, b.UpdatedAt)
+	default:
+		return 0
+	}
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareTimes sorts nil last, the way Postgres sorts NULL.
+func compareTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	default:
+		return a.Compare(*b)
+	}
+}
+
+func equalStringPtr(a, b *string) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func equalUint64Ptr(a, b *uint64) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	return a == nil && b == nil || a != nil && b != nil && a.Equal(*b)
+}
+
+func (u *eventUsecase) UpdateOccurrence(actor domain.Actor, id, version uint64, occurrence *request.OccurrenceRequest, req *request.EventRequest) (*response.EventResponse, error) {
+	return u.updateOccurrence(actor, id, version, occurrence, req, false)
+}
+
+// updateOccurrence is UpdateOccurrence for a req that may last no time at
+// all when instant.
+func (u *eventUsecase) updateOccurrence(actor domain.Actor, id, version uint64, occurrence *request.OccurrenceRequest, req *request.EventRequest, instant bool) (*response.EventResponse, error) {
+	if err := validateEventTimes(req, instant); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Invalid event")
+	}
+
+	event, err := u.getOccurrenceEvent(actor, id, version, occurrence)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Error getting occurrence")
+	}
+
+	// Tags belong to the series, a single occurrence keeps them
+	start := *occurrence.Occurrence
+	if occurrence.Scope == "this" {
+		exception := &models.EventExceptions{
+			OccurrenceStart: start,
+			Title:           &req.Title,
+			Description:     &req.Description,
+			Location:        &req.Location,
+			StartTime:       req.StartTime,
+			EndTime:         req.EndTime,
+			Complete:        req.Complete,
+		}
+		event.Changes = append(event.Changes, constant.WebhookEventUpdated)
+		if err := u.eventRepository.SaveEventException(event, exception); err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Error saving occurrence")
+		}
+		occurrenceResponse := newOccurrenceResponse(event, start, exception)
+		if err := u.setAccess(actor, occurrenceResponse); err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Error getting access")
+		}
+		return occurrenceResponse, nil
+	}
+
+	// "following" from the first occurrence is the whole series
+	if start.Equal(*event.StartTime) {
+		return u.UpdateEvent(actor, id, version, req)
+	}
+
+	if err := u.validateCalendar(actor, req.CalendarID, event.OwnerID); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Invalid calendar")
+	}
+	next := &models.Events{
+		OwnerID:      event.OwnerID,
+		WorkspaceID:  event.WorkspaceID,
+		CalendarID:   req.CalendarID,
+		Title:        req.Title,
+		Description:  &req.Description,
+		Location:     req.Location,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		AutoComplete: req.AutoComplete,
+
+		AllowBlockedCompletion: req.AllowBlockedCompletion,
+	}
+	setTask(next, req)
+	setComplete(next, *req.Complete)
+	timezone, err := u.recurrenceTimezone(actor, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Error getting calendar")
+	}
+	if err := setRecurrence(next, req, timezone); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Invalid recurrence")
+	}
+	if err := setTags(next, req.Tags); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Invalid tags")
+	}
+	if err := u.setBlockers(actor, next, req.BlockedBy); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Invalid blockers")
+	}
+	if err := u.setEventStatus(actor, next, req.Status, false); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Invalid status")
+	}
+	if err := setReminders(next, req.Reminders, false); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Invalid reminders")
+	}
+
+	endSeriesBefore(event, start)
+	event.Changes = append(event.Changes, constant.WebhookEventUpdated)
+	next.Changes = append(next.Changes, constant.WebhookEventCreated)
+	if err := u.eventRepository.SplitEventSeries(event, next); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Error splitting series")
+	}
+
+	eventResponse := newEventResponse(next)
+	if err := u.setAccess(actor, eventResponse); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateOccurrence]: Error getting access")
+	}
+	return eventResponse, nil
+}
+
+func (u *eventUsecase) DeleteOccurrence(actor domain.Actor, id, version uint64, occurrence *request.OccurrenceRequest) error {
+	event, err := u.getOccurrenceEvent(actor, id, version, occurrence)
+	if err != nil {
+		return errors.Wrap(err, "[EventUsecase.DeleteOccurrence]: Error getting occurrence")
+	}
+
+	start := *occurrence.Occurrence
+	if occurrence.Scope == "this" {
+		event.ExDates = append(event.ExDates, start)
+		event.Changes = append(event.Changes, constant.WebhookEventUpdated)
+		if err := u.eventRepository.UpdateEvent(event); err != nil {
+			return errors.Wrap(err, "[EventUsecase.DeleteOccurrence]: Error excluding occurrence")
+		}
+		return nil
+	}
+
+	// "following" from the first occurrence is the whole series
+	if start.Equal(*event.StartTime) {
+		return u.DeleteEvent(actor, id, version)
+	}
+
+	endSeriesBefore(event, start)
+	event.Changes = append(event.Changes, constant.WebhookEventUpdated)
+	if err := u.eventRepository.SplitEventSeries(event, nil); err != nil {
+		return errors.Wrap(err, "[EventUsecase.DeleteOccurrence]: Error ending series")
+	}
+	return nil
+}
+
+// getOccurrenceEvent loads the recurring event an occurrence write targets
+// and checks that the occurrence exists.
+func (u *eventUsecase) getOccurrenceEvent(actor domain.Actor, id, version uint64, occurrence *request.OccurrenceRequest) (*models.Events, error) {
+	if occurrence.Scope != "this" && occurrence.Scope != "following" {
+		return nil, domain.NewValidationError("scope must be this or following", response.FieldError{
+			Field: "scope", Rule: "oneof", Message: "scope must be one of: this, following",
+		})
+	}
+	if occurrence.Occurrence == nil {
+		return nil, domain.NewValidationError("occurrence is required", response.FieldError{
+			Field: "occurrence", Rule: "required", Message: "occurrence is required",
+		})
+	}
+
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if event == nil {
+		return nil, domain.ErrEventNotFound
+	}
+
+	if version != 0 && event.Version != version {
+		return nil, domain.ErrEventVersionMismatch
+	}
+
+	if err := u.checkEditAccess(actor, event); err != nil {
+		return nil, err
+	}
+
+	if event.RRule == nil {
+		return nil, domain.NewValidationError("event is not recurring", response.FieldError{
+			Field: "scope", Rule: "oneof", Message: "scope must be all for events that do not recur",
+		})
+	}
+
+	ok, err := isOccurrence(event, *occurrence.Occurrence)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.NewNotFoundError("occurrence not found")
+	}
+	return event, nil
+}
+
+// endSeriesBefore bounds the series of event so its last occurrence starts
+// before start. Occurrences are at least a second apart.
+func endSeriesBefore(event *models.Events, start time.Time) {
+	end := start.Add(-time.Second)
+	if event.RecurrenceEnd == nil || end.Before(*event.RecurrenceEnd) {
+		event.RecurrenceEnd = &end
+	}
+}
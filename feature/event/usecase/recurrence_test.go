@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+// Helper function to create a weekly test series starting Monday 2024-01-01 09:00 UTC
+func createTestSeries(id uint64, rule string) *models.Events {
+	startTime := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	event := createTestEvent(id, "Standup", "Daily sync", "Office", false, startTime, startTime.Add(15*time.Minute))
+	event.RRule = &rule
+	return event
+}
+
+func TestNewRecurrence(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	recurrenceEnd := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	beforeStart := startTime.Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		rule           string
+		recurrenceEnd  *time.Time
+		expectedRule   *string
+		expectedEnd    *time.Time
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name: "single event",
+			rule: "  ",
+		},
+		{
+			name:         "unbounded weekly rule",
+			rule:         "FREQ=WEEKLY;BYDAY=MO",
+			expectedRule: stringPtr("FREQ=WEEKLY;BYDAY=MO"),
+		},
+		{
+			name:         "rrule prefix is dropped",
+			rule:         "RRULE:FREQ=DAILY",
+			expectedRule: stringPtr("FREQ=DAILY"),
+		},
+		{
+			name:         "count bounds the series",
+			rule:         "FREQ=WEEKLY;COUNT=3",
+			expectedRule: stringPtr("FREQ=WEEKLY;COUNT=3"),
+			expectedEnd:  timePtr(time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:         "until bounds the series",
+			rule:         "FREQ=DAILY;UNTIL=20240110T000000Z",
+			expectedRule: stringPtr("FREQ=DAILY;UNTIL=20240110T000000Z"),
+			expectedEnd:  timePtr(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:          "recurrence end bounds the series",
+			rule:          "FREQ=DAILY",
+			recurrenceEnd: &recurrenceEnd,
+			expectedRule:  stringPtr("FREQ=DAILY"),
+			expectedEnd:   &recurrenceEnd,
+		},
+		{
+			name:           "invalid rule",
+			rule:           "FREQ=SOMETIMES",
+			expectedError:  true,
+			expectedErrMsg: "rrule is invalid",
+		},
+		{
+			name:           "rule carrying its own start",
+			rule:           "DTSTART=20240101T090000Z;FREQ=DAILY",
+			expectedError:  true,
+			expectedErrMsg: "must not contain DTSTART",
+		},
+		{
+			name:           "recurrence end before start",
+			rule:           "FREQ=DAILY",
+			recurrenceEnd:  &beforeStart,
+			expectedError:  true,
+			expectedErrMsg: "recurrenceEnd must not be before startTime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, end, err := newRecurrence(tt.rule, startTime, tt.recurrenceEnd, time.UTC)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if !equalStringPtr(rule, tt.expectedRule) {
+				t.Errorf("Expected rule %v, got %v", tt.expectedRule, rule)
+			}
+
+			if !equalTimePtr(end, tt.expectedEnd) {
+				t.Errorf("Expected recurrence end %v, got %v", tt.expectedEnd, end)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_GetEventList_Recurring(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)
+
+	series := createTestSeries(1, "FREQ=WEEKLY;BYDAY=MO")
+	series.ExDates = []time.Time{time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)}
+	single := createTestEvent(2, "Dentist", "Checkup", "Clinic", false,
+		time.Date(2024, 1, 10, 14, 0, 0, 0, time.UTC), time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC))
+
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{series, single}
+	mockRepo.exceptions = []*models.EventExceptions{{
+		EventID:         1,
+		OccurrenceStart: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		Title:           stringPtr("Planning"),
+	}}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.GetEventList(testActor, &request.EventListRequest{
+		PaginationRequest: request.PaginationRequest{Page: 1, Limit: 10},
+		From:              &from,
+		To:                &to,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !mockRepo.lastFilter.Expand {
+		t.Error("Expected a bounded window to expand recurring events")
+	}
+
+	expected := []struct {
+		title string
+		start time.Time
+	}{
+		{"Standup", time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)},
+		{"Dentist", time.Date(2024, 1, 10, 14, 0, 0, 0, time.UTC)},
+		{"Planning", time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"Standup", time.Date(2024, 1, 22, 9, 0, 0, 0, time.UTC)},
+	}
+
+	if result.Pagination.Total != len(expected) {
+		t.Fatalf("Expected total %d, got %d", len(expected), result.Pagination.Total)
+	}
+
+	for i, event := range result.Data {
+		if event.Title != expected[i].title || !event.StartTime.Equal(expected[i].start) {
+			t.Errorf("Expected %s at %v, got %s at %v", expected[i].title, expected[i].start, event.Title, event.StartTime)
+		}
+		if event.ID == 1 && (event.OccurrenceStart == nil || !event.OccurrenceStart.Equal(*event.StartTime)) {
+			t.Errorf("Expected occurrence start %v, got %v", event.StartTime, event.OccurrenceStart)
+		}
+	}
+}
+
+func TestEventUsecase_RecurrenceTimezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// A Monday 09:00 standup in New York keeps its local time across the
+	// start of DST on 2024-03-10
+	standup := createTestEvent(1, "Standup", "Daily sync", "Office", false,
+		time.Date(2024, 3, 4, 9, 0, 0, 0, newYork), time.Date(2024, 3, 4, 9, 15, 0, 0, newYork))
+	standup.RRule = stringPtr("FREQ=WEEKLY;BYDAY=MO")
+	standup.Timezone = stringPtr("America/New_York")
+
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{standup}
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 19, 0, 0, 0, 0, time.UTC)
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.GetEventList(testActor, &request.EventListRequest{
+		PaginationRequest: request.PaginationRequest{Page: 1, Limit: 10},
+		From:              &from,
+		To:                &to,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []time.Time{
+		time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 18, 13, 0, 0, 0, time.UTC),
+	}
+	if len(result.Data) != len(expected) {
+		t.Fatalf("Expected %d occurrences, got %d", len(expected), len(result.Data))
+	}
+	for i, occurrence := range result.Data {
+		if !occurrence.StartTime.Equal(expected[i]) {
+			t.Errorf("Expected occurrence %d at %v, got %v", i, expected[i], occurrence.StartTime)
+		}
+	}
+
+	// Series take the zone of their calendar, so BYDAY=MO is Monday in
+	// Tokyo even though 08:00 there is Sunday in UTC
+	calendarID := uint64(1)
+	mockRepo = newMockEventRepository()
+	usecase = NewEventUsecase(mockRepo, &mockCalendarRepository{calendars: []*models.Calendars{
+		{ID: calendarID, OwnerID: testActor.UserID, Timezone: "Asia/Tokyo"},
+	}})
+	startTime := time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)
+	req := createTestEventRequest("Planning", "Weekly", "Office", false, startTime, startTime.Add(time.Hour))
+	req.CalendarID = &calendarID
+	req.RRule = "FREQ=WEEKLY;BYDAY=MO;COUNT=2"
+	created, err := usecase.CreateEvent(testActor, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created.Timezone == nil || *created.Timezone != "Asia/Tokyo" {
+		t.Errorf("Expected the series to recur in Asia/Tokyo, got %v", created.Timezone)
+	}
+	if lastStart := time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC); created.RecurrenceEnd == nil || !created.RecurrenceEnd.Equal(lastStart) {
+		t.Errorf("Expected the last occurrence at %v, got %v", lastStart, created.RecurrenceEnd)
+	}
+
+	req.Timezone = "Mars/Olympus"
+	if _, err := usecase.CreateEvent(testActor, req); err == nil || !strings.Contains(err.Error(), "timezone") {
+		t.Errorf("Expected an unknown timezone to be rejected, got: %v", err)
+	}
+}
+
+func TestEventUsecase_UpdateOccurrence(t *testing.T) {
+	secondMonday := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		rule              string
+		scope             string
+		occurrence        *time.Time
+		expectedError     bool
+		expectedErrMsg    string
+		expectedException bool
+		expectedSplit     bool
+	}{
+		{
+			name:              "this occurrence becomes an exception",
+			rule:              "FREQ=WEEKLY",
+			scope:             "this",
+			occurrence:        &secondMonday,
+			expectedException: true,
+		},
+		{
+			name:          "this and following splits the series",
+			rule:          "FREQ=WEEKLY",
+			scope:         "following",
+			occurrence:    &secondMonday,
+			expectedSplit: true,
+		},
+		{
+			name:           "missing occurrence",
+			rule:           "FREQ=WEEKLY",
+			scope:          "this",
+			expectedError:  true,
+			expectedErrMsg: "occurrence is required",
+		},
+		{
+			name:           "time outside the series",
+			rule:           "FREQ=WEEKLY",
+			scope:          "this",
+			occurrence:     timePtr(secondMonday.Add(time.Hour)),
+			expectedError:  true,
+			expectedErrMsg: "occurrence not found",
+		},
+		{
+			name:           "single event",
+			scope:          "this",
+			occurrence:     &secondMonday,
+			expectedError:  true,
+			expectedErrMsg: "event is not recurring",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := createTestSeries(1, tt.rule)
+			if tt.rule == "" {
+				series.RRule = nil
+			}
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{series}
+
+			req := createTestEventRequest("Moved standup", "Daily sync", "Office", false,
+				secondMonday.Add(time.Hour), secondMonday.Add(time.Hour+15*time.Minute))
+			req.RRule = "FREQ=WEEKLY"
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.UpdateOccurrence(testActor, 1, 1, &request.OccurrenceRequest{
+				Scope:      tt.scope,
+				Occurrence: tt.occurrence,
+			}, req)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if tt.expectedException {
+				if len(mockRepo.exceptions) != 1 || !mockRepo.exceptions[0].OccurrenceStart.Equal(secondMonday) {
+					t.Errorf("Expected an exception for %v, got %+v", secondMonday, mockRepo.exceptions)
+				}
+				if result.ID != 1 || !result.StartTime.Equal(*req.StartTime) || result.Title != req.Title {
+					t.Errorf("Expected the overridden occurrence, got %+v", result)
+				}
+			}
+
+			if tt.expectedSplit {
+				if series.RecurrenceEnd == nil || !series.RecurrenceEnd.Before(secondMonday) {
+					t.Errorf("Expected the series to end before %v, got %v", secondMonday, series.RecurrenceEnd)
+				}
+				if mockRepo.splitNext == nil || result.ID != mockRepo.splitNext.ID || result.RRule == nil {
+					t.Errorf("Expected a new recurring series, got %+v", result)
+				}
+			}
+		})
+	}
+}
+
+func TestEventUsecase_DeleteOccurrence(t *testing.T) {
+	firstMonday := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	secondMonday := firstMonday.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name            string
+		scope           string
+		occurrence      time.Time
+		expectedExDates int
+		expectedEndSet  bool
+		expectedDeleted bool
+	}{
+		{
+			name:            "this occurrence is excluded",
+			scope:           "this",
+			occurrence:      secondMonday,
+			expectedExDates: 1,
+		},
+		{
+			name:           "this and following ends the series",
+			scope:          "following",
+			occurrence:     secondMonday,
+			expectedEndSet: true,
+		},
+		{
+			name:            "this and following from the first occurrence deletes the series",
+			scope:           "following",
+			occurrence:      firstMonday,
+			expectedDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := createTestSeries(1, "FREQ=WEEKLY")
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{series}
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			err := usecase.DeleteOccurrence(testActor, 1, 1, &request.OccurrenceRequest{
+				Scope:      tt.scope,
+				Occurrence: &tt.occurrence,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if len(series.ExDates) != tt.expectedExDates {
+				t.Errorf("Expected %d exception dates, got %v", tt.expectedExDates, series.ExDates)
+			}
+
+			if (series.RecurrenceEnd != nil) != tt.expectedEndSet {
+				t.Errorf("Expected recurrence end set: %v, got %v", tt.expectedEndSet, series.RecurrenceEnd)
+			}
+
+			if (len(mockRepo.events) == 0) != tt.expectedDeleted {
+				t.Errorf("Expected series deleted: %v, got %d events", tt.expectedDeleted, len(mockRepo.events))
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// SendDueReminders skips the occurrences whose reminders were missed, so
+// a reminder is sent once however late it is. Reminders of complete events,
+// and of occurrences the event no longer has, are dropped unsent, as are
+// ones that keep failing.
+func (u *eventUsecase) SendDueReminders(ctx context.Context, notifier domain.Notifier, lateAfter time.Duration) (int, error) {
+	now := time.Now()
+	sent, failed := 0, 0
+	var lastErr error
+	err := u.eventRepository.ProcessDueReminders(now, constant.ReminderBatchSize, func(reminder *models.Reminders) error {
+		event := reminder.Event
+		if event == nil {
+			reminder.RemindAt = nil
+			return nil
+		}
+		start := reminder.RemindAt.Add(time.Duration(reminder.MinutesBefore) * time.Minute)
+		current, err := isReminded(event, start)
+		if err != nil {
+			// An unreadable series would otherwise hold up the queue
+			failed++
+			lastErr = err
+			reminder.RemindAt = nil
+			return nil
+		}
+
+		if current && !event.Complete && reminder.User != nil {
+			late := now.Sub(*reminder.RemindAt) > lateAfter
+			if err := notifier.Notify(ctx, newReminderNotification(reminder, start, late)); err != nil {
+				failed++
+				lastErr = err
+				// Retried on the next run until it runs out of attempts
+				if reminder.Attempts++; reminder.Attempts < constant.MaxReminderAttempts {
+					return nil
+				}
+			} else {
+				reminder.SentAt = &now
+				reminder.Late = late
+				sent++
+			}
+		}
+
+		reminder.Attempts = 0
+		if reminder.RemindAt, err = nextReminder(event, reminder.MinutesBefore, now); err != nil {
+			failed++
+			lastErr = err
+		}
+		return nil
+	})
+	if err != nil {
+		return sent, errors.Wrap(err, "[EventUsecase.SendDueReminders]: Error processing reminders")
+	}
+	if failed > 0 {
+		return sent, errors.Wrapf(lastErr, "[EventUsecase.SendDueReminders]: Error sending %d reminders", failed)
+	}
+	return sent, nil
+}
+
+// setReminders gives event a reminder each of minutes before it, keeping
+// the ones it has. New ones are scheduled for the next time they are due,
+// and kept ones too when reschedule, as the event moved.
+func setReminders(event *models.Events, minutes []int, reschedule bool) error {
+	minutes = slices.Clone(minutes)
+	slices.Sort(minutes)
+	minutes = slices.Compact(minutes)
+
+	now := time.Now()
+	reminders := make([]*models.Reminders, 0, len(minutes))
+	for _, before := range minutes {
+		index := slices.IndexFunc(event.Reminders, func(reminder *models.Reminders) bool { return reminder.MinutesBefore == before })
+		if index >= 0 && !reschedule {
+			reminders = append(reminders, event.Reminders[index])
+			continue
+		}
+
+		reminder := &models.Reminders{WorkspaceID: event.WorkspaceID, UserID: event.OwnerID, MinutesBefore: before}
+		if index >= 0 {
+			reminder = event.Reminders[index]
+		}
+		remindAt, err := nextReminder(event, before, now)
+		if err != nil {
+			return err
+		}
+		reminder.RemindAt = remindAt
+		reminder.Attempts = 0
+		reminders = append(reminders, reminder)
+	}
+	event.Reminders = reminders
+	return nil
+}
+
+// nextReminder is when the reminder minutes before event is next due: for
+// the first occurrence it is still ahead of after, nil when there is none.
+func nextReminder(event *models.Events, minutes int, after time.Time) (*time.Time, error) {
+	before := time.Duration(minutes) * time.Minute
+	start := reminderAnchor(event)
+	if start == nil {
+		return nil, nil
+	}
+
+	if event.RRule != nil {
+		set, err := newRecurrenceSet(event)
+		if err != nil {
+			return nil, err
+		}
+		next := set.After(after.Add(before), false).UTC()
+		if next.IsZero() {
+			return nil, nil
+		}
+		start = &next
+	} else if !start.After(after.Add(before)) {
+		return nil, nil
+	}
+
+	remindAt := start.Add(-before)
+	return &remindAt, nil
+}
+
+// isReminded reports whether event still has an occurrence starting at
+// start, or is still due then.
+func isReminded(event *models.Events, start time.Time) (bool, error) {
+	if event.RRule != nil {
+		return isOccurrence(event, start)
+	}
+	anchor := reminderAnchor(event)
+	return anchor != nil && anchor.Equal(start), nil
+}
+
+// reminderAnchor is the time reminders of event count back from: its
+// start, or when it is due for tasks without a window.
+func reminderAnchor(event *models.Events) *time.Time {
+	if event.StartTime != nil {
+		return event.StartTime
+	}
+	return event.DueAt
+}
+
+// remindersMoved reports whether event starts or is due at other times than
+// it did as previous.
+func remindersMoved(previous, event *models.Events) bool {
+	return !equalTimePtr(previous.StartTime, event.StartTime) ||
+		!equalTimePtr(previous.DueAt, event.DueAt) ||
+		!equalStringPtr(previous.RRule, event.RRule) ||
+		!equalTimePtr(previous.RecurrenceEnd, event.RecurrenceEnd) ||
+		!slices.EqualFunc(previous.ExDates, event.ExDates, time.Time.Equal)
+}
+
+// reminderMinutes are the minutes before event of its reminders, in order;
+// never nil.
+func reminderMinutes(event *models.Events) []int {
+	minutes := make([]int, 0, len(event.Reminders))
+	for _, reminder := range event.Reminders {
+		minutes = append(minutes, reminder.MinutesBefore)
+	}
+	return minutes
+}
+
+func newReminderResponses(reminders []*models.Reminders) []*response.ReminderResponse {
+	reminderResponses := make([]*response.ReminderResponse, 0, len(reminders))
+	for _, reminder := range reminders {
+		reminderResponses = append(reminderResponses, &response.ReminderResponse{
+			MinutesBefore: reminder.MinutesBefore,
+			RemindAt:      reminder.RemindAt,
+			SentAt:        reminder.SentAt,
+			Late:          reminder.Late,
+		})
+	}
+	return reminderResponses
+}
+
+func newReminderNotification(reminder *models.Reminders, start time.Time, late bool) *response.ReminderNotification {
+	return &response.ReminderNotification{
+		ReminderID: reminder.ID,
+		EventID:    reminder.EventID,
+		Title:      reminder.Event.Title,
+		Location:   reminder.Event.Location,
+		StartTime:  start,
+		RemindAt:   *reminder.RemindAt,
+		Late:       late,
+		Email:      reminder.User.Email,
+		Name:       reminder.User.Name,
+	}
+}
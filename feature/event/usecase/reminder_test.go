@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// mockNotifier records the reminders it is asked to send
+type mockNotifier struct {
+	sent []*response.ReminderNotification
+	err  error
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, notification *response.ReminderNotification) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, notification)
+	return nil
+}
+
+// Helper function to create an event starting at start with a reminder
+// minutes before, due at remindAt
+func createTestReminderEvent(start time.Time, minutes int, remindAt time.Time) *models.Events {
+	event := createTestEvent(1, "Review", "Description", "Office", false, start, start.Add(time.Hour))
+	event.Reminders = []*models.Reminders{{ID: 1, EventID: 1, UserID: testActor.UserID, MinutesBefore: minutes, RemindAt: &remindAt}}
+	return event
+}
+
+func TestEventUsecase_CreateEvent_Reminders(t *testing.T) {
+	startTime := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	req := createTestEventRequest("Review", "Description", "Office", false, startTime, startTime.Add(time.Hour))
+	req.Reminders = []int{1440, 15, 15}
+
+	usecase := NewEventUsecase(newMockEventRepository(), &mockCalendarRepository{})
+	result, err := usecase.CreateEvent(testActor, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(result.Reminders) != 2 || result.Reminders[0].MinutesBefore != 15 || result.Reminders[1].MinutesBefore != 1440 {
+		t.Fatalf("Expected reminders 15 and 1440 minutes before, got %+v", result.Reminders)
+	}
+	if remindAt := result.Reminders[0].RemindAt; remindAt == nil || !remindAt.Equal(startTime.Add(-15*time.Minute)) {
+		t.Errorf("Expected the 15 minute reminder due at %v, got %v", startTime.Add(-15*time.Minute), remindAt)
+	}
+	// A day before has already gone by
+	if remindAt := result.Reminders[1].RemindAt; remindAt != nil {
+		t.Errorf("Expected the 1440 minute reminder not scheduled, got %v", remindAt)
+	}
+}
+
+func TestEventUsecase_SendDueReminders(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+
+	tests := []struct {
+		name             string
+		event            func() *models.Events
+		notifyError      error
+		expectedSent     int
+		expectedLate     bool
+		expectedRemindAt bool
+		expectedAttempts int
+		expectedError    bool
+	}{
+		{
+			name: "due",
+			event: func() *models.Events {
+				return createTestReminderEvent(now.Add(15*time.Minute), 15, now)
+			},
+			expectedSent: 1,
+		},
+		{
+			name: "missed sends once, late",
+			event: func() *models.Events {
+				return createTestReminderEvent(now.Add(-2*time.Hour), 15, now.Add(-135*time.Minute))
+			},
+			expectedSent: 1,
+			expectedLate: true,
+		},
+		{
+			name: "missed occurrences of a series",
+			event: func() *models.Events {
+				start := now.Add(-72 * time.Hour)
+				event := createTestReminderEvent(start, 60, start.Add(-time.Hour))
+				event.RRule = stringPtr("FREQ=DAILY")
+				return event
+			},
+			expectedSent:     1,
+			expectedLate:     true,
+			expectedRemindAt: true,
+		},
+		{
+			name: "complete event",
+			event: func() *models.Events {
+				event := createTestReminderEvent(now.Add(15*time.Minute), 15, now)
+				event.Complete = true
+				return event
+			},
+		},
+		{
+			name: "stale reminder of a moved event",
+			event: func() *models.Events {
+				return createTestReminderEvent(now.Add(time.Hour), 15, now)
+			},
+			expectedRemindAt: true,
+		},
+		{
+			name: "notifier failing",
+			event: func() *models.Events {
+				return createTestReminderEvent(now.Add(15*time.Minute), 15, now)
+			},
+			notifyError:      errors.New("connection refused"),
+			expectedRemindAt: true,
+			expectedAttempts: 1,
+			expectedError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := tt.event()
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{event}
+			notifier := &mockNotifier{err: tt.notifyError}
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			sent, err := usecase.SendDueReminders(context.Background(), notifier, 2*time.Minute)
+
+			if tt.expectedError != (err != nil) {
+				t.Errorf("Expected error %v, got: %v", tt.expectedError, err)
+			}
+			if sent != tt.expectedSent || len(notifier.sent) != tt.expectedSent {
+				t.Fatalf("Expected %d sent, got %d with %d notifications", tt.expectedSent, sent, len(notifier.sent))
+			}
+
+			reminder := event.Reminders[0]
+			if tt.expectedSent > 0 {
+				if notifier.sent[0].Late != tt.expectedLate || reminder.Late != tt.expectedLate {
+					t.Errorf("Expected late %v, got %v", tt.expectedLate, notifier.sent[0].Late)
+				}
+				if notifier.sent[0].Email != "owner@example.com" || reminder.SentAt == nil {
+					t.Errorf("Expected the reminder sent to the owner, got %+v", notifier.sent[0])
+				}
+			}
+			if (reminder.RemindAt != nil) != tt.expectedRemindAt {
+				t.Errorf("Expected remind at set %v, got %v", tt.expectedRemindAt, reminder.RemindAt)
+			}
+			if reminder.RemindAt != nil && tt.notifyError == nil && !reminder.RemindAt.After(now) {
+				t.Errorf("Expected the reminder rescheduled after now, got %v", reminder.RemindAt)
+			}
+			if reminder.Attempts != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, reminder.Attempts)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_SendDueReminders_GivesUp(t *testing.T) {
+	now := time.Now()
+	event := createTestReminderEvent(now.Add(15*time.Minute), 15, now)
+	event.Reminders[0].Attempts = constant.MaxReminderAttempts - 1
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{event}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	if _, err := usecase.SendDueReminders(context.Background(), &mockNotifier{err: errors.New("rejected")}, time.Minute); err == nil {
+		t.Error("Expected error, got nil")
+	}
+	if event.Reminders[0].RemindAt != nil || event.Reminders[0].SentAt != nil {
+		t.Errorf("Expected the reminder dropped unsent, got %+v", event.Reminders[0])
+	}
+}
+
+func TestEventUsecase_PatchEvent_Reminders(t *testing.T) {
+	startTime := time.Now().Add(3 * time.Hour).Truncate(time.Minute)
+	event := createTestReminderEvent(startTime, 15, startTime.Add(-15*time.Minute))
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{event}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	moved := startTime.Add(time.Hour)
+	result, err := usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType,
+		[]byte(`{"startTime":"`+moved.Format(time.RFC3339)+`","endTime":"`+moved.Add(time.Hour).Format(time.RFC3339)+`"}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !slices.Contains(mockRepo.lastColumns, "reminders") {
+		t.Errorf("Expected the reminders saved, got %v", mockRepo.lastColumns)
+	}
+	if remindAt := result.Reminders[0].RemindAt; remindAt == nil || !remindAt.Equal(moved.Add(-15*time.Minute)) {
+		t.Errorf("Expected the reminder moved with the event to %v, got %v", moved.Add(-15*time.Minute), remindAt)
+	}
+}
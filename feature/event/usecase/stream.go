@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+func (u *eventUsecase) StreamEvents(ctx context.Context, actor domain.Actor, after *uint64, send func(changes []*response.EventChangeResponse) error) error {
+	// Subscribed first, so changes made while catching up still wake it
+	wake, unsubscribe := u.eventRepository.SubscribeEventChanges()
+	defer unsubscribe()
+
+	var last uint64
+	if after != nil {
+		last = *after
+	} else {
+		var err error
+		if last, err = u.eventRepository.LastEventChangeID(); err != nil {
+			return errors.Wrap(err, "[EventUsecase.StreamEvents]: Error getting last change")
+		}
+	}
+
+	heartbeat := time.NewTicker(constant.StreamHeartbeat)
+	defer heartbeat.Stop()
+	idle := false
+	for {
+		changes, more, err := u.nextEventChanges(actor, &last)
+		if err != nil {
+			return errors.Wrap(err, "[EventUsecase.StreamEvents]: Error getting changes")
+		}
+		if len(changes) > 0 || idle {
+			if err := send(changes); err != nil {
+				return errors.Wrap(err, "[EventUsecase.StreamEvents]: Error sending changes")
+			}
+			idle = false
+		}
+		if more {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-wake:
+		case <-heartbeat.C:
+			// Also picks up changes whose notification was missed
+			idle = true
+		}
+	}
+}
+
+// nextEventChanges reads the changes after last that actor can see, moving
+// last past them, and reports whether there are more to read.
+func (u *eventUsecase) nextEventChanges(actor domain.Actor, last *uint64) ([]*response.EventChangeResponse, bool, error) {
+	changes, err := u.eventRepository.GetEventChanges(actor, *last, constant.StreamBatchSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Events changed more than once are read once, as they are now
+	events := make(map[uint64]*response.EventResponse)
+	changeResponses := make([]*response.EventChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		*last = change.ID
+		changeResponse := &response.EventChangeResponse{ID: change.ID, Type: streamEventType(change.Type)}
+		if change.Type == constant.WebhookEventDeleted {
+			changeResponse.Event = &response.EventResponse{
+				ID:         change.EventID,
+				OwnerID:    change.OwnerID,
+				CalendarID: change.CalendarID,
+				Version:    change.Version,
+				DeletedAt:  change.CreatedAt,
+			}
+			changeResponses = append(changeResponses, changeResponse)
+			continue
+		}
+
+		event, found := events[change.EventID]
+		if !found {
+			event, err = u.GetEventByID(actor, change.EventID)
+			if err != nil && !errors.Is(err, domain.ErrEventNotFound) {
+				return nil, false, err
+			}
+			events[change.EventID] = event
+		}
+		// Gone since, or moved out of actor's sight
+		if event == nil {
+			continue
+		}
+		changeResponse.Event = event
+		changeResponses = append(changeResponses, changeResponse)
+	}
+	return changeResponses, len(changes) == constant.StreamBatchSize, nil
+}
+
+// streamEventType is the kind of change the stream sends for a change to
+// the outbox of type changeType.
+func streamEventType(changeType string) string {
+	switch changeType {
+	case constant.WebhookEventCreated:
+		return constant.StreamEventCreated
+	case constant.WebhookEventDeleted:
+		return constant.StreamEventDeleted
+	default:
+		return constant.StreamEventUpdated
+	}
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// changeSummary is a change as the tests compare it: its ID, type and the
+// event it carries
+type changeSummary struct {
+	id      uint64
+	kind    string
+	eventID uint64
+}
+
+func summarizeChanges(changes []*response.EventChangeResponse) []changeSummary {
+	summaries := make([]changeSummary, 0, len(changes))
+	for _, change := range changes {
+		summaries = append(summaries, changeSummary{id: change.ID, kind: change.Type, eventID: change.Event.ID})
+	}
+	return summaries
+}
+
+func TestEventUsecase_StreamEvents_Resume(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	first, err := usecase.CreateEvent(testActor, createTestEventRequest("Meeting", "Description", "Office", false, startTime, endTime))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second, err := usecase.CreateEvent(testActor, createTestEventRequest("Lunch", "Description", "Cafe", false, startTime, endTime))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.PatchEvent(testActor, first.ID, 0, constant.MergePatchContentType, []byte(`{"complete":true}`)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := usecase.DeleteEvent(testActor, second.ID, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Changes of other users and workspaces
+	mockRepo.addChange(&models.Events{ID: 9, OwnerID: 2}, constant.WebhookEventCreated)
+	mockRepo.addChange(&models.Events{ID: 10, OwnerID: testActor.UserID, WorkspaceID: 3}, constant.WebhookEventCreated)
+
+	tests := []struct {
+		name     string
+		after    uint64
+		expected []changeSummary
+	}{
+		{
+			name:  "from the start",
+			after: 0,
+			// The lunch is gone by the time its creation is read
+			expected: []changeSummary{
+				{id: 1, kind: constant.StreamEventCreated, eventID: first.ID},
+				{id: 3, kind: constant.StreamEventUpdated, eventID: first.ID},
+				{id: 4, kind: constant.StreamEventDeleted, eventID: second.ID},
+			},
+		},
+		{
+			name:     "after a change",
+			after:    3,
+			expected: []changeSummary{{id: 4, kind: constant.StreamEventDeleted, eventID: second.ID}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var received []*response.EventChangeResponse
+			err := usecase.StreamEvents(ctx, testActor, &tt.after, func(changes []*response.EventChangeResponse) error {
+				received = append(received, changes...)
+				cancel()
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if summaries := summarizeChanges(received); !slices.Equal(summaries, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, summaries)
+			}
+			for _, change := range received {
+				switch change.Type {
+				case constant.StreamEventDeleted:
+					if change.Event.DeletedAt == nil || change.Event.Title != "" {
+						t.Errorf("Expected the deleted event reduced to its IDs and deletion, got %+v", change.Event)
+					}
+				default:
+					if change.Event.Title != "Meeting" || !*change.Event.Complete || change.Event.Access != constant.CalendarRoleOwner {
+						t.Errorf("Expected the event as it is now, got %+v", change.Event)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEventUsecase_StreamEvents_Live(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{createTestEvent(1, "Meeting", "Description", "Office", false, startTime, endTime)}
+	mockRepo.addChange(mockRepo.events[0], constant.WebhookEventCreated)
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	// Made once the stream started, without a cursor
+	var created *response.EventResponse
+	mockRepo.onLastChange = func() {
+		var err error
+		if created, err = usecase.CreateEvent(testActor, createTestEventRequest("Lunch", "Description", "Cafe", false, startTime, endTime)); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received []*response.EventChangeResponse
+	err := usecase.StreamEvents(ctx, testActor, nil, func(changes []*response.EventChangeResponse) error {
+		received = append(received, changes...)
+		if len(received) == 1 {
+			// Woken up by the next change
+			_, err := usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType, []byte(`{"title":"Standup"}`))
+			return err
+		}
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []changeSummary{
+		{id: 2, kind: constant.StreamEventCreated, eventID: created.ID},
+		{id: 3, kind: constant.StreamEventUpdated, eventID: 1},
+	}
+	if summaries := summarizeChanges(received); !slices.Equal(summaries, expected) {
+		t.Errorf("Expected only the changes made since it started, %v, got %v", expected, summaries)
+	}
+}
+
+func TestEventUsecase_StreamEvents_CatchUp(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{createTestEvent(1, "Meeting", "Description", "Office", false, startTime, endTime)}
+	total := 2*constant.StreamBatchSize + constant.StreamBatchSize/2
+	for range total {
+		mockRepo.addChange(mockRepo.events[0], constant.WebhookEventUpdated)
+	}
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var batches []int
+	var last uint64
+	after := uint64(0)
+	err := usecase.StreamEvents(ctx, testActor, &after, func(changes []*response.EventChangeResponse) error {
+		batches = append(batches, len(changes))
+		for _, change := range changes {
+			if change.ID != last+1 {
+				t.Fatalf("Expected change %d next, got %d", last+1, change.ID)
+			}
+			last = change.ID
+		}
+		if last == uint64(total) {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if expected := []int{constant.StreamBatchSize, constant.StreamBatchSize, constant.StreamBatchSize / 2}; !slices.Equal(batches, expected) {
+		t.Errorf("Expected batches of %v, got %v", expected, batches)
+	}
+}
+
+func TestEventUsecase_StreamEvents_Shared(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	work := uint64(1)
+	viewer := domain.Actor{UserID: 2}
+	calendars := &mockCalendarRepository{
+		calendars: []*models.Calendars{{ID: work, OwnerID: testActor.UserID, Name: "Work"}},
+		shares: []*models.CalendarShares{
+			{ID: 1, CalendarID: work, UserID: viewer.UserID, Role: constant.CalendarRoleViewer, Status: constant.CalendarShareAccepted},
+		},
+	}
+	mockRepo := newMockEventRepository()
+	mockRepo.calendars = calendars
+
+	shared := createTestEvent(1, "Standup", "Description", "Office", false, startTime, endTime)
+	shared.CalendarID = &work
+	private := createTestEvent(2, "Dentist", "Description", "Clinic", false, startTime, endTime)
+	mockRepo.events = []*models.Events{shared, private}
+	mockRepo.addChange(shared, constant.WebhookEventCreated)
+	mockRepo.addChange(private, constant.WebhookEventCreated)
+	usecase := NewEventUsecase(mockRepo, calendars)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received []*response.EventChangeResponse
+	after := uint64(0)
+	err := usecase.StreamEvents(ctx, viewer, &after, func(changes []*response.EventChangeResponse) error {
+		received = append(received, changes...)
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(received) != 1 || received[0].Event.ID != shared.ID || received[0].Event.Access != constant.CalendarRoleViewer {
+		t.Errorf("Expected only the shared event, with the viewer's access, got %v", summarizeChanges(received))
+	}
+}
+
+func TestEventUsecase_StreamEvents_Errors(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	after := uint64(0)
+
+	mockRepo := newMockEventRepository()
+	mockRepo.shouldError = true
+	mockRepo.errorMessage = "database error"
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	err := usecase.StreamEvents(context.Background(), testActor, &after, func([]*response.EventChangeResponse) error { return nil })
+	if err == nil {
+		t.Error("Expected the repository error, got nil")
+	}
+
+	// A client gone away ends the stream
+	mockRepo = newMockEventRepository()
+	mockRepo.events = []*models.Events{createTestEvent(1, "Meeting", "Description", "Office", false, startTime, endTime)}
+	mockRepo.addChange(mockRepo.events[0], constant.WebhookEventCreated)
+	gone := errors.New("connection closed")
+	usecase = NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	err = usecase.StreamEvents(context.Background(), testActor, &after, func([]*response.EventChangeResponse) error { return gone })
+	if !errors.Is(err, gone) {
+		t.Errorf("Expected the send error, got: %v", err)
+	}
+}
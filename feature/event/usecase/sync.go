@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// syncToken is what a sync token holds: the changes up to Change in the
+// outbox are synced. The outbox rather than UpdatedAt orders them, since
+// its IDs follow commit order and it keeps deletions past the trash. While
+// a snapshot is being taken, Event is the last event sent.
+type syncToken struct {
+	Version     int    `json:"v"`
+	WorkspaceID uint64 `json:"w"`
+	UserID      uint64 `json:"u"`
+	Change      uint64 `json:"c"`
+	Event       uint64 `json:"e,omitempty"`
+}
+
+func (u *eventUsecase) SyncEvents(actor domain.Actor, req *request.SyncRequest) (*response.SyncResponse, error) {
+	token := syncToken{Version: constant.SyncTokenVersion, WorkspaceID: actor.WorkspaceID, UserID: actor.UserID}
+	if req.Since == "" {
+		// Changes made while the snapshot is taken are synced after it,
+		// which may send an event twice but misses none
+		last, err := u.eventRepository.LastEventChangeID()
+		if err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.SyncEvents]: Error getting last change")
+		}
+		token.Change = last
+		return u.syncSnapshot(actor, token, req.Limit)
+	}
+
+	var since syncToken
+	if err := utils.DecodeCursor(req.Since, &since); err != nil {
+		return nil, errors.Wrap(domain.ErrSyncTokenInvalid, "[EventUsecase.SyncEvents]: Error reading sync token")
+	}
+	if since.Version != token.Version || since.WorkspaceID != token.WorkspaceID || since.UserID != token.UserID {
+		return nil, errors.Wrap(domain.ErrSyncTokenInvalid, "[EventUsecase.SyncEvents]: Error reading sync token")
+	}
+	if since.Event != 0 {
+		return u.syncSnapshot(actor, since, req.Limit)
+	}
+	return u.syncChanges(actor, since, req.Limit)
+}
+
+// syncSnapshot sends the next limit events actor can see after
+// token.Event. Once they are all sent the token moves on to the changes
+// made meanwhile, which the client is told to sync right away.
+func (u *eventUsecase) syncSnapshot(actor domain.Actor, token syncToken, limit int) (*response.SyncResponse, error) {
+	events, err := u.eventRepository.GetEventsAfter(actor, token.Event, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SyncEvents]: Error getting events")
+	}
+
+	resp := &response.SyncResponse{
+		Events:  make([]*response.EventResponse, 0, len(events)),
+		Deleted: []*response.EventTombstone{},
+		HasMore: true,
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, newEventResponse(event))
+		token.Event = event.ID
+	}
+	if err := u.setAccess(actor, resp.Events...); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SyncEvents]: Error getting access")
+	}
+
+	if len(events) < limit {
+		token.Event = 0
+	}
+	resp.Token = utils.EncodeCursor(token)
+	return resp, nil
+}
+
+// syncChanges sends the events of the next limit changes after
+// token.Change actor can see, each once, as it is now.
+func (u *eventUsecase) syncChanges(actor domain.Actor, token syncToken, limit int) (*response.SyncResponse, error) {
+	// Read first: every change up to it is committed, so past the last
+	// change actor sees the token skips to it
+	last, err := u.eventRepository.LastEventChangeID()
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SyncEvents]: Error getting last change")
+	}
+	changes, err := u.eventRepository.GetEventChanges(actor, token.Change, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SyncEvents]: Error getting changes")
+	}
+
+	var ids []uint64
+	latest := make(map[uint64]*models.OutboxEvents, len(changes))
+	for _, change := range changes {
+		if _, found := latest[change.EventID]; !found {
+			ids = append(ids, change.EventID)
+		}
+		latest[change.EventID] = change
+		token.Change = change.ID
+	}
+	hasMore := len(changes) == limit
+	if !hasMore {
+		token.Change = max(token.Change, last)
+	}
+
+	events, err := u.eventRepository.GetChangedEvents(actor, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SyncEvents]: Error getting events")
+	}
+	current := make(map[uint64]*models.Events, len(events))
+	for _, event := range events {
+		current[event.ID] = event
+	}
+
+	resp := &response.SyncResponse{
+		Events:  []*response.EventResponse{},
+		Deleted: []*response.EventTombstone{},
+		Token:   utils.EncodeCursor(token),
+		HasMore: hasMore,
+	}
+	for _, id := range ids {
+		event := current[id]
+		switch {
+		case event == nil:
+			// Purged, or out of actor's sight since
+			change := latest[id]
+			tombstone := &response.EventTombstone{ID: id, Version: change.Version}
+			if change.Type == constant.WebhookEventDeleted && change.UserID == nil {
+				tombstone.DeletedAt = change.CreatedAt
+			}
+			resp.Deleted = append(resp.Deleted, tombstone)
+		case event.DeleteAt.Valid:
+			resp.Deleted = append(resp.Deleted, &response.EventTombstone{ID: id, Version: event.Version, DeletedAt: &event.DeleteAt.Time})
+		default:
+			resp.Events = append(resp.Events, newEventResponse(event))
+		}
+	}
+	if err := u.setAccess(actor, resp.Events...); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SyncEvents]: Error getting access")
+	}
+	return resp, nil
+}
+
+func (u *eventUsecase) ApplySyncMutations(actor domain.Actor, req *request.SyncMutationsRequest) (*response.SyncMutationsResponse, error) {
+	resp := &response.SyncMutationsResponse{Results: make([]*response.SyncMutationResult, 0, len(req.Mutations))}
+	for _, mutation := range req.Mutations {
+		result := u.applySyncMutation(actor, mutation)
+		switch result.Status {
+		case constant.SyncApplied:
+			resp.Applied++
+		case constant.SyncConflict:
+			resp.Conflicts++
+		default:
+			resp.Failed++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+func (u *eventUsecase) applySyncMutation(actor domain.Actor, mutation *request.SyncMutation) *response.SyncMutationResult {
+	result := &response.SyncMutationResult{ClientID: mutation.ClientID}
+	if mutation.Op != constant.SyncOpDelete {
+		if mutation.Event == nil {
+			return failSync(result, domain.NewValidationError("event is required", response.FieldError{
+				Field: "event", Rule: "required", Message: "event is required",
+			}))
+		}
+		if err := validateEventRequest(mutation.Event); err != nil {
+			return failSync(result, err)
+		}
+	}
+
+	var err error
+	switch mutation.Op {
+	case constant.SyncOpCreate:
+		result.Event, err = u.CreateEvent(actor, mutation.Event)
+	case constant.SyncOpUpdate:
+		result.Event, err = u.UpdateEvent(actor, mutation.EventID, mutation.Version, mutation.Event)
+	default:
+		// Deleting an event that is already gone changes nothing
+		if err = u.DeleteEvent(actor, mutation.EventID, mutation.Version); errors.Is(err, domain.ErrEventNotFound) {
+			err = nil
+		}
+	}
+
+	switch {
+	case err == nil:
+		result.Status = constant.SyncApplied
+		return result
+	case errors.Is(err, domain.ErrEventVersionMismatch), errors.Is(err, domain.ErrEventNotFound), errors.Is(err, domain.ErrEventUIDConflict):
+		var domainErr *domain.Error
+		errors.As(err, &domainErr)
+		result.Status = constant.SyncConflict
+		result.Message = domainErr.Message
+		if result.Event, err = u.conflictingEvent(actor, mutation); err != nil {
+			return failSync(result, err)
+		}
+		return result
+	default:
+		return failSync(result, err)
+	}
+}
+
+// conflictingEvent is the event a mutation conflicted with as it is now:
+// the one it wrote to or, for a create, the one holding its UID. It is nil
+// once the event is gone.
+func (u *eventUsecase) conflictingEvent(actor domain.Actor, mutation *request.SyncMutation) (*response.EventResponse, error) {
+	id := mutation.EventID
+	if mutation.Op == constant.SyncOpCreate {
+		// UIDs are unique among the events of the calendar's owner
+		ownerID, err := u.calendarOwner(actor, mutation.Event.CalendarID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := u.eventRepository.GetEventByUID(domain.Actor{UserID: ownerID, WorkspaceID: actor.WorkspaceID}, mutation.Event.UID)
+		if err != nil {
+			if errors.Is(err, domain.ErrEventNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		id = event.ID
+	}
+
+	// Only what actor can see of it
+	event, err := u.GetEventByID(actor, id)
+	if errors.Is(err, domain.ErrEventNotFound) {
+		return nil, nil
+	}
+	return event, err
+}
+
+func failSync(result *response.SyncMutationResult, err error) *response.SyncMutationResult {
+	result.Status = constant.SyncFailed
+	result.Event = nil
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		result.Message = domainErr.Message
+		result.Errors = domainErr.Fields
+		return result
+	}
+	log.Error(err)
+	result.Message = "internal error"
+	return result
+}
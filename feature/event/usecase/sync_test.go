@@ -0,0 +1,445 @@
+package usecase
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+)
+
+func syncEvents(t *testing.T, usecase domain.EventUsecase, actor domain.Actor, since string, limit int) *response.SyncResponse {
+	t.Helper()
+	resp, err := usecase.SyncEvents(actor, &request.SyncRequest{Since: since, Limit: limit})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return resp
+}
+
+func syncedIDs(resp *response.SyncResponse) ([]uint64, []uint64) {
+	var events, deleted []uint64
+	for _, event := range resp.Events {
+		events = append(events, event.ID)
+	}
+	for _, tombstone := range resp.Deleted {
+		deleted = append(deleted, tombstone.ID)
+	}
+	return events, deleted
+}
+
+func TestEventUsecase_SyncEvents_Snapshot(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	for id := uint64(1); id <= 3; id++ {
+		event := createTestEvent(id, "Meeting", "Description", "Office", false, startTime, endTime)
+		mockRepo.events = append(mockRepo.events, event)
+		mockRepo.addChange(event, constant.WebhookEventCreated)
+	}
+	// Another user's
+	other := createTestEvent(4, "Dentist", "Description", "Clinic", false, startTime, endTime)
+	other.OwnerID = 2
+	mockRepo.events = append(mockRepo.events, other)
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	first := syncEvents(t, usecase, testActor, "", 2)
+	if events, _ := syncedIDs(first); !slices.Equal(events, []uint64{1, 2}) || !first.HasMore {
+		t.Fatalf("Expected the first two events and more, got %v (more: %v)", events, first.HasMore)
+	}
+	if first.Events[0].Access != constant.CalendarRoleOwner {
+		t.Errorf("Expected the events with their access, got %q", first.Events[0].Access)
+	}
+
+	// Changed while the snapshot is taken
+	if _, err := usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType, []byte(`{"title":"Standup"}`)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	second := syncEvents(t, usecase, testActor, first.Token, 2)
+	if events, _ := syncedIDs(second); !slices.Equal(events, []uint64{3}) || !second.HasMore {
+		t.Fatalf("Expected the last event and the changes made meanwhile to follow, got %v (more: %v)", events, second.HasMore)
+	}
+
+	third := syncEvents(t, usecase, testActor, second.Token, 2)
+	if events, deleted := syncedIDs(third); !slices.Equal(events, []uint64{1}) || len(deleted) != 0 || third.HasMore {
+		t.Fatalf("Expected only the event changed during the snapshot, got %v, deleted %v (more: %v)", events, deleted, third.HasMore)
+	}
+	if third.Events[0].Title != "Standup" {
+		t.Errorf("Expected the event as it is now, got %q", third.Events[0].Title)
+	}
+
+	if events, deleted := syncedIDs(syncEvents(t, usecase, testActor, third.Token, 2)); len(events)+len(deleted) != 0 {
+		t.Errorf("Expected nothing new, got %v, deleted %v", events, deleted)
+	}
+}
+
+func TestEventUsecase_SyncEvents_Changes(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	start := syncEvents(t, usecase, testActor, "", constant.SyncBatchSize)
+	start = syncEvents(t, usecase, testActor, start.Token, constant.SyncBatchSize)
+	if len(start.Events) != 0 || start.HasMore {
+		t.Fatalf("Expected an empty account synced, got %v (more: %v)", start.Events, start.HasMore)
+	}
+
+	var ids []uint64
+	for _, title := range []string{"Meeting", "Lunch", "Dentist"} {
+		event, err := usecase.CreateEvent(testActor, createTestEventRequest(title, "Description", "Office", false, startTime, endTime))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+	meeting, lunch, dentist := ids[0], ids[1], ids[2]
+	if _, err := usecase.PatchEvent(testActor, meeting, 0, constant.MergePatchContentType, []byte(`{"complete":true}`)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := usecase.DeleteEvent(testActor, lunch, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := usecase.PurgeEvent(testActor, dentist, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// A page per change, each event once
+	paged := syncEvents(t, usecase, testActor, start.Token, 1)
+	if events, _ := syncedIDs(paged); !slices.Equal(events, []uint64{meeting}) || !paged.HasMore {
+		t.Errorf("Expected a page of one change with more to come, got %v (more: %v)", events, paged.HasMore)
+	}
+
+	changes := syncEvents(t, usecase, testActor, start.Token, constant.SyncBatchSize)
+	events, deleted := syncedIDs(changes)
+	if !slices.Equal(events, []uint64{meeting}) || !slices.Equal(deleted, []uint64{lunch, dentist}) || changes.HasMore {
+		t.Fatalf("Expected the meeting changed and the others deleted, got %v, deleted %v (more: %v)", events, deleted, changes.HasMore)
+	}
+	if !*changes.Events[0].Complete {
+		t.Error("Expected the meeting as it is now")
+	}
+	for _, tombstone := range changes.Deleted {
+		if tombstone.DeletedAt == nil {
+			t.Errorf("Expected event %d deleted with its deletion time, trashed or purged", tombstone.ID)
+		}
+	}
+
+	if err := usecase.DeleteEvent(testActor, meeting, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.RestoreEvent(testActor, lunch); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	restored := syncEvents(t, usecase, testActor, changes.Token, constant.SyncBatchSize)
+	if events, deleted := syncedIDs(restored); !slices.Equal(events, []uint64{lunch}) || !slices.Equal(deleted, []uint64{meeting}) {
+		t.Errorf("Expected the lunch back and the meeting deleted, got %v, deleted %v", events, deleted)
+	}
+}
+
+func TestEventUsecase_SyncEvents_Shared(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	work := uint64(1)
+	viewer := domain.Actor{UserID: 2}
+	calendars := &mockCalendarRepository{
+		calendars: []*models.Calendars{{ID: work, OwnerID: testActor.UserID, Name: "Work"}},
+		shares: []*models.CalendarShares{
+			{ID: 1, CalendarID: work, UserID: viewer.UserID, Role: constant.CalendarRoleViewer, Status: constant.CalendarShareAccepted},
+		},
+	}
+	mockRepo := newMockEventRepository()
+	mockRepo.calendars = calendars
+	usecase := NewEventUsecase(mockRepo, calendars)
+
+	shared := createTestEvent(1, "Standup", "Description", "Office", false, startTime, endTime)
+	shared.CalendarID = &work
+	private := createTestEvent(2, "Dentist", "Description", "Clinic", false, startTime, endTime)
+	mockRepo.events = []*models.Events{shared, private}
+
+	snapshot := syncEvents(t, usecase, viewer, "", constant.SyncBatchSize)
+	if events, _ := syncedIDs(snapshot); !slices.Equal(events, []uint64{shared.ID}) || snapshot.Events[0].Access != constant.CalendarRoleViewer {
+		t.Fatalf("Expected only the shared event, with the viewer's access, got %v", events)
+	}
+	synced := syncEvents(t, usecase, viewer, snapshot.Token, constant.SyncBatchSize)
+
+	// Updated, then taken out of the shared calendar
+	shared.Version++
+	mockRepo.addChange(shared, constant.WebhookEventUpdated)
+	shared.CalendarID = nil
+	shared.Version++
+	mockRepo.addChange(shared, constant.WebhookEventUpdated)
+	mockRepo.addChange(private, constant.WebhookEventUpdated)
+
+	changes := syncEvents(t, usecase, viewer, synced.Token, constant.SyncBatchSize)
+	if len(changes.Events) != 0 || len(changes.Deleted) != 1 || changes.Deleted[0].ID != shared.ID || changes.Deleted[0].DeletedAt != nil {
+		t.Errorf("Expected the event out of sight dropped without a deletion time, got %v, deleted %+v", changes.Events, changes.Deleted)
+	}
+}
+
+func TestEventUsecase_SyncEvents_DeletedCalendar(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	work, home, archive := uint64(1), uint64(2), uint64(3)
+	mockRepo := newMockEventRepository()
+	calendars := &mockCalendarRepository{
+		calendars: []*models.Calendars{
+			{ID: work, OwnerID: testActor.UserID, Name: "Work"},
+			{ID: home, OwnerID: testActor.UserID, Name: "Home"},
+			{ID: archive, OwnerID: testActor.UserID, Name: "Archive"},
+		},
+		events: mockRepo,
+	}
+	mockRepo.calendars = calendars
+	usecase := NewEventUsecase(mockRepo, calendars)
+
+	standup := createTestEvent(1, "Standup", "Description", "Office", false, startTime, endTime)
+	standup.CalendarID = &work
+	review := createTestEvent(2, "Review", "Description", "Office", false, startTime, endTime)
+	review.CalendarID = &work
+	dinner := createTestEvent(3, "Dinner", "Description", "Home", false, startTime, endTime)
+	dinner.CalendarID = &home
+	dentist := createTestEvent(4, "Dentist", "Description", "Clinic", false, startTime, endTime)
+	mockRepo.events = []*models.Events{standup, review, dinner, dentist}
+
+	snapshot := syncEvents(t, usecase, testActor, "", constant.SyncBatchSize)
+	synced := syncEvents(t, usecase, testActor, snapshot.Token, constant.SyncBatchSize)
+
+	if err := calendars.DeleteCalendar(testActor, work, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	trashed := syncEvents(t, usecase, testActor, synced.Token, constant.SyncBatchSize)
+	events, deleted := syncedIDs(trashed)
+	if len(events) != 0 || !slices.Equal(deleted, []uint64{standup.ID, review.ID}) {
+		t.Fatalf("Expected the events of the calendar deleted, got %v, deleted %v", events, deleted)
+	}
+	for _, tombstone := range trashed.Deleted {
+		if tombstone.DeletedAt == nil {
+			t.Errorf("Expected event %d deleted with its deletion time", tombstone.ID)
+		}
+	}
+
+	if err := calendars.DeleteCalendar(testActor, home, &archive); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	moved := syncEvents(t, usecase, testActor, trashed.Token, constant.SyncBatchSize)
+	if events, deleted := syncedIDs(moved); !slices.Equal(events, []uint64{dinner.ID}) || len(deleted) != 0 {
+		t.Fatalf("Expected the events of the calendar changed, got %v, deleted %v", events, deleted)
+	}
+	if calendarID := moved.Events[0].CalendarID; calendarID == nil || *calendarID != archive {
+		t.Errorf("Expected the event in the calendar it moved to, got %v", calendarID)
+	}
+}
+
+func TestEventUsecase_SyncEvents_RevokedShare(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	work := uint64(1)
+	viewer := domain.Actor{UserID: 2}
+	mockRepo := newMockEventRepository()
+	calendars := &mockCalendarRepository{
+		calendars: []*models.Calendars{{ID: work, OwnerID: testActor.UserID, Name: "Work"}},
+		shares: []*models.CalendarShares{
+			{ID: 1, CalendarID: work, UserID: viewer.UserID, Role: constant.CalendarRoleViewer, Status: constant.CalendarShareAccepted},
+		},
+		events: mockRepo,
+	}
+	mockRepo.calendars = calendars
+	usecase := NewEventUsecase(mockRepo, calendars)
+
+	standup := createTestEvent(1, "Standup", "Description", "Office", false, startTime, endTime)
+	standup.CalendarID = &work
+	review := createTestEvent(2, "Review", "Description", "Office", false, startTime, endTime)
+	review.CalendarID = &work
+	mockRepo.events = []*models.Events{standup, review}
+
+	snapshot := syncEvents(t, usecase, viewer, "", constant.SyncBatchSize)
+	synced := syncEvents(t, usecase, viewer, snapshot.Token, constant.SyncBatchSize)
+	owned := syncEvents(t, usecase, testActor, "", constant.SyncBatchSize)
+	owned = syncEvents(t, usecase, testActor, owned.Token, constant.SyncBatchSize)
+
+	if _, err := usecase.PatchEvent(testActor, standup.ID, 0, "application/merge-patch+json", []byte(`{"calendarId":null}`)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	movedOut := syncEvents(t, usecase, viewer, synced.Token, constant.SyncBatchSize)
+	if events, deleted := syncedIDs(movedOut); len(events) != 0 || !slices.Equal(deleted, []uint64{standup.ID}) {
+		t.Fatalf("Expected the event taken out of the calendar dropped, got %v, deleted %v", events, deleted)
+	}
+	if tombstone := movedOut.Deleted[0]; tombstone.DeletedAt != nil || tombstone.Version != standup.Version {
+		t.Errorf("Expected the event dropped at version %d without a deletion time, got %+v", standup.Version, tombstone)
+	}
+
+	if err := calendars.DeleteCalendarShare(viewer, 1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	revoked := syncEvents(t, usecase, viewer, movedOut.Token, constant.SyncBatchSize)
+	if events, deleted := syncedIDs(revoked); len(events) != 0 || !slices.Equal(deleted, []uint64{review.ID}) {
+		t.Fatalf("Expected the events of the calendar no longer shared dropped, got %v, deleted %v", events, deleted)
+	}
+	if revoked.Deleted[0].DeletedAt != nil {
+		t.Errorf("Expected the event dropped without a deletion time, got %+v", revoked.Deleted[0])
+	}
+
+	// The owner only hears of the move
+	changes := syncEvents(t, usecase, testActor, owned.Token, constant.SyncBatchSize)
+	if events, deleted := syncedIDs(changes); !slices.Equal(events, []uint64{standup.ID}) || len(deleted) != 0 {
+		t.Errorf("Expected only the moved event changed for the owner, got %v, deleted %v", events, deleted)
+	}
+}
+
+func TestEventUsecase_SyncEvents_InvalidToken(t *testing.T) {
+	mockRepo := newMockEventRepository()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	token := syncEvents(t, usecase, testActor, "", constant.SyncBatchSize).Token
+
+	tests := []struct {
+		name  string
+		actor domain.Actor
+		since string
+	}{
+		{name: "malformed", actor: testActor, since: "not a token"},
+		{name: "another user's", actor: domain.Actor{UserID: 2}, since: token},
+		{name: "another workspace's", actor: domain.Actor{UserID: testActor.UserID, WorkspaceID: 3}, since: token},
+		{name: "an older kind", actor: testActor, since: utils.EncodeCursor(syncToken{UserID: testActor.UserID})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := usecase.SyncEvents(tt.actor, &request.SyncRequest{Since: tt.since, Limit: constant.SyncBatchSize})
+			if !errors.Is(err, domain.ErrSyncTokenInvalid) {
+				t.Errorf("Expected ErrSyncTokenInvalid, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_ApplySyncMutations(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	work := uint64(1)
+	viewer := domain.Actor{UserID: 2}
+	calendars := &mockCalendarRepository{
+		calendars: []*models.Calendars{{ID: work, OwnerID: 2, Name: "Work"}},
+		shares: []*models.CalendarShares{
+			{ID: 1, CalendarID: work, UserID: testActor.UserID, Role: constant.CalendarRoleViewer, Status: constant.CalendarShareAccepted},
+		},
+	}
+	mockRepo := newMockEventRepository()
+	mockRepo.calendars = calendars
+
+	current := createTestEvent(1, "Meeting", "Description", "Office", false, startTime, endTime)
+	stale := createTestEvent(2, "Lunch", "Description", "Cafe", false, startTime, endTime)
+	stale.Version = 3
+	uid := "lunch@example.com"
+	stale.UID = &uid
+	doomed := createTestEvent(3, "Dentist", "Description", "Clinic", false, startTime, endTime)
+	readOnly := createTestEvent(5, "Standup", "Description", "Office", false, startTime, endTime)
+	readOnly.OwnerID = viewer.UserID
+	readOnly.CalendarID = &work
+	mockRepo.events = []*models.Events{current, stale, doomed, readOnly}
+	usecase := NewEventUsecase(mockRepo, calendars)
+
+	duplicate := createTestEventRequest("Lunch", "Description", "Cafe", false, startTime, endTime)
+	duplicate.UID = uid
+	untitled := createTestEventRequest("", "Description", "Office", false, startTime, endTime)
+
+	tests := []struct {
+		mutation *request.SyncMutation
+		status   string
+		eventID  uint64 // of the event in the result, 0 for none
+	}{
+		{
+			mutation: &request.SyncMutation{ClientID: "update", Op: constant.SyncOpUpdate, EventID: current.ID, Version: 1,
+				Event: createTestEventRequest("Standup", "Description", "Office", false, startTime, endTime)},
+			status:  constant.SyncApplied,
+			eventID: current.ID,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "stale update", Op: constant.SyncOpUpdate, EventID: stale.ID, Version: 2,
+				Event: createTestEventRequest("Brunch", "Description", "Cafe", false, startTime, endTime)},
+			status:  constant.SyncConflict,
+			eventID: stale.ID,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "stale delete", Op: constant.SyncOpDelete, EventID: stale.ID, Version: 2},
+			status:   constant.SyncConflict,
+			eventID:  stale.ID,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "delete", Op: constant.SyncOpDelete, EventID: doomed.ID, Version: 1},
+			status:   constant.SyncApplied,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "delete again", Op: constant.SyncOpDelete, EventID: doomed.ID, Version: 1},
+			status:   constant.SyncApplied,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "update deleted", Op: constant.SyncOpUpdate, EventID: doomed.ID, Version: 1,
+				Event: createTestEventRequest("Dentist", "Description", "Clinic", true, startTime, endTime)},
+			status: constant.SyncConflict,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "duplicate uid", Op: constant.SyncOpCreate, Event: duplicate},
+			status:   constant.SyncConflict,
+			eventID:  stale.ID,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "untitled", Op: constant.SyncOpCreate, Event: untitled},
+			status:   constant.SyncFailed,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "no event", Op: constant.SyncOpCreate},
+			status:   constant.SyncFailed,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "read only", Op: constant.SyncOpDelete, EventID: readOnly.ID, Version: 1},
+			status:   constant.SyncFailed,
+		},
+		{
+			mutation: &request.SyncMutation{ClientID: "create", Op: constant.SyncOpCreate,
+				Event: createTestEventRequest("Review", "Description", "Office", false, startTime, endTime)},
+			status:  constant.SyncApplied,
+			eventID: 4,
+		},
+	}
+
+	req := &request.SyncMutationsRequest{}
+	for _, tt := range tests {
+		req.Mutations = append(req.Mutations, tt.mutation)
+	}
+	resp, err := usecase.ApplySyncMutations(testActor, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.Applied != 4 || resp.Conflicts != 4 || resp.Failed != 3 || len(resp.Results) != len(tests) {
+		t.Fatalf("Expected 4 applied, 4 conflicts and 3 failed, got %d, %d and %d of %d",
+			resp.Applied, resp.Conflicts, resp.Failed, len(resp.Results))
+	}
+
+	for i, tt := range tests {
+		result := resp.Results[i]
+		if result.ClientID != tt.mutation.ClientID {
+			t.Errorf("Expected result %d for %q, got %q", i, tt.mutation.ClientID, result.ClientID)
+			continue
+		}
+		if result.Status != tt.status {
+			t.Errorf("%s: expected %s, got %s (%s)", result.ClientID, tt.status, result.Status, result.Message)
+		}
+		var eventID uint64
+		if result.Event != nil {
+			eventID = result.Event.ID
+		}
+		if eventID != tt.eventID {
+			t.Errorf("%s: expected event %d in the result, got %d", result.ClientID, tt.eventID, eventID)
+		}
+	}
+
+	if conflict := resp.Results[1].Event; conflict.Title != "Lunch" || conflict.Version != 3 {
+		t.Errorf("Expected the server's event on a conflict, got %q at version %d", conflict.Title, conflict.Version)
+	}
+	if untitled := resp.Results[7]; len(untitled.Errors) == 0 || untitled.Errors[0].Field != "title" {
+		t.Errorf("Expected the failed validation reported by field, got %+v", untitled.Errors)
+	}
+	if current.Title != "Standup" || stale.Title != "Lunch" {
+		t.Errorf("Expected only the current update applied, got %q and %q", current.Title, stale.Title)
+	}
+}
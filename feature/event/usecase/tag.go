@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+)
+
+// normalizeTags folds names to stored tag names and drops duplicates,
+// keeping the first one. List filters are comma separated, so tag names
+// may not contain commas.
+func normalizeTags(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = utils.NormalizeTag(name)
+		if name == "" {
+			return nil, domain.NewValidationError("tags must not be blank", response.FieldError{
+				Field: "tags", Rule: "required", Message: "tags must not be blank",
+			})
+		}
+		if strings.Contains(name, ",") {
+			return nil, domain.NewValidationError("tags must not contain commas", response.FieldError{
+				Field: "tags", Rule: "excludes", Message: "tags must not contain commas",
+			})
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
+
+// setTags gives event the tags named by names. Tags are the event owner's,
+// whoever is tagging the event; the repository resolves them by name.
+func setTags(event *models.Events, names []string) error {
+	normalized, err := normalizeTags(names)
+	if err != nil {
+		return err
+	}
+
+	event.Tags = make([]*models.Tags, 0, len(normalized))
+	for _, name := range normalized {
+		event.Tags = append(event.Tags, &models.Tags{
+			WorkspaceID: event.WorkspaceID,
+			OwnerID:     event.OwnerID,
+			Name:        name,
+		})
+	}
+	return nil
+}
+
+// tagNames lists the names of the tags of event, never nil.
+func tagNames(event *models.Events) []string {
+	names := make([]string, 0, len(event.Tags))
+	for _, tag := range event.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+func TestEventUsecase_CreateEvent_Tags(t *testing.T) {
+	tests := []struct {
+		name           string
+		tags           []string
+		expectedTags   []string
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name:         "no tags",
+			expectedTags: []string{},
+		},
+		{
+			name:         "tags are normalized and deduplicated",
+			tags:         []string{" Exam ", "MATH", "exam", "Final  Week"},
+			expectedTags: []string{"exam", "math", "final week"},
+		},
+		{
+			name:           "blank tag",
+			tags:           []string{"exam", "  "},
+			expectedError:  true,
+			expectedErrMsg: "tags must not be blank",
+		},
+		{
+			name:           "tag with a comma",
+			tags:           []string{"exam,math"},
+			expectedError:  true,
+			expectedErrMsg: "tags must not contain commas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startTime, endTime := getTestTimes()
+			req := createTestEventRequest("Tagged", "Description", "Office", false, startTime, endTime)
+			req.Tags = tt.tags
+
+			mockRepo := newMockEventRepository()
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.CreateEvent(testActor, req)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(result.Tags, tt.expectedTags) {
+				t.Errorf("Expected tags %q, got %q", tt.expectedTags, result.Tags)
+			}
+			for _, tag := range mockRepo.events[0].Tags {
+				if tag.OwnerID != testActor.UserID || tag.WorkspaceID != testActor.WorkspaceID {
+					t.Errorf("Expected tags of the event owner, got %+v", tag)
+				}
+			}
+		})
+	}
+}
+
+func TestEventUsecase_PatchEvent_Tags(t *testing.T) {
+	tests := []struct {
+		name            string
+		patch           string
+		expectedColumns []string
+		expectedTags    []string
+	}{
+		{
+			name:            "tags replaced",
+			patch:           `{"tags": ["Exam", "physics"]}`,
+			expectedColumns: []string{"tags"},
+			expectedTags:    []string{"exam", "physics"},
+		},
+		{
+			name:            "tags cleared",
+			patch:           `{"tags": []}`,
+			expectedColumns: []string{"tags"},
+			expectedTags:    []string{},
+		},
+		{
+			name:            "tags left alone",
+			patch:           `{"title": "Renamed"}`,
+			expectedColumns: []string{"title"},
+			expectedTags:    []string{"exam", "math"},
+		},
+		{
+			name:            "same tags in another case",
+			patch:           `{"tags": ["EXAM", "Math"]}`,
+			expectedColumns: nil,
+			expectedTags:    []string{"exam", "math"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startTime, endTime := getTestTimes()
+			event := createTestEvent(1, "Tagged", "Description", "Office", false, startTime, endTime)
+			event.Tags = []*models.Tags{{ID: 1, OwnerID: 1, Name: "exam"}, {ID: 2, OwnerID: 1, Name: "math"}}
+
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{event}
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType, []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if !slices.Equal(mockRepo.lastColumns, tt.expectedColumns) {
+				t.Errorf("Expected columns %v, got %v", tt.expectedColumns, mockRepo.lastColumns)
+			}
+			if !reflect.DeepEqual(result.Tags, tt.expectedTags) {
+				t.Errorf("Expected tags %q, got %q", tt.expectedTags, result.Tags)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_GetEventList_Tags(t *testing.T) {
+	tests := []struct {
+		name            string
+		tags            string
+		tagMode         string
+		expectedTags    []string
+		expectedAllTags bool
+	}{
+		{
+			name: "no tag filter",
+		},
+		{
+			name:         "any of the tags",
+			tags:         "Exam, math,,exam",
+			expectedTags: []string{"exam", "math"},
+		},
+		{
+			name:            "all of the tags",
+			tags:            "exam,math",
+			tagMode:         constant.TagModeAll,
+			expectedTags:    []string{"exam", "math"},
+			expectedAllTags: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockEventRepository()
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			req := &request.EventListRequest{Tags: tt.tags, TagMode: tt.tagMode}
+			req.Page = 1
+			req.Limit = 10
+			if _, err := usecase.GetEventList(testActor, req); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			filter := mockRepo.lastFilter
+			if !slices.Equal(filter.Tags, tt.expectedTags) || filter.AllTags != tt.expectedAllTags {
+				t.Errorf("Expected tags %v (all %v), got %v (all %v)", tt.expectedTags, tt.expectedAllTags, filter.Tags, filter.AllTags)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// eventType is the type req asks for, an event unless it says otherwise.
+func eventType(req *request.EventRequest) string {
+	if req.Type == "" {
+		return constant.EventTypeEvent
+	}
+	return req.Type
+}
+
+// validateEventTimes checks the window and due date of req against its
+// type: events need a window, tasks may have one, and only tasks have a
+// due date. A window ends after it starts or, when instant, may end as it
+// starts, as imported events without an end do (RFC 5545 section 3.6.1).
+func validateEventTimes(req *request.EventRequest, instant bool) error {
+	if eventType(req) == constant.EventTypeEvent {
+		if req.StartTime == nil || req.EndTime == nil {
+			field := "startTime"
+			if req.StartTime != nil {
+				field = "endTime"
+			}
+			return domain.NewValidationError(field+" is required for events", response.FieldError{
+				Field: field, Rule: "required", Message: field + " is required for events",
+			})
+		}
+		if req.DueAt != nil {
+			return domain.NewValidationError("only tasks have a due date", response.FieldError{
+				Field: "dueAt", Rule: "excluded", Message: "dueAt is only allowed on tasks",
+			})
+		}
+	}
+
+	if (req.StartTime == nil) != (req.EndTime == nil) {
+		return domain.NewValidationError("startTime and endTime go together", response.FieldError{
+			Field: "endTime", Rule: "required_with", Message: "startTime and endTime must both be set or both be left out",
+		})
+	}
+	if req.StartTime != nil && (req.EndTime.Before(*req.StartTime) || !instant && req.EndTime.Equal(*req.StartTime)) {
+		return domain.NewValidationError("startTime must be before endTime", response.FieldError{
+			Field: "endTime", Rule: "gtfield", Message: "endTime must be after startTime",
+		})
+	}
+	return nil
+}
+
+// setTask stores the type, due date and priority of req on event.
+func setTask(event *models.Events, req *request.EventRequest) {
+	event.Type = eventType(req)
+	event.DueAt = req.DueAt
+	event.Priority = priorityLevel(req.Priority)
+}
+
+// setComplete marks event complete or not, stamping when it was completed.
+func setComplete(event *models.Events, complete bool) {
+	if complete && !event.Complete {
+		now := time.Now()
+		event.CompletedAt = &now
+	}
+	if !complete {
+		event.CompletedAt = nil
+	}
+	event.Complete = complete
+}
+
+// priorityLevel is the stored level of a priority name, none when empty.
+func priorityLevel(name string) int {
+	return max(slices.Index(constant.Priorities, strings.ToLower(name)), 0)
+}
+
+func priorityName(level int) string {
+	if level < 0 || level >= len(constant.Priorities) {
+		return constant.Priorities[0]
+	}
+	return constant.Priorities[level]
+}
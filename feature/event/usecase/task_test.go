@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// Helper function to create a task request without a window
+func createTestTaskRequest(title string, dueAt *time.Time, priority string) *request.EventRequest {
+	complete := false
+	return &request.EventRequest{
+		Type:     constant.EventTypeTask,
+		Title:    title,
+		Location: "Desk",
+		Complete: &complete,
+		DueAt:    dueAt,
+		Priority: priority,
+	}
+}
+
+func TestEventUsecase_CreateEvent_Tasks(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	dueAt := endTime.Add(24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		request        func() *request.EventRequest
+		expectedType   string
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name:         "task without a window",
+			request:      func() *request.EventRequest { return createTestTaskRequest("Finish report", &dueAt, "high") },
+			expectedType: constant.EventTypeTask,
+		},
+		{
+			name: "task without a location",
+			request: func() *request.EventRequest {
+				req := createTestTaskRequest("Call the bank", &dueAt, "")
+				req.Location = ""
+				return req
+			},
+			expectedType: constant.EventTypeTask,
+		},
+		{
+			name: "task with a window",
+			request: func() *request.EventRequest {
+				req := createTestTaskRequest("Study", &dueAt, "")
+				req.StartTime, req.EndTime = &startTime, &endTime
+				return req
+			},
+			expectedType: constant.EventTypeTask,
+		},
+		{
+			name: "type defaults to event",
+			request: func() *request.EventRequest {
+				return createTestEventRequest("Meeting", "Description", "Office", false, startTime, endTime)
+			},
+			expectedType: constant.EventTypeEvent,
+		},
+		{
+			name: "event without a window",
+			request: func() *request.EventRequest {
+				req := createTestTaskRequest("Meeting", nil, "")
+				req.Type = constant.EventTypeEvent
+				return req
+			},
+			expectedError:  true,
+			expectedErrMsg: "startTime is required for events",
+		},
+		{
+			name: "event with a due date",
+			request: func() *request.EventRequest {
+				req := createTestEventRequest("Meeting", "Description", "Office", false, startTime, endTime)
+				req.DueAt = &dueAt
+				return req
+			},
+			expectedError:  true,
+			expectedErrMsg: "only tasks have a due date",
+		},
+		{
+			name: "task with half a window",
+			request: func() *request.EventRequest {
+				req := createTestTaskRequest("Study", nil, "")
+				req.StartTime = &startTime
+				return req
+			},
+			expectedError:  true,
+			expectedErrMsg: "startTime and endTime go together",
+		},
+		{
+			name: "recurring task without a start",
+			request: func() *request.EventRequest {
+				req := createTestTaskRequest("Water plants", nil, "")
+				req.RRule = "FREQ=WEEKLY"
+				return req
+			},
+			expectedError:  true,
+			expectedErrMsg: "rrule requires startTime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.request()
+			usecase := NewEventUsecase(newMockEventRepository(), &mockCalendarRepository{})
+			result, err := usecase.CreateEvent(testActor, req)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.Type != tt.expectedType {
+				t.Errorf("Expected type %q, got %q", tt.expectedType, result.Type)
+			}
+			if !equalTimePtr(result.StartTime, req.StartTime) || !equalTimePtr(result.DueAt, req.DueAt) {
+				t.Errorf("Expected start %v and due %v, got %v and %v", req.StartTime, req.DueAt, result.StartTime, result.DueAt)
+			}
+			expectedPriority := req.Priority
+			if expectedPriority == "" {
+				expectedPriority = "none"
+			}
+			if result.Priority != expectedPriority {
+				t.Errorf("Expected priority %q, got %q", expectedPriority, result.Priority)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_CompletedAt(t *testing.T) {
+	mockRepo := newMockEventRepository()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	req := createTestTaskRequest("Finish report", nil, "")
+	created, err := usecase.CreateEvent(testActor, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created.CompletedAt != nil {
+		t.Errorf("Expected an open task to have no completedAt, got %v", created.CompletedAt)
+	}
+
+	completed, err := usecase.PatchEvent(testActor, created.ID, 0, constant.MergePatchContentType, []byte(`{"complete": true}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if completed.CompletedAt == nil {
+		t.Fatal("Expected completedAt set on completion")
+	}
+	completedAt := *completed.CompletedAt
+
+	// Saving a completed task again keeps when it was completed
+	complete := true
+	req.Complete = &complete
+	updated, err := usecase.UpdateEvent(testActor, created.ID, 0, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.CompletedAt == nil || !updated.CompletedAt.Equal(completedAt) {
+		t.Errorf("Expected completedAt %v kept, got %v", completedAt, updated.CompletedAt)
+	}
+
+	reopened, err := usecase.PatchEvent(testActor, created.ID, 0, constant.MergePatchContentType, []byte(`{"complete": false}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if reopened.CompletedAt != nil {
+		t.Errorf("Expected completedAt cleared on reopening, got %v", reopened.CompletedAt)
+	}
+}
+
+func TestEventUsecase_PatchEvent_Tasks(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{createTestEvent(1, "Meeting", "Description", "Office", false, startTime, endTime)}
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	result, err := usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType,
+		[]byte(`{"type": "task", "startTime": null, "endTime": null, "dueAt": "2024-01-05T17:00:00Z", "priority": "urgent"}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expectedColumns := []string{"start_time", "end_time", "type", "due_at", "priority"}
+	if !reflect.DeepEqual(mockRepo.lastColumns, expectedColumns) {
+		t.Errorf("Expected columns %v, got %v", expectedColumns, mockRepo.lastColumns)
+	}
+	if result.Type != constant.EventTypeTask || result.StartTime != nil || result.Priority != "urgent" {
+		t.Errorf("Expected an urgent task without a window, got %+v", result)
+	}
+
+	// Nor does it need a location
+	result, err = usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType, []byte(`{"location": null}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(mockRepo.lastColumns, []string{"location"}) || result.Location != "" {
+		t.Errorf("Expected the location cleared, got %q with columns %v", result.Location, mockRepo.lastColumns)
+	}
+}
+
+func TestEventUsecase_GetEventList_TaskSort(t *testing.T) {
+	mockRepo := newMockEventRepository()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	req := &request.EventListRequest{Sort: "-priority,dueAt", Type: constant.EventTypeTask}
+	req.Page = 1
+	req.Limit = 10
+	if _, err := usecase.GetEventList(testActor, req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []domain.SortField{{Column: "priority", Desc: true}, {Column: "due_at"}}
+	if !reflect.DeepEqual(mockRepo.lastFilter.Sort, expected) || mockRepo.lastFilter.Type != constant.EventTypeTask {
+		t.Errorf("Expected tasks sorted by %v, got %+v", expected, mockRepo.lastFilter)
+	}
+
+	// Expanded lists are sorted in memory, due dates missing last
+	early := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	late := early.Add(24 * time.Hour)
+	events := []*response.EventResponse{
+		{ID: 1, Priority: "low", DueAt: &early},
+		{ID: 2, Priority: "urgent"},
+		{ID: 3, Priority: "urgent", DueAt: &late},
+		{ID: 4, Priority: "none", DueAt: &early},
+	}
+	sortEventResponses(events, expected)
+	var order []uint64
+	for _, event := range events {
+		order = append(order, event.ID)
+	}
+	if !reflect.DeepEqual(order, []uint64{3, 2, 1, 4}) {
+		t.Errorf("Expected order [3 2 1 4], got %v", order)
+	}
+}
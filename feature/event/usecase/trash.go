@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+func (u *eventUsecase) GetDeletedEventList(actor domain.Actor, req *request.PaginationRequest) (*response.PaginatedResponse[*response.EventResponse], error) {
+	filter := &domain.EventFilter{Page: req.Page, Limit: req.Limit}
+
+	events, total, err := u.eventRepository.GetDeletedEventList(actor, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetDeletedEventList]: Error getting deleted event list")
+	}
+
+	var eventResponses []*response.EventResponse
+	for _, event := range events {
+		eventResponses = append(eventResponses, newEventResponse(event))
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	return &response.PaginatedResponse[*response.EventResponse]{
+		Data: eventResponses,
+		Pagination: response.Pagination{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+func (u *eventUsecase) RestoreEvent(actor domain.Actor, id uint64) (*response.EventResponse, error) {
+	event, err := u.eventRepository.GetDeletedEventByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.RestoreEvent]: Error getting deleted event")
+	}
+
+	// The UID may have been taken again, e.g. by an import, while in the trash
+	if event.UID != nil {
+		if _, err := u.eventRepository.GetEventByUID(actor, *event.UID); err == nil {
+			return nil, errors.Wrap(domain.ErrEventUIDConflict, "[EventUsecase.RestoreEvent]: Duplicate uid")
+		} else if !errors.Is(err, domain.ErrEventNotFound) {
+			return nil, errors.Wrap(err, "[EventUsecase.RestoreEvent]: Error checking uid")
+		}
+	}
+
+	// Its calendar may have been deleted meanwhile
+	if event.CalendarID != nil {
+		if _, err := u.calendarRepository.GetCalendarByID(actor, *event.CalendarID); errors.Is(err, domain.ErrCalendarNotFound) {
+			event.CalendarID = nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.RestoreEvent]: Error getting calendar")
+		}
+	}
+
+	event.Changes = append(event.Changes, constant.WebhookEventCreated)
+	if err := u.eventRepository.RestoreEvent(event); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.RestoreEvent]: Error restoring event")
+	}
+
+	return newEventResponse(event), nil
+}
+
+func (u *eventUsecase) PurgeEvent(actor domain.Actor, id, version uint64) error {
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil && !errors.Is(err, domain.ErrEventNotFound) {
+		return errors.Wrap(err, "[EventUsecase.PurgeEvent]: Error getting event")
+	}
+
+	if event == nil {
+		event, err = u.eventRepository.GetDeletedEventByID(actor, id)
+		if err != nil {
+			return errors.Wrap(err, "[EventUsecase.PurgeEvent]: Error getting deleted event")
+		}
+	}
+
+	// Editors of a shared calendar may only move its events to the trash
+	if event.OwnerID != actor.UserID {
+		return errors.Wrap(domain.ErrEventNotOwner, "[EventUsecase.PurgeEvent]: Error purging event")
+	}
+
+	if version != 0 && event.Version != version {
+		return errors.Wrap(domain.ErrEventVersionMismatch, "[EventUsecase.PurgeEvent]: Error purging event")
+	}
+
+	if err := u.eventRepository.PurgeEvent(actor, id, version); err != nil {
+		return errors.Wrap(err, "[EventUsecase.PurgeEvent]: Error purging event")
+	}
+	return nil
+}
+
+func (u *eventUsecase) PurgeDeletedEvents(retention time.Duration) (int64, error) {
+	purged, err := u.eventRepository.PurgeDeletedEvents(time.Now().Add(-retention))
+	if err != nil {
+		return 0, errors.Wrap(err, "[EventUsecase.PurgeDeletedEvents]: Error purging deleted events")
+	}
+	return purged, nil
+}
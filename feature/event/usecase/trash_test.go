@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"gorm.io/gorm"
+)
+
+// Helper function to create an event that has been in the trash since deletedAt
+func createDeletedTestEvent(id uint64, title string, deletedAt time.Time) *models.Events {
+	startTime, endTime := getTestTimes()
+	event := createTestEvent(id, title, "Description", "Office", false, startTime, endTime)
+	event.DeleteAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+	return event
+}
+
+func TestEventUsecase_GetDeletedEventList(t *testing.T) {
+	deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := newMockEventRepository()
+	mockRepo.deleted = []*models.Events{
+		createDeletedTestEvent(1, "Trashed 1", deletedAt),
+		createDeletedTestEvent(2, "Trashed 2", deletedAt),
+	}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.GetDeletedEventList(testActor, &request.PaginationRequest{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(result.Data) != 2 || result.Pagination.Total != 2 || result.Pagination.TotalPages != 1 {
+		t.Errorf("Expected 2 deleted events on 1 page, got %d events, pagination %+v", len(result.Data), result.Pagination)
+	}
+	for _, event := range result.Data {
+		if event.DeletedAt == nil || !event.DeletedAt.Equal(deletedAt) {
+			t.Errorf("Expected deletedAt %v, got %v", deletedAt, event.DeletedAt)
+		}
+	}
+}
+
+func TestEventUsecase_RestoreEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             uint64
+		activeUID      *string
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name: "restore from trash",
+			id:   1,
+		},
+		{
+			name:           "event not in trash",
+			id:             2,
+			expectedError:  true,
+			expectedErrMsg: "event not found",
+		},
+		{
+			name:           "uid taken while in trash",
+			id:             1,
+			activeUID:      stringPtr("trashed@example.com"),
+			expectedError:  true,
+			expectedErrMsg: "uid already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startTime, endTime := getTestTimes()
+			trashed := createDeletedTestEvent(1, "Trashed", time.Now())
+			trashed.UID = stringPtr("trashed@example.com")
+			active := createTestEvent(2, "Active", "Description", "Office", false, startTime, endTime)
+			active.UID = tt.activeUID
+
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{active}
+			mockRepo.deleted = []*models.Events{trashed}
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.RestoreEvent(testActor, tt.id)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.DeletedAt != nil || result.Version != 2 {
+				t.Errorf("Expected a restored event at version 2, got deletedAt %v version %d", result.DeletedAt, result.Version)
+			}
+			if len(mockRepo.deleted) != 0 || len(mockRepo.events) != 2 {
+				t.Errorf("Expected the event out of the trash, got %d active and %d deleted", len(mockRepo.events), len(mockRepo.deleted))
+			}
+		})
+	}
+}
+
+func TestEventUsecase_PurgeEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             uint64
+		version        uint64
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name: "purge active event",
+			id:   1,
+		},
+		{
+			name:    "purge trashed event",
+			id:      2,
+			version: 1,
+		},
+		{
+			name:           "version mismatch",
+			id:             2,
+			version:        5,
+			expectedError:  true,
+			expectedErrMsg: "event has been modified",
+		},
+		{
+			name:           "event not found",
+			id:             3,
+			expectedError:  true,
+			expectedErrMsg: "event not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startTime, endTime := getTestTimes()
+			mockRepo := newMockEventRepository()
+			mockRepo.events = []*models.Events{createTestEvent(1, "Active", "Description", "Office", false, startTime, endTime)}
+			mockRepo.deleted = []*models.Events{createDeletedTestEvent(2, "Trashed", time.Now())}
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			err := usecase.PurgeEvent(testActor, tt.id, tt.version)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if len(mockRepo.events)+len(mockRepo.deleted) != 1 {
+				t.Errorf("Expected the event gone for good, got %d active and %d deleted", len(mockRepo.events), len(mockRepo.deleted))
+			}
+		})
+	}
+}
+
+func TestEventUsecase_PurgeDeletedEvents(t *testing.T) {
+	now := time.Now()
+	mockRepo := newMockEventRepository()
+	mockRepo.deleted = []*models.Events{
+		createDeletedTestEvent(1, "Expired", now.Add(-48*time.Hour)),
+		createDeletedTestEvent(2, "Recent", now.Add(-time.Hour)),
+	}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	purged, err := usecase.PurgeDeletedEvents(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if purged != 1 || len(mockRepo.deleted) != 1 || mockRepo.deleted[0].ID != 2 {
+		t.Errorf("Expected only the expired event purged, got %d purged and %d left", purged, len(mockRepo.deleted))
+	}
+}
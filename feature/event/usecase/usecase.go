@@ -1,151 +1,729 @@
 package usecase
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
 	"github.com/pubestpubest/g12-todo-backend/domain"
 	"github.com/pubestpubest/g12-todo-backend/models"
 	"github.com/pubestpubest/g12-todo-backend/request"
 	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
 )
 
 type eventUsecase struct {
-	eventRepository domain.EventRepository
+	eventRepository    domain.EventRepository
+	calendarRepository domain.CalendarRepository
+}
+
+func NewEventUsecase(eventRepository domain.EventRepository, calendarRepository domain.CalendarRepository) domain.EventUsecase {
+	return &eventUsecase{eventRepository: eventRepository, calendarRepository: calendarRepository}
 }
 
-func NewEventUsecase(eventRepository domain.EventRepository) domain.EventUsecase {
-	return &eventUsecase{eventRepository: eventRepository}
+// eventSortColumns maps the sort keys accepted by the API to their columns.
+var eventSortColumns = map[string]string{
+	"title":     "title",
+	"location":  "location",
+	"complete":  "complete",
+	"status":    "status",
+	"position":  "position",
+	"startTime": "start_time",
+	"endTime":   "end_time",
+	"dueAt":     "due_at",
+	"priority":  "priority",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
 }
 
-func (u *eventUsecase) GetEventList(page, limit int) (*response.PaginatedResponse[*response.EventResponse], error) {
-	events, total, err := u.eventRepository.GetEventList(page, limit)
+func (u *eventUsecase) GetEventList(actor domain.Actor, req *request.EventListRequest) (*response.PaginatedResponse[*response.EventResponse], error) {
+	filter, err := newEventFilter(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Invalid filter")
+	}
+
+	// A bounded window lists occurrences of recurring events
+	if filter.From != nil && filter.To != nil {
+		if req.Cursor != nil {
+			return nil, errors.Wrap(errCursorUnsupported("lists of occurrences, between from and to"), "[EventUsecase.GetEventList]: Invalid cursor")
+		}
+		return u.getExpandedEventList(actor, filter)
+	}
+
+	if req.Cursor != nil {
+		return u.getCursorEventList(actor, filter, *req.Cursor)
+	}
+
+	events, total, err := u.eventRepository.GetEventList(actor, filter)
 	if err != nil {
 		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error getting event list")
 	}
 
 	var eventResponses []*response.EventResponse
 	for _, event := range events {
-		eventResponses = append(eventResponses, &response.EventResponse{
-			ID:          event.ID,
-			Title:       event.Title,
-			Description: event.Description,
-			Complete:    &event.Complete,
-			CreatedAt:   event.CreatedAt,
-			UpdatedAt:   event.UpdatedAt,
-			Location:    event.Location,
-			StartTime:   event.StartTime,
-			EndTime:     event.EndTime,
-		})
+		eventResponses = append(eventResponses, newEventResponse(event))
+	}
+	if err := u.setAccess(actor, eventResponses...); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error getting access")
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	return &response.PaginatedResponse[*response.EventResponse]{
+		Data: eventResponses,
+		Pagination: response.Pagination{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+func (u *eventUsecase) getExpandedEventList(actor domain.Actor, filter *domain.EventFilter) (*response.PaginatedResponse[*response.EventResponse], error) {
+	filter.Expand = true
+	events, _, err := u.eventRepository.GetEventList(actor, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error getting event list")
+	}
+
+	var recurringIDs []uint64
+	for _, event := range events {
+		if event.RRule != nil {
+			recurringIDs = append(recurringIDs, event.ID)
+		}
+	}
+	exceptions, err := u.eventRepository.GetEventExceptions(actor, recurringIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error getting event exceptions")
+	}
+
+	expanded, err := expandEvents(events, exceptions, *filter.From, *filter.To)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error expanding recurring events")
+	}
+
+	matched := make([]*response.EventResponse, 0, len(expanded))
+	for _, event := range expanded {
+		if matchesEventFilter(event, filter) {
+			matched = append(matched, event)
+		}
+	}
+	sortEventResponses(matched, filter.Sort)
+	if err := u.setAccess(actor, matched...); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventList]: Error getting access")
 	}
 
-	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	total := len(matched)
+	start := min((filter.Page-1)*filter.Limit, total)
+	end := min(start+filter.Limit, total)
+
+	return &response.PaginatedResponse[*response.EventResponse]{
+		Data: matched[start:end],
+		Pagination: response.Pagination{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			Total:      total,
+			TotalPages: (total + filter.Limit - 1) / filter.Limit,
+		},
+	}, nil
+}
+
+func (u *eventUsecase) SearchEvents(actor domain.Actor, req *request.EventSearchRequest) (*response.PaginatedResponse[*response.EventResponse], error) {
+	filter, err := newEventFilter(&req.EventListRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SearchEvents]: Invalid filter")
+	}
+
+	if req.Cursor != nil {
+		return nil, errors.Wrap(errCursorUnsupported("search"), "[EventUsecase.SearchEvents]: Invalid cursor")
+	}
+
+	filter.Query = strings.TrimSpace(req.Query)
+	if filter.Query == "" {
+		return nil, errors.Wrap(domain.NewValidationError("q must not be blank", response.FieldError{
+			Field: "q", Rule: "required", Message: "q must not be blank",
+		}), "[EventUsecase.SearchEvents]: Invalid query")
+	}
+
+	results, total, err := u.eventRepository.SearchEvents(actor, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SearchEvents]: Error searching events")
+	}
+
+	eventResponses := make([]*response.EventResponse, 0, len(results))
+	for _, result := range results {
+		eventResponse := newEventResponse(&result.Events)
+		eventResponse.Score = &result.Rank
+		eventResponse.Snippet = &result.Snippet
+		eventResponses = append(eventResponses, eventResponse)
+	}
+	if err := u.setAccess(actor, eventResponses...); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.SearchEvents]: Error getting access")
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
 
 	return &response.PaginatedResponse[*response.EventResponse]{
 		Data: eventResponses,
 		Pagination: response.Pagination{
-			Page:       page,
-			Limit:      limit,
+			Page:       filter.Page,
+			Limit:      filter.Limit,
 			Total:      int(total),
 			TotalPages: totalPages,
 		},
 	}, nil
 }
 
-func (u *eventUsecase) GetEventByID(id uint64) (*response.EventResponse, error) {
-	event, err := u.eventRepository.GetEventByID(id)
+func newEventFilter(req *request.EventListRequest) (*domain.EventFilter, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, domain.NewValidationError("from must be before to", response.FieldError{
+			Field: "to", Rule: "gtfield", Message: "to must be after from",
+		})
+	}
+
+	filter := &domain.EventFilter{
+		Page:       req.Page,
+		Limit:      req.Limit,
+		CalendarID: req.CalendarID,
+		Type:       req.Type,
+		Complete:   req.Complete,
+		Status:     utils.NormalizeStatus(req.Status),
+		Location:   strings.TrimSpace(req.Location),
+		From:       req.From,
+		To:         req.To,
+		AllTags:    req.TagMode == constant.TagModeAll,
+		WithTotal:  req.WithTotal,
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(req.Tags, ",") {
+		tag = utils.NormalizeTag(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	for _, key := range strings.Split(req.Sort, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		desc := strings.HasPrefix(key, "-")
+		column, ok := eventSortColumns[strings.TrimPrefix(key, "-")]
+		if !ok {
+			message := fmt.Sprintf("unsupported sort key %q", strings.TrimPrefix(key, "-"))
+			return nil, domain.NewValidationError(message, response.FieldError{
+				Field: "sort", Rule: "oneof", Message: message,
+			})
+		}
+		filter.Sort = append(filter.Sort, domain.SortField{Column: column, Desc: desc})
+	}
+
+	if len(filter.Sort) == 0 {
+		filter.Sort = []domain.SortField{{Column: "start_time"}}
+	}
+
+	return filter, nil
+}
+
+func (u *eventUsecase) GetEventByID(actor domain.Actor, id uint64) (*response.EventResponse, error) {
+	event, err := u.eventRepository.GetEventByID(actor, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[EventUsecase.GetEventByID]: Error getting event")
 	}
 
 	if event == nil {
-		return nil, errors.New("event not found")
+		return nil, errors.Wrap(domain.ErrEventNotFound, "[EventUsecase.GetEventByID]: Error getting event")
 	}
 
-	return &response.EventResponse{
-		ID:          event.ID,
-		Title:       event.Title,
-		Description: event.Description,
-		Complete:    &event.Complete,
-		CreatedAt:   event.CreatedAt,
-		UpdatedAt:   event.UpdatedAt,
-		Location:    event.Location,
-		StartTime:   event.StartTime,
-		EndTime:     event.EndTime,
-	}, nil
+	eventResponse := newEventResponse(event)
+	if err := u.setAccess(actor, eventResponse); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.GetEventByID]: Error getting access")
+	}
+	return eventResponse, nil
+}
+
+func (u *eventUsecase) CreateEvent(actor domain.Actor, req *request.EventRequest) (*response.EventResponse, error) {
+	return u.createEvent(actor, req, false)
 }
 
-func (u *eventUsecase) CreateEvent(req *request.EventRequest) (*response.EventResponse, error) {
+// createEvent creates the event of req, which may last no time at all when
+// instant.
+func (u *eventUsecase) createEvent(actor domain.Actor, req *request.EventRequest, instant bool) (*response.EventResponse, error) {
+	if err := validateEventTimes(req, instant); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Invalid event")
+	}
+
+	// Events in a shared calendar belong to the owner of the calendar
+	ownerID, err := u.calendarOwner(actor, req.CalendarID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Invalid calendar")
+	}
 
-	if req.StartTime.After(req.EndTime) || req.StartTime.Equal(req.EndTime) {
-		return nil, errors.New("[EventUsecase.CreateEvent]: startTime must be before endTime")
+	uid := req.UID
+	if uid == "" {
+		uid = uuid.NewString()
+	} else if _, err := u.eventRepository.GetEventByUID(domain.Actor{UserID: ownerID, WorkspaceID: actor.WorkspaceID}, uid); err == nil {
+		return nil, errors.Wrap(domain.ErrEventUIDConflict, "[EventUsecase.CreateEvent]: Duplicate uid")
+	} else if !errors.Is(err, domain.ErrEventNotFound) {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Error checking uid")
 	}
 
 	event := &models.Events{
-		Title:       req.Title,
-		Description: &req.Description,
-		Complete:    *req.Complete,
-		Location:    req.Location,
-		StartTime:   req.StartTime,
-		EndTime:     req.EndTime,
+		OwnerID:      ownerID,
+		WorkspaceID:  actor.WorkspaceID,
+		CalendarID:   req.CalendarID,
+		UID:          &uid,
+		Title:        req.Title,
+		Description:  &req.Description,
+		Location:     req.Location,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		AutoComplete: req.AutoComplete,
+
+		AllowBlockedCompletion: req.AllowBlockedCompletion,
+	}
+	setTask(event, req)
+	setComplete(event, *req.Complete)
+	timezone, err := u.recurrenceTimezone(actor, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Error getting calendar")
+	}
+	if err := setRecurrence(event, req, timezone); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Invalid recurrence")
+	}
+	if err := setTags(event, req.Tags); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Invalid tags")
+	}
+	if err := u.setBlockers(actor, event, req.BlockedBy); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Invalid blockers")
+	}
+	if err := u.setEventStatus(actor, event, req.Status, false); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Invalid status")
+	}
+	if err := setReminders(event, req.Reminders, false); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Invalid reminders")
+	}
+	if err := checkBlocked(event, false); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Error completing event")
 	}
 
+	event.Changes = append(event.Changes, constant.WebhookEventCreated)
 	if err := u.eventRepository.CreateEvent(event); err != nil {
 		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Error creating event")
 	}
 
-	return &response.EventResponse{
-		ID:          event.ID,
-		Title:       event.Title,
-		Description: event.Description,
-		Complete:    &event.Complete,
-		CreatedAt:   event.CreatedAt,
-		UpdatedAt:   event.UpdatedAt,
-		Location:    event.Location,
-		StartTime:   event.StartTime,
-		EndTime:     event.EndTime,
-	}, nil
+	eventResponse := newEventResponse(event)
+	if err := u.setAccess(actor, eventResponse); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.CreateEvent]: Error getting access")
+	}
+	return eventResponse, nil
 }
 
-func (u *eventUsecase) UpdateEvent(id uint64, req *request.EventRequest) (*response.EventResponse, error) {
-	if req.StartTime.After(req.EndTime) || req.StartTime.Equal(req.EndTime) {
-		return nil, errors.New("[EventUsecase.UpdateEvent]: startTime must be before endTime")
+func (u *eventUsecase) UpdateEvent(actor domain.Actor, id, version uint64, req *request.EventRequest) (*response.EventResponse, error) {
+	if err := validateEventTimes(req, false); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Invalid event")
 	}
 
-	event, err := u.eventRepository.GetEventByID(id)
+	event, err := u.eventRepository.GetEventByID(actor, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Error getting event")
 	}
 
 	if event == nil {
-		return nil, errors.New("event not found")
+		return nil, errors.Wrap(domain.ErrEventNotFound, "[EventUsecase.UpdateEvent]: Error getting event")
+	}
+
+	if version != 0 && event.Version != version {
+		return nil, errors.Wrap(domain.ErrEventVersionMismatch, "[EventUsecase.UpdateEvent]: Error updating event")
 	}
 
+	if err := u.checkEditAccess(actor, event); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Error checking access")
+	}
+
+	if err := u.validateCalendar(actor, req.CalendarID, event.OwnerID); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Invalid calendar")
+	}
+
+	moved := !equalUint64Ptr(req.CalendarID, event.CalendarID)
+	previous := *event
+	if moved {
+		event.PreviousCalendarID = event.CalendarID
+	}
+	event.CalendarID = req.CalendarID
 	event.Title = req.Title
 	event.Description = &req.Description
-	event.Complete = *req.Complete
 	event.Location = req.Location
 	event.StartTime = req.StartTime
 	event.EndTime = req.EndTime
+	event.AutoComplete = req.AutoComplete
+	event.AllowBlockedCompletion = req.AllowBlockedCompletion
+	wasComplete := event.Complete
+	setTask(event, req)
+	setComplete(event, *req.Complete)
+	timezone, err := u.recurrenceTimezone(actor, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Error getting calendar")
+	}
+	if err := setRecurrence(event, req, timezone); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Invalid recurrence")
+	}
+	if err := setTags(event, req.Tags); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Invalid tags")
+	}
+	if err := u.setBlockers(actor, event, req.BlockedBy); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Invalid blockers")
+	}
+	if err := u.setEventStatus(actor, event, req.Status, moved); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Invalid status")
+	}
+	if err := setReminders(event, req.Reminders, remindersMoved(&previous, event)); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Invalid reminders")
+	}
+	if err := checkBlocked(event, wasComplete); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Error completing event")
+	}
 
+	publishChange(event, wasComplete)
 	if err := u.eventRepository.UpdateEvent(event); err != nil {
 		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Error updating event")
 	}
 
-	return &response.EventResponse{
+	eventResponse := newEventResponse(event)
+	if err := u.setAccess(actor, eventResponse); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.UpdateEvent]: Error getting access")
+	}
+	return eventResponse, nil
+}
+
+func (u *eventUsecase) PatchEvent(actor domain.Actor, id, version uint64, contentType string, patch []byte) (*response.EventResponse, error) {
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Error getting event")
+	}
+
+	if event == nil {
+		return nil, errors.Wrap(domain.ErrEventNotFound, "[EventUsecase.PatchEvent]: Error getting event")
+	}
+
+	if version != 0 && event.Version != version {
+		return nil, errors.Wrap(domain.ErrEventVersionMismatch, "[EventUsecase.PatchEvent]: Error patching event")
+	}
+
+	if err := u.checkEditAccess(actor, event); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Error checking access")
+	}
+
+	wasComplete := event.Complete
+	previous := *event
+	current := request.EventRequest{
+		Type:          event.Type,
+		CalendarID:    event.CalendarID,
+		Title:         event.Title,
+		Location:      event.Location,
+		StartTime:     event.StartTime,
+		EndTime:       event.EndTime,
+		DueAt:         event.DueAt,
+		Priority:      priorityName(event.Priority),
+		Complete:      &event.Complete,
+		Tags:          tagNames(event),
+		ExDates:       event.ExDates,
+		RecurrenceEnd: event.RecurrenceEnd,
+		AutoComplete:  event.AutoComplete,
+
+		BlockedBy:              blockerIDs(event),
+		AllowBlockedCompletion: event.AllowBlockedCompletion,
+
+		Status:    event.Status,
+		Reminders: reminderMinutes(event),
+	}
+	if event.Description != nil {
+		current.Description = *event.Description
+	}
+	if event.RRule != nil {
+		current.RRule = *event.RRule
+	}
+	if event.Timezone != nil {
+		current.Timezone = *event.Timezone
+	}
+
+	req, err := applyEventPatch(&current, contentType, patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Error applying patch")
+	}
+
+	if err := validateEventRequest(req); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid event")
+	}
+
+	if err := validateEventTimes(req, false); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid event")
+	}
+
+	var columns []string
+	if !equalUint64Ptr(req.CalendarID, current.CalendarID) {
+		if err := u.validateCalendar(actor, req.CalendarID, event.OwnerID); err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid calendar")
+		}
+		event.PreviousCalendarID = current.CalendarID
+		event.CalendarID = req.CalendarID
+		columns = append(columns, "calendar_id")
+	}
+	if req.Title != current.Title {
+		event.Title = req.Title
+		columns = append(columns, "title")
+	}
+	if req.Description != current.Description {
+		event.Description = &req.Description
+		columns = append(columns, "description")
+	}
+	if req.Location != current.Location {
+		event.Location = req.Location
+		columns = append(columns, "location")
+	}
+	if !equalTimePtr(req.StartTime, current.StartTime) {
+		event.StartTime = req.StartTime
+		columns = append(columns, "start_time")
+	}
+	if !equalTimePtr(req.EndTime, current.EndTime) {
+		event.EndTime = req.EndTime
+		columns = append(columns, "end_time")
+	}
+	if *req.Complete != wasComplete {
+		setComplete(event, *req.Complete)
+	}
+	if req.AutoComplete != current.AutoComplete {
+		event.AutoComplete = req.AutoComplete
+		columns = append(columns, "auto_complete")
+	}
+	if req.AllowBlockedCompletion != current.AllowBlockedCompletion {
+		event.AllowBlockedCompletion = req.AllowBlockedCompletion
+		columns = append(columns, "allow_blocked_completion")
+	}
+
+	itemType, dueAt, priority := event.Type, event.DueAt, event.Priority
+	setTask(event, req)
+	if event.Type != itemType {
+		columns = append(columns, "type")
+	}
+	if !equalTimePtr(dueAt, event.DueAt) {
+		columns = append(columns, "due_at")
+	}
+	if event.Priority != priority {
+		columns = append(columns, "priority")
+	}
+
+	rule, exDates, recurrenceEnd, timezone := event.RRule, event.ExDates, event.RecurrenceEnd, event.Timezone
+	recurrenceTimezone, err := u.recurrenceTimezone(actor, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Error getting calendar")
+	}
+	if err := setRecurrence(event, req, recurrenceTimezone); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid recurrence")
+	}
+	if !equalStringPtr(rule, event.RRule) {
+		columns = append(columns, "rrule")
+	}
+	if !slices.EqualFunc(exDates, event.ExDates, time.Time.Equal) {
+		columns = append(columns, "ex_dates")
+	}
+	if !equalTimePtr(recurrenceEnd, event.RecurrenceEnd) {
+		columns = append(columns, "recurrence_end")
+	}
+	if !equalStringPtr(timezone, event.Timezone) {
+		columns = append(columns, "timezone")
+	}
+	if err := setTags(event, req.Tags); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid tags")
+	}
+	if !slices.Equal(tagNames(event), current.Tags) {
+		columns = append(columns, "tags")
+	}
+	if err := u.setBlockers(actor, event, req.BlockedBy); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid blockers")
+	}
+	if !slices.Equal(blockerIDs(event), current.BlockedBy) {
+		columns = append(columns, "blocked_by")
+	}
+
+	// Keeping the status lets complete pick the state
+	status, position := "", event.Position
+	if req.Status != current.Status {
+		status = req.Status
+	}
+	if err := u.setEventStatus(actor, event, status, slices.Contains(columns, "calendar_id")); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid status")
+	}
+	if event.Status != current.Status {
+		columns = append(columns, "status")
+	}
+	if event.Position != position {
+		columns = append(columns, "position")
+	}
+	if event.Complete != wasComplete {
+		columns = append(columns, "complete", "completed_at")
+	}
+	rescheduled := remindersMoved(&previous, event) && len(event.Reminders) > 0
+	if err := setReminders(event, req.Reminders, rescheduled); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Invalid reminders")
+	}
+	if rescheduled || !slices.Equal(reminderMinutes(event), current.Reminders) {
+		columns = append(columns, "reminders")
+	}
+	if err := checkBlocked(event, wasComplete); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Error completing event")
+	}
+
+	if len(columns) > 0 {
+		publishChange(event, wasComplete)
+		if err := u.eventRepository.PatchEvent(event, columns); err != nil {
+			return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Error patching event")
+		}
+	}
+
+	eventResponse := newEventResponse(event)
+	if err := u.setAccess(actor, eventResponse); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.PatchEvent]: Error getting access")
+	}
+	return eventResponse, nil
+}
+
+// applyEventPatch applies a JSON Merge Patch or JSON Patch document to the
+// JSON form of current and decodes the result.
+func applyEventPatch(current *request.EventRequest, contentType string, patch []byte) (*request.EventRequest, error) {
+	document, err := json.Marshal(current)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error encoding event")
+	}
+
+	switch contentType {
+	case constant.MergePatchContentType:
+		document, err = jsonpatch.MergePatch(document, patch)
+	case constant.JSONPatchContentType:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			document, err = operations.Apply(document)
+		}
+	default:
+		return nil, domain.NewValidationError(fmt.Sprintf("unsupported patch content type %q", contentType))
+	}
+	if err != nil {
+		return nil, domain.NewValidationError("invalid patch: " + err.Error())
+	}
+
+	var patched request.EventRequest
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return nil, domain.NewValidationError("invalid patched event: " + err.Error())
+	}
+
+	return &patched, nil
+}
+
+func (u *eventUsecase) DeleteEvent(actor domain.Actor, id, version uint64) error {
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil {
+		return errors.Wrap(err, "[EventUsecase.DeleteEvent]: Error getting event")
+	}
+
+	if event == nil {
+		return errors.Wrap(domain.ErrEventNotFound, "[EventUsecase.DeleteEvent]: Error getting event")
+	}
+
+	if version != 0 && event.Version != version {
+		return errors.Wrap(domain.ErrEventVersionMismatch, "[EventUsecase.DeleteEvent]: Error deleting event")
+	}
+
+	if err := u.checkEditAccess(actor, event); err != nil {
+		return errors.Wrap(err, "[EventUsecase.DeleteEvent]: Error checking access")
+	}
+
+	if err := u.eventRepository.DeleteEvent(actor, id, version); err != nil {
+		return errors.Wrap(err, "[EventUsecase.DeleteEvent]: Error deleting event")
+	}
+	return nil
+}
+
+// publishChange has the write saving event publish the change to it: as
+// completed when it completes it, as updated otherwise, along with the
+// events it blocks when it completes or reopens.
+func publishChange(event *models.Events, wasComplete bool) {
+	change := constant.WebhookEventUpdated
+	if event.Complete && !wasComplete {
+		change = constant.WebhookEventCompleted
+	}
+	event.Changes = append(event.Changes, change)
+	event.CompletionChanged = event.Complete != wasComplete
+}
+
+// validateEventRequest applies the binding rules of EventRequest to a
+// request that did not come through the handler, such as a patched event.
+func validateEventRequest(req *request.EventRequest) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		fields := request.FieldErrors(err)
+		messages := make([]string, 0, len(fields))
+		for _, field := range fields {
+			messages = append(messages, field.Message)
+		}
+		return domain.NewValidationError(strings.Join(messages, "; "), fields...)
+	}
+	return nil
+}
+
+func newEventResponse(event *models.Events) *response.EventResponse {
+	eventResponse := &response.EventResponse{
 		ID:          event.ID,
+		Type:        event.Type,
+		UID:         event.UID,
+		OwnerID:     event.OwnerID,
+		CalendarID:  event.CalendarID,
 		Title:       event.Title,
 		Description: event.Description,
 		Complete:    &event.Complete,
+		CompletedAt: event.CompletedAt,
 		CreatedAt:   event.CreatedAt,
 		UpdatedAt:   event.UpdatedAt,
 		Location:    event.Location,
+		Tags:        tagNames(event),
 		StartTime:   event.StartTime,
 		EndTime:     event.EndTime,
-	}, nil
-}
+		DueAt:       event.DueAt,
+		Priority:    priorityName(event.Priority),
+		Version:     event.Version,
 
-func (u *eventUsecase) DeleteEvent(id uint64) error {
-	if err := u.eventRepository.DeleteEvent(id); err != nil {
-		return errors.Wrap(err, "[EventUsecase.DeleteEvent]: Error deleting event")
+		RRule:         event.RRule,
+		ExDates:       event.ExDates,
+		RecurrenceEnd: event.RecurrenceEnd,
+		Timezone:      event.Timezone,
+
+		Progress:     eventProgress(event),
+		AutoComplete: event.AutoComplete,
+
+		BlockedBy:              blockerIDs(event),
+		Blocks:                 dependencyIDs(event.Blocks),
+		Blocked:                blocked(event),
+		AllowBlockedCompletion: event.AllowBlockedCompletion,
+
+		Status:   event.Status,
+		Position: event.Position,
+
+		Reminders: newReminderResponses(event.Reminders),
 	}
-	return nil
+	if event.DeleteAt.Valid {
+		eventResponse.DeletedAt = &event.DeleteAt.Time
+	}
+	return eventResponse
 }
@@ -1,13 +1,19 @@
 package usecase
 
 import (
+	"cmp"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
 	"github.com/pubestpubest/g12-todo-backend/models"
 	"github.com/pubestpubest/g12-todo-backend/request"
+	"gorm.io/gorm"
 )
 
 // mockEventRepository implements domain.EventRepository for testing
@@ -17,6 +23,72 @@ type mockEventRepository struct {
 	errorMessage  string
 	getByIDResult *models.Events
 	getByIDError  error
+	lastFilter    *domain.EventFilter
+	lastColumns   []string
+	exceptions    []*models.EventExceptions
+	splitNext     *models.Events
+	savedItems    []*models.EventItems
+	removedItems  []*models.EventItems
+	lastItemID    uint64
+	dependencies  []*models.EventDependencies
+	deleted       []*models.Events
+	published     []string // webhook changes saved, in order
+	outbox        []*models.OutboxEvents
+	wake          chan struct{}
+	onLastChange  func()                  // called once LastEventChangeID read the last change
+	calendars     *mockCalendarRepository // shares events, if set
+}
+
+// publish records the changes of the events saved in the outbox and wakes
+// the stream, bumping and publishing the events waiting on those whose
+// completion changed, as the repository would
+func (m *mockEventRepository) publish(events ...*models.Events) {
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		for _, change := range event.Changes {
+			m.addChange(event, change)
+		}
+		m.published = append(m.published, event.Changes...)
+		event.Changes = nil
+		event.PreviousCalendarID = nil
+
+		if !event.CompletionChanged {
+			continue
+		}
+		event.CompletionChanged = false
+		for _, dependent := range append(slices.Clone(m.events), m.deleted...) {
+			if !slices.Contains(blockerIDs(dependent), event.ID) {
+				continue
+			}
+			dependent.Version++
+			if !dependent.DeleteAt.Valid {
+				m.addChange(dependent, constant.WebhookEventUpdated)
+			}
+		}
+	}
+}
+
+func (m *mockEventRepository) addChange(event *models.Events, change string) {
+	now := time.Now()
+	m.outbox = append(m.outbox, &models.OutboxEvents{
+		ID:                 uint64(len(m.outbox) + 1),
+		WorkspaceID:        event.WorkspaceID,
+		Type:               change,
+		EventID:            event.ID,
+		OwnerID:            event.OwnerID,
+		CalendarID:         event.CalendarID,
+		PreviousCalendarID: event.PreviousCalendarID,
+		Version:            event.Version,
+		CreatedAt:          &now,
+	})
+	if m.wake != nil {
+		select {
+		case m.wake <- struct{}{}:
+		default:
+		}
+	}
 }
 
 func newMockEventRepository() *mockEventRepository {
@@ -26,16 +98,23 @@ func newMockEventRepository() *mockEventRepository {
 	}
 }
 
-func (m *mockEventRepository) GetEventList(page, limit int) ([]*models.Events, int64, error) {
+func (m *mockEventRepository) GetEventList(actor domain.Actor, filter *domain.EventFilter) ([]*models.Events, int64, error) {
+	m.lastFilter = filter
 	if m.shouldError {
 		return nil, 0, errors.New(m.errorMessage)
 	}
 
 	total := int64(len(m.events))
+	if filter.Expand {
+		return m.events, total, nil
+	}
+	if filter.Cursor {
+		return m.seekEvents(filter), total, nil
+	}
 
 	// Calculate pagination
-	start := (page - 1) * limit
-	end := start + limit
+	start := (filter.Page - 1) * filter.Limit
+	end := start + filter.Limit
 
 	if start >= len(m.events) {
 		return []*models.Events{}, total, nil
@@ -48,7 +127,89 @@ func (m *mockEventRepository) GetEventList(page, limit int) ([]*models.Events, i
 	return m.events[start:end], total, nil
 }
 
-func (m *mockEventRepository) GetEventByID(id uint64) (*models.Events, error) {
+// seekEvents reads a page of the events by cursor the way the repository
+// does: sorted, past filter.Seek and one past the limit.
+func (m *mockEventRepository) seekEvents(filter *domain.EventFilter) []*models.Events {
+	// order compares an event, by its sort values and ID, with another
+	order := func(event *models.Events, values []any, id uint64) int {
+		for i, field := range filter.Sort {
+			order := compareSortValues(eventSortValue(event, field.Column), values[i])
+			if field.Desc {
+				order = -order
+			}
+			if order != 0 {
+				return order
+			}
+		}
+		return cmp.Compare(event.ID, id)
+	}
+	sortValues := func(event *models.Events) []any {
+		var values []any
+		for _, field := range filter.Sort {
+			values = append(values, eventSortValue(event, field.Column))
+		}
+		return values
+	}
+
+	events := slices.Clone(m.events)
+	slices.SortFunc(events, func(a, b *models.Events) int { return order(a, sortValues(b), b.ID) })
+	if seek := filter.Seek; seek != nil {
+		events = slices.DeleteFunc(events, func(event *models.Events) bool {
+			past := order(event, seek.Values, seek.ID)
+			if seek.Inclusive && past == 0 {
+				return false
+			}
+			return seek.Backward && past >= 0 || !seek.Backward && past <= 0
+		})
+		if seek.Backward {
+			slices.Reverse(events)
+		}
+	}
+	return events[:min(len(events), filter.Limit+1)]
+}
+
+// compareSortValues compares sort values of the same column, NULLs last.
+func compareSortValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case bool:
+		return compareBools(a, b.(bool))
+	case int:
+		return cmp.Compare(a, b.(int))
+	default:
+		return a.(time.Time).Compare(b.(time.Time))
+	}
+}
+
+func (m *mockEventRepository) SearchEvents(actor domain.Actor, filter *domain.EventFilter) ([]*models.EventSearchResult, int64, error) {
+	m.lastFilter = filter
+	if m.shouldError {
+		return nil, 0, errors.New(m.errorMessage)
+	}
+
+	var results []*models.EventSearchResult
+	for _, event := range m.events {
+		if strings.Contains(strings.ToLower(event.Title), strings.ToLower(filter.Query)) {
+			results = append(results, &models.EventSearchResult{
+				Events:  *event,
+				Rank:    1,
+				Snippet: "<mark>" + event.Title + "</mark>",
+			})
+		}
+	}
+	return results, int64(len(results)), nil
+}
+
+func (m *mockEventRepository) GetEventByID(actor domain.Actor, id uint64) (*models.Events, error) {
 	if m.getByIDError != nil {
 		return nil, m.getByIDError
 	}
@@ -58,13 +219,23 @@ func (m *mockEventRepository) GetEventByID(id uint64) (*models.Events, error) {
 	}
 
 	for _, event := range m.events {
-		if event.ID == id {
+		if event.ID == id && event.WorkspaceID == actor.WorkspaceID &&
+			(event.OwnerID == actor.UserID || m.calendars != nil && m.calendars.isShared(actor, event.CalendarID)) {
 			return event, nil
 		}
 	}
 	return nil, nil
 }
 
+func (m *mockEventRepository) GetEventByUID(actor domain.Actor, uid string) (*models.Events, error) {
+	for _, event := range m.events {
+		if event.UID != nil && *event.UID == uid && event.OwnerID == actor.UserID && event.WorkspaceID == actor.WorkspaceID {
+			return event, nil
+		}
+	}
+	return nil, domain.ErrEventNotFound
+}
+
 func (m *mockEventRepository) CreateEvent(event *models.Events) error {
 	if m.shouldError {
 		return errors.New(m.errorMessage)
@@ -76,6 +247,7 @@ func (m *mockEventRepository) CreateEvent(event *models.Events) error {
 	event.CreatedAt = &now
 	event.UpdatedAt = &now
 
+	m.publish(event)
 	m.events = append(m.events, event)
 	return nil
 }
@@ -89,6 +261,8 @@ func (m *mockEventRepository) UpdateEvent(event *models.Events) error {
 		if e.ID == event.ID {
 			now := time.Now()
 			event.UpdatedAt = &now
+			event.Version++
+			m.publish(event)
 			m.events[i] = event
 			return nil
 		}
@@ -96,33 +270,469 @@ func (m *mockEventRepository) UpdateEvent(event *models.Events) error {
 	return errors.New("event not found")
 }
 
-func (m *mockEventRepository) DeleteEvent(id uint64) error {
+func (m *mockEventRepository) PatchEvent(event *models.Events, columns []string) error {
+	m.lastColumns = columns
+	if m.shouldError {
+		return errors.New(m.errorMessage)
+	}
+
+	now := time.Now()
+	event.UpdatedAt = &now
+	event.Version++
+	m.publish(event)
+	return nil
+}
+
+func (m *mockEventRepository) DeleteEvent(actor domain.Actor, id, version uint64) error {
 	if m.shouldError {
 		return errors.New(m.errorMessage)
 	}
 
 	for i, event := range m.events {
 		if event.ID == id {
+			if version != 0 && event.Version != version {
+				return domain.ErrEventVersionMismatch
+			}
 			m.events = append(m.events[:i], m.events[i+1:]...)
+			event.DeleteAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			m.deleted = append(m.deleted, event)
+			m.addChange(event, constant.WebhookEventDeleted)
 			return nil
 		}
 	}
 	return errors.New("event not found")
 }
 
+func (m *mockEventRepository) GetDeletedEventList(actor domain.Actor, filter *domain.EventFilter) ([]*models.Events, int64, error) {
+	if m.shouldError {
+		return nil, 0, errors.New(m.errorMessage)
+	}
+	m.lastFilter = filter
+	return m.deleted, int64(len(m.deleted)), nil
+}
+
+func (m *mockEventRepository) GetDeletedEventByID(actor domain.Actor, id uint64) (*models.Events, error) {
+	for _, event := range m.deleted {
+		if event.ID == id && event.OwnerID == actor.UserID {
+			return event, nil
+		}
+	}
+	return nil, domain.ErrEventNotFound
+}
+
+func (m *mockEventRepository) RestoreEvent(event *models.Events) error {
+	for i, deleted := range m.deleted {
+		if deleted.ID == event.ID {
+			m.deleted = append(m.deleted[:i], m.deleted[i+1:]...)
+			event.DeleteAt = gorm.DeletedAt{}
+			event.Version++
+			m.publish(event)
+			m.events = append(m.events, event)
+			return nil
+		}
+	}
+	return domain.ErrEventVersionMismatch
+}
+
+func (m *mockEventRepository) PurgeEvent(actor domain.Actor, id, version uint64) error {
+	for _, list := range []*[]*models.Events{&m.events, &m.deleted} {
+		for i, event := range *list {
+			if event.ID == id {
+				if version != 0 && event.Version != version {
+					return domain.ErrEventVersionMismatch
+				}
+				*list = append((*list)[:i], (*list)[i+1:]...)
+				if list == &m.events {
+					m.addChange(event, constant.WebhookEventDeleted)
+				}
+				return nil
+			}
+		}
+	}
+	return domain.ErrEventVersionMismatch
+}
+
+func (m *mockEventRepository) PurgeDeletedEvents(deletedBefore time.Time) (int64, error) {
+	var kept []*models.Events
+	for _, event := range m.deleted {
+		if !event.DeleteAt.Time.Before(deletedBefore) {
+			kept = append(kept, event)
+		}
+	}
+	purged := int64(len(m.deleted) - len(kept))
+	m.deleted = kept
+	return purged, nil
+}
+
+func (m *mockEventRepository) GetEventExceptions(actor domain.Actor, eventIDs []uint64) ([]*models.EventExceptions, error) {
+	return m.exceptions, nil
+}
+
+func (m *mockEventRepository) SaveEventException(event *models.Events, exception *models.EventExceptions) error {
+	if m.shouldError {
+		return errors.New(m.errorMessage)
+	}
+
+	event.Version++
+	m.publish(event)
+	exception.EventID = event.ID
+	m.exceptions = append(m.exceptions, exception)
+	return nil
+}
+
+func (m *mockEventRepository) SplitEventSeries(event *models.Events, next *models.Events) error {
+	if m.shouldError {
+		return errors.New(m.errorMessage)
+	}
+
+	event.Version++
+	m.splitNext = next
+	if next != nil {
+		next.ID = uint64(len(m.events) + 1)
+		next.Version = 1
+		m.events = append(m.events, next)
+	}
+	m.publish(event, next)
+	return nil
+}
+
+func (m *mockEventRepository) SaveEventItems(event *models.Events, items, removed []*models.EventItems) error {
+	if m.shouldError {
+		return errors.New(m.errorMessage)
+	}
+
+	event.Version++
+	m.publish(event)
+	for _, item := range items {
+		if item.ID == 0 {
+			m.lastItemID++
+			item.ID = m.lastItemID
+		}
+		item.EventID = event.ID
+	}
+	m.savedItems = items
+	m.removedItems = removed
+	return nil
+}
+
+func (m *mockEventRepository) GetEventsByIDs(actor domain.Actor, ids []uint64) ([]*models.Events, error) {
+	if m.shouldError {
+		return nil, errors.New(m.errorMessage)
+	}
+
+	var events []*models.Events
+	for _, id := range ids {
+		if event, err := m.GetEventByID(actor, id); err == nil && event != nil {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockEventRepository) GetChangedEvents(actor domain.Actor, ids []uint64) ([]*models.Events, error) {
+	events, err := m.GetEventsByIDs(actor, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range m.deleted {
+		if slices.Contains(ids, event.ID) && event.WorkspaceID == actor.WorkspaceID &&
+			(event.OwnerID == actor.UserID || m.calendars != nil && m.calendars.isShared(actor, event.CalendarID)) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockEventRepository) GetEventsAfter(actor domain.Actor, after uint64, limit int) ([]*models.Events, error) {
+	if m.shouldError {
+		return nil, errors.New(m.errorMessage)
+	}
+
+	var events []*models.Events
+	for _, event := range m.events {
+		if visible, _ := m.GetEventByID(actor, event.ID); visible != nil && event.ID > after {
+			events = append(events, event)
+		}
+	}
+	slices.SortFunc(events, func(a, b *models.Events) int { return cmp.Compare(a.ID, b.ID) })
+	return events[:min(limit, len(events))], nil
+}
+
+func (m *mockEventRepository) GetEventDependencies(actor domain.Actor, ids []uint64) ([]*models.EventDependencies, error) {
+	if m.shouldError {
+		return nil, errors.New(m.errorMessage)
+	}
+
+	var dependencies []*models.EventDependencies
+	for _, dependency := range m.dependencies {
+		if slices.Contains(ids, dependency.EventID) || slices.Contains(ids, dependency.BlockerID) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies, nil
+}
+
+func (m *mockEventRepository) NextEventPosition(event *models.Events) (int, error) {
+	if m.shouldError {
+		return 0, errors.New(m.errorMessage)
+	}
+
+	position := 0
+	for _, other := range m.events {
+		if other.ID != event.ID && sameColumn(other, event) {
+			position = max(position, other.Position+1)
+		}
+	}
+	return position, nil
+}
+
+func (m *mockEventRepository) MoveEvent(event *models.Events) error {
+	if m.shouldError {
+		return errors.New(m.errorMessage)
+	}
+
+	event.Version++
+	m.publish(event)
+
+	// The event shares its pointer, so its old slot is gone; renumbering
+	// every column around the new one closes it all the same
+	others := slices.DeleteFunc(slices.Clone(m.events), func(other *models.Events) bool { return other.ID == event.ID })
+	slices.SortStableFunc(others, func(a, b *models.Events) int { return a.Position - b.Position })
+	for i, other := range others {
+		position := 0
+		for _, before := range others[:i] {
+			if sameColumn(before, other) {
+				position++
+			}
+		}
+		if sameColumn(other, event) && position >= event.Position {
+			position++
+		}
+		if other.Position != position {
+			other.Position = position
+			other.Version++
+			m.addChange(other, constant.WebhookEventUpdated)
+		}
+	}
+	return nil
+}
+
+func (m *mockEventRepository) ProcessDueReminders(now time.Time, limit int, process func(reminder *models.Reminders) error) error {
+	if m.shouldError {
+		return errors.New(m.errorMessage)
+	}
+
+	for _, event := range m.events {
+		for _, reminder := range event.Reminders {
+			if limit == 0 {
+				return nil
+			}
+			if reminder.RemindAt == nil || reminder.RemindAt.After(now) {
+				continue
+			}
+			limit--
+			reminder.Event = event
+			reminder.User = &models.Users{ID: reminder.UserID, Email: "owner@example.com", Name: "Owner"}
+			if err := process(reminder); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockEventRepository) GetEventChanges(actor domain.Actor, after uint64, limit int) ([]*models.OutboxEvents, error) {
+	if m.shouldError {
+		return nil, errors.New(m.errorMessage)
+	}
+
+	var changes []*models.OutboxEvents
+	for _, change := range m.outbox {
+		if change.ID <= after || change.WorkspaceID != actor.WorkspaceID {
+			continue
+		}
+		if m.seesChange(actor, change) {
+			changes = append(changes, change)
+		}
+		if len(changes) == limit {
+			break
+		}
+	}
+	return changes, nil
+}
+
+func (m *mockEventRepository) LastEventChangeAt(actor domain.Actor) (*time.Time, error) {
+	if m.shouldError {
+		return nil, errors.New(m.errorMessage)
+	}
+
+	var changedAt *time.Time
+	for _, change := range m.outbox {
+		if change.WorkspaceID == actor.WorkspaceID && m.seesChange(actor, change) {
+			changedAt = latestTime(changedAt, change.CreatedAt)
+		}
+	}
+	return changedAt, nil
+}
+
+// seesChange reports whether change is one actor hears of, as the
+// repository scopes them
+func (m *mockEventRepository) seesChange(actor domain.Actor, change *models.OutboxEvents) bool {
+	if change.UserID != nil {
+		return *change.UserID == actor.UserID
+	}
+	return change.OwnerID == actor.UserID || (m.calendars != nil &&
+		(m.calendars.isShared(actor, change.CalendarID) || m.calendars.isShared(actor, change.PreviousCalendarID)))
+}
+
+func (m *mockEventRepository) LastEventChangeID() (uint64, error) {
+	last := uint64(len(m.outbox))
+	if m.onLastChange != nil {
+		m.onLastChange()
+	}
+	return last, nil
+}
+
+func (m *mockEventRepository) SubscribeEventChanges() (<-chan struct{}, func()) {
+	m.wake = make(chan struct{}, 1)
+	return m.wake, func() {}
+}
+
+// sameColumn reports whether a and b are in the same workflow column
+func sameColumn(a, b *models.Events) bool {
+	return a.Status == b.Status && equalUint64Ptr(a.CalendarID, b.CalendarID) &&
+		(a.CalendarID != nil || a.OwnerID == b.OwnerID)
+}
+
+// mockCalendarRepository implements the lookups of domain.CalendarRepository
+// the event usecase makes
+type mockCalendarRepository struct {
+	domain.CalendarRepository
+	calendars []*models.Calendars
+	shares    []*models.CalendarShares
+	events    *mockEventRepository // holds the events of the calendars, if set
+}
+
+func (m *mockCalendarRepository) isShared(actor domain.Actor, calendarID *uint64) bool {
+	for _, share := range m.shares {
+		if calendarID != nil && share.CalendarID == *calendarID && share.UserID == actor.UserID &&
+			share.WorkspaceID == actor.WorkspaceID && share.Status == constant.CalendarShareAccepted {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockCalendarRepository) GetCalendarByID(actor domain.Actor, id uint64) (*models.Calendars, error) {
+	for _, calendar := range m.calendars {
+		if calendar.ID == id && calendar.WorkspaceID == actor.WorkspaceID &&
+			(calendar.OwnerID == actor.UserID || m.isShared(actor, &calendar.ID)) {
+			return calendar, nil
+		}
+	}
+	return nil, domain.ErrCalendarNotFound
+}
+
+// DeleteCalendar trashes or moves the owner's events of the calendar and
+// publishes them, as the repository would
+func (m *mockCalendarRepository) DeleteCalendar(actor domain.Actor, id uint64, moveTo *uint64) error {
+	i := slices.IndexFunc(m.calendars, func(calendar *models.Calendars) bool {
+		return calendar.ID == id && calendar.OwnerID == actor.UserID && calendar.WorkspaceID == actor.WorkspaceID
+	})
+	if i < 0 {
+		return domain.ErrCalendarNotFound
+	}
+	m.calendars = slices.Delete(m.calendars, i, i+1)
+	m.shares = slices.DeleteFunc(m.shares, func(share *models.CalendarShares) bool { return share.CalendarID == id })
+	if m.events == nil {
+		return nil
+	}
+
+	inCalendar := func(event *models.Events) bool {
+		return event.OwnerID == actor.UserID && event.CalendarID != nil && *event.CalendarID == id
+	}
+	for _, event := range slices.Clone(m.events.events) {
+		if !inCalendar(event) {
+			continue
+		}
+		if moveTo == nil {
+			if err := m.events.DeleteEvent(actor, event.ID, 0); err != nil {
+				return err
+			}
+			continue
+		}
+		event.CalendarID = moveTo
+		event.Version++
+		m.events.addChange(event, constant.WebhookEventUpdated)
+	}
+	for _, event := range m.events.deleted {
+		if inCalendar(event) && moveTo != nil {
+			event.CalendarID = moveTo
+			event.Version++
+		}
+	}
+	return nil
+}
+
+// DeleteCalendarShare removes the share and, if it was accepted, publishes
+// the deletion of the calendar's events for its user alone, as the
+// repository would
+func (m *mockCalendarRepository) DeleteCalendarShare(actor domain.Actor, id uint64) error {
+	i := slices.IndexFunc(m.shares, func(share *models.CalendarShares) bool {
+		return share.ID == id && share.WorkspaceID == actor.WorkspaceID
+	})
+	if i < 0 {
+		return domain.ErrCalendarShareNotFound
+	}
+	share := m.shares[i]
+	m.shares = slices.Delete(m.shares, i, i+1)
+	if m.events == nil || share.Status != constant.CalendarShareAccepted {
+		return nil
+	}
+
+	for _, event := range m.events.events {
+		if event.CalendarID != nil && *event.CalendarID == share.CalendarID {
+			m.events.addChange(event, constant.WebhookEventDeleted)
+			m.events.outbox[len(m.events.outbox)-1].UserID = &share.UserID
+		}
+	}
+	return nil
+}
+
+func (m *mockCalendarRepository) GetUserCalendarShares(actor domain.Actor, status string) ([]*models.CalendarShares, error) {
+	var shares []*models.CalendarShares
+	for _, share := range m.shares {
+		if share.UserID == actor.UserID && share.WorkspaceID == actor.WorkspaceID && share.Status == status {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+// testActor owns the events created by the helpers below
+var testActor = domain.Actor{UserID: 1}
+
 // Helper function to create test events
 func createTestEvent(id uint64, title, description, location string, complete bool, startTime, endTime time.Time) *models.Events {
 	now := time.Now()
+	status := constant.WorkflowTodo
+	if complete {
+		status = constant.WorkflowDone
+	}
 	return &models.Events{
 		ID:          id,
+		OwnerID:     testActor.UserID,
+		Type:        constant.EventTypeEvent,
 		Title:       title,
 		Description: &description,
 		Complete:    complete,
 		CreatedAt:   &now,
 		UpdatedAt:   &now,
 		Location:    location,
-		StartTime:   startTime,
-		EndTime:     endTime,
+		StartTime:   &startTime,
+		EndTime:     &endTime,
+		Version:     1,
+		Status:      status,
 	}
 }
 
@@ -132,8 +742,8 @@ func createTestEventRequest(title, description, location string, complete bool,
 		Title:       title,
 		Description: description,
 		Location:    location,
-		StartTime:   startTime,
-		EndTime:     endTime,
+		StartTime:   &startTime,
+		EndTime:     &endTime,
 		Complete:    &complete,
 	}
 }
@@ -146,7 +756,7 @@ func getTestTimes() (time.Time, time.Time) {
 
 func TestNewEventUsecase(t *testing.T) {
 	mockRepo := newMockEventRepository()
-	usecase := NewEventUsecase(mockRepo)
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
 
 	if usecase == nil {
 		t.Error("Expected usecase to be created, got nil")
@@ -207,8 +817,10 @@ func TestEventUsecase_GetEventList(t *testing.T) {
 			mockRepo.shouldError = tt.shouldError
 			mockRepo.errorMessage = tt.errorMessage
 
-			usecase := NewEventUsecase(mockRepo)
-			result, err := usecase.GetEventList(tt.page, tt.limit)
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.GetEventList(testActor, &request.EventListRequest{
+				PaginationRequest: request.PaginationRequest{Page: tt.page, Limit: tt.limit},
+			})
 
 			if tt.expectedError {
 				if err == nil {
@@ -242,28 +854,209 @@ func TestEventUsecase_GetEventList(t *testing.T) {
 	}
 }
 
-func TestEventUsecase_GetEventByID(t *testing.T) {
+func TestEventUsecase_GetEventList_Filter(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	complete := true
+
 	tests := []struct {
-		name          string
-		eventID       uint64
-		mockEvent     *models.Events
-		mockError     error
-		expectedError bool
-		expectedNil   bool
+		name           string
+		request        *request.EventListRequest
+		expectedError  bool
+		expectedErrMsg string
+		expectedSort   []domain.SortField
 	}{
 		{
-			name:    "successful get event by id",
-			eventID: 1,
-			mockEvent: createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
-				time.Now(), time.Now().Add(time.Hour)),
-			expectedError: false,
-			expectedNil:   false,
+			name:         "default sort by start time",
+			request:      &request.EventListRequest{},
+			expectedSort: []domain.SortField{{Column: "start_time"}},
 		},
 		{
-			name:          "repository error",
-			eventID:       1,
-			mockError:     errors.New("database error"),
-			expectedError: true,
+			name: "multiple sort keys with direction",
+			request: &request.EventListRequest{
+				Sort: "startTime,-createdAt",
+			},
+			expectedSort: []domain.SortField{{Column: "start_time"}, {Column: "created_at", Desc: true}},
+		},
+		{
+			name: "filters are passed through",
+			request: &request.EventListRequest{
+				Complete: &complete,
+				Location: "  Room 1 ",
+				From:     &startTime,
+				To:       &endTime,
+			},
+			expectedSort: []domain.SortField{{Column: "start_time"}},
+		},
+		{
+			name: "unsupported sort key",
+			request: &request.EventListRequest{
+				Sort: "-password",
+			},
+			expectedError:  true,
+			expectedErrMsg: `unsupported sort key "password"`,
+		},
+		{
+			name: "from after to",
+			request: &request.EventListRequest{
+				From: &endTime,
+				To:   &startTime,
+			},
+			expectedError:  true,
+			expectedErrMsg: "from must be before to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockEventRepository()
+			tt.request.Page = 1
+			tt.request.Limit = 10
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			_, err := usecase.GetEventList(testActor, tt.request)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			filter := mockRepo.lastFilter
+			if !reflect.DeepEqual(filter.Sort, tt.expectedSort) {
+				t.Errorf("Expected sort %v, got %v", tt.expectedSort, filter.Sort)
+			}
+
+			if filter.Complete != tt.request.Complete || filter.From != tt.request.From || filter.To != tt.request.To {
+				t.Errorf("Expected filters to be passed through, got %+v", filter)
+			}
+
+			if filter.Location != strings.TrimSpace(tt.request.Location) {
+				t.Errorf("Expected location %q, got %q", strings.TrimSpace(tt.request.Location), filter.Location)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_SearchEvents(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockEvents := []*models.Events{
+		createTestEvent(1, "Dentist", "Checkup", "Clinic", false, startTime, endTime),
+		createTestEvent(2, "Standup", "Daily", "Office", false, startTime, endTime),
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		shouldError    bool
+		expectedError  bool
+		expectedErrMsg string
+		expectedData   int
+	}{
+		{
+			name:         "successful search",
+			query:        " dentist ",
+			expectedData: 1,
+		},
+		{
+			name:         "no matches",
+			query:        "holiday",
+			expectedData: 0,
+		},
+		{
+			name:           "blank query",
+			query:          "   ",
+			expectedError:  true,
+			expectedErrMsg: "q must not be blank",
+		},
+		{
+			name:          "repository error",
+			query:         "dentist",
+			shouldError:   true,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockEventRepository()
+			mockRepo.events = mockEvents
+			mockRepo.shouldError = tt.shouldError
+			mockRepo.errorMessage = "database error"
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.SearchEvents(testActor, &request.EventSearchRequest{
+				EventListRequest: request.EventListRequest{
+					PaginationRequest: request.PaginationRequest{Page: 1, Limit: 10},
+				},
+				Query: tt.query,
+			})
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if tt.expectedErrMsg != "" && !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if mockRepo.lastFilter.Query != strings.TrimSpace(tt.query) {
+				t.Errorf("Expected query %q, got %q", strings.TrimSpace(tt.query), mockRepo.lastFilter.Query)
+			}
+
+			if len(result.Data) != tt.expectedData {
+				t.Errorf("Expected data length %d, got %d", tt.expectedData, len(result.Data))
+				return
+			}
+
+			for _, event := range result.Data {
+				if event.Score == nil || event.Snippet == nil {
+					t.Errorf("Expected score and snippet to be set, got %+v", event)
+				}
+			}
+		})
+	}
+}
+
+func TestEventUsecase_GetEventByID(t *testing.T) {
+	tests := []struct {
+		name          string
+		eventID       uint64
+		mockEvent     *models.Events
+		mockError     error
+		expectedError bool
+		expectedNil   bool
+	}{
+		{
+			name:    "successful get event by id",
+			eventID: 1,
+			mockEvent: createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
+				time.Now(), time.Now().Add(time.Hour)),
+			expectedError: false,
+			expectedNil:   false,
+		},
+		{
+			name:          "repository error",
+			eventID:       1,
+			mockError:     errors.New("database error"),
+			expectedError: true,
 		},
 		{
 			name:          "event not found",
@@ -280,8 +1073,8 @@ func TestEventUsecase_GetEventByID(t *testing.T) {
 			mockRepo.getByIDResult = tt.mockEvent
 			mockRepo.getByIDError = tt.mockError
 
-			usecase := NewEventUsecase(mockRepo)
-			result, err := usecase.GetEventByID(tt.eventID)
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.GetEventByID(testActor, tt.eventID)
 
 			if tt.expectedError {
 				if err == nil {
@@ -362,8 +1155,8 @@ func TestEventUsecase_CreateEvent(t *testing.T) {
 			mockRepo.shouldError = tt.shouldError
 			mockRepo.errorMessage = tt.errorMessage
 
-			usecase := NewEventUsecase(mockRepo)
-			result, err := usecase.CreateEvent(tt.request)
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.CreateEvent(testActor, tt.request)
 
 			if tt.expectedError {
 				if err == nil {
@@ -400,10 +1193,13 @@ func TestEventUsecase_CreateEvent(t *testing.T) {
 func TestEventUsecase_UpdateEvent(t *testing.T) {
 	existingEvent := createTestEvent(1, "Original Event", "Original Description", "Original Location", false,
 		time.Now(), time.Now().Add(time.Hour))
+	versionedEvent := createTestEvent(1, "Original Event", "Original Description", "Original Location", false,
+		time.Now(), time.Now().Add(time.Hour))
 
 	tests := []struct {
 		name           string
 		eventID        uint64
+		version        uint64
 		request        *request.EventRequest
 		mockEvent      *models.Events
 		mockGetError   error
@@ -422,6 +1218,27 @@ func TestEventUsecase_UpdateEvent(t *testing.T) {
 			expectedError: false,
 			setupEvents:   []*models.Events{existingEvent},
 		},
+		{
+			name:    "matching version",
+			eventID: 1,
+			version: 1,
+			request: createTestEventRequest("Updated Event", "Updated Description", "Updated Location", true,
+				time.Now(), time.Now().Add(time.Hour)),
+			mockEvent:     versionedEvent,
+			expectedError: false,
+			setupEvents:   []*models.Events{versionedEvent},
+		},
+		{
+			name:    "stale version",
+			eventID: 1,
+			version: 2,
+			request: createTestEventRequest("Updated Event", "Updated Description", "Updated Location", true,
+				time.Now(), time.Now().Add(time.Hour)),
+			mockEvent: createTestEvent(1, "Original Event", "Original Description", "Original Location", false,
+				time.Now(), time.Now().Add(time.Hour)),
+			expectedError:  true,
+			expectedErrMsg: domain.ErrEventVersionMismatch.Error(),
+		},
 		{
 			name:    "start time after end time",
 			eventID: 1,
@@ -474,8 +1291,8 @@ func TestEventUsecase_UpdateEvent(t *testing.T) {
 				mockRepo.events = tt.setupEvents
 			}
 
-			usecase := NewEventUsecase(mockRepo)
-			result, err := usecase.UpdateEvent(tt.eventID, tt.request)
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.UpdateEvent(testActor, tt.eventID, tt.version, tt.request)
 
 			if tt.expectedError {
 				if err == nil {
@@ -505,6 +1322,157 @@ func TestEventUsecase_UpdateEvent(t *testing.T) {
 	}
 }
 
+func TestEventUsecase_PatchEvent(t *testing.T) {
+	startTime, endTime := getTestTimes()
+
+	tests := []struct {
+		name            string
+		version         uint64
+		contentType     string
+		patch           string
+		notFound        bool
+		shouldError     bool
+		expectedError   bool
+		expectedErrMsg  string
+		expectedColumns []string
+		expectedTitle   string
+	}{
+		{
+			name:            "merge patch toggles complete",
+			contentType:     constant.MergePatchContentType,
+			patch:           `{"complete": true}`,
+			expectedColumns: []string{"status", "complete", "completed_at"},
+			expectedTitle:   "Original Event",
+		},
+		{
+			name:            "json patch replaces title",
+			contentType:     constant.JSONPatchContentType,
+			patch:           `[{"op": "replace", "path": "/title", "value": "Patched Event"}]`,
+			expectedColumns: []string{"title"},
+			expectedTitle:   "Patched Event",
+		},
+		{
+			name:            "unchanged values are not written",
+			contentType:     constant.MergePatchContentType,
+			patch:           `{"title": "Original Event"}`,
+			expectedColumns: nil,
+			expectedTitle:   "Original Event",
+		},
+		{
+			name:            "matching version",
+			version:         1,
+			contentType:     constant.MergePatchContentType,
+			patch:           `{"complete": true}`,
+			expectedColumns: []string{"status", "complete", "completed_at"},
+			expectedTitle:   "Original Event",
+		},
+		{
+			name:           "stale version",
+			version:        3,
+			contentType:    constant.MergePatchContentType,
+			patch:          `{"complete": true}`,
+			expectedError:  true,
+			expectedErrMsg: domain.ErrEventVersionMismatch.Error(),
+		},
+		{
+			name:           "merged times must stay ordered",
+			contentType:    constant.MergePatchContentType,
+			patch:          `{"startTime": "` + endTime.Add(time.Hour).Format(time.RFC3339) + `"}`,
+			expectedError:  true,
+			expectedErrMsg: "startTime must be before endTime",
+		},
+		{
+			name:           "removing a required field",
+			contentType:    constant.MergePatchContentType,
+			patch:          `{"title": null}`,
+			expectedError:  true,
+			expectedErrMsg: "title is required",
+		},
+		{
+			name:           "title too long",
+			contentType:    constant.MergePatchContentType,
+			patch:          `{"title": "` + strings.Repeat("a", 201) + `"}`,
+			expectedError:  true,
+			expectedErrMsg: "title must be at most 200 characters",
+		},
+		{
+			name:           "unknown field",
+			contentType:    constant.MergePatchContentType,
+			patch:          `{"owner": "someone"}`,
+			expectedError:  true,
+			expectedErrMsg: "unknown field",
+		},
+		{
+			name:           "failed json patch test operation",
+			contentType:    constant.JSONPatchContentType,
+			patch:          `[{"op": "test", "path": "/title", "value": "Other"}]`,
+			expectedError:  true,
+			expectedErrMsg: "invalid patch",
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "application/json",
+			patch:          `{"complete": true}`,
+			expectedError:  true,
+			expectedErrMsg: "unsupported patch content type",
+		},
+		{
+			name:           "event not found",
+			contentType:    constant.MergePatchContentType,
+			patch:          `{"complete": true}`,
+			notFound:       true,
+			expectedError:  true,
+			expectedErrMsg: "event not found",
+		},
+		{
+			name:          "repository error",
+			contentType:   constant.MergePatchContentType,
+			patch:         `{"complete": true}`,
+			shouldError:   true,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockEventRepository()
+			if !tt.notFound {
+				mockRepo.getByIDResult = createTestEvent(1, "Original Event", "Original Description", "Original Location", false,
+					startTime, endTime)
+			}
+			mockRepo.shouldError = tt.shouldError
+			mockRepo.errorMessage = "database error"
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.PatchEvent(testActor, 1, tt.version, tt.contentType, []byte(tt.patch))
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if tt.expectedErrMsg != "" && !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(mockRepo.lastColumns, tt.expectedColumns) {
+				t.Errorf("Expected columns %v, got %v", tt.expectedColumns, mockRepo.lastColumns)
+			}
+
+			if result.Title != tt.expectedTitle {
+				t.Errorf("Expected title %s, got %s", tt.expectedTitle, result.Title)
+			}
+		})
+	}
+}
+
 func TestEventUsecase_DeleteEvent(t *testing.T) {
 	existingEvent := createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
 		time.Now(), time.Now().Add(time.Hour))
@@ -512,6 +1480,7 @@ func TestEventUsecase_DeleteEvent(t *testing.T) {
 	tests := []struct {
 		name          string
 		eventID       uint64
+		version       uint64
 		shouldError   bool
 		errorMessage  string
 		expectedError bool
@@ -523,12 +1492,35 @@ func TestEventUsecase_DeleteEvent(t *testing.T) {
 			expectedError: false,
 			setupEvents:   []*models.Events{existingEvent},
 		},
+		{
+			name:          "matching version",
+			eventID:       1,
+			version:       1,
+			expectedError: false,
+			setupEvents: []*models.Events{createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
+				time.Now(), time.Now().Add(time.Hour))},
+		},
+		{
+			name:          "stale version",
+			eventID:       1,
+			version:       2,
+			expectedError: true,
+			setupEvents: []*models.Events{createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
+				time.Now(), time.Now().Add(time.Hour))},
+		},
+		{
+			name:          "event not found",
+			eventID:       999,
+			expectedError: true,
+		},
 		{
 			name:          "repository error",
 			eventID:       1,
 			shouldError:   true,
 			errorMessage:  "delete failed",
 			expectedError: true,
+			setupEvents: []*models.Events{createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
+				time.Now(), time.Now().Add(time.Hour))},
 		},
 	}
 
@@ -541,8 +1533,8 @@ func TestEventUsecase_DeleteEvent(t *testing.T) {
 				mockRepo.events = tt.setupEvents
 			}
 
-			usecase := NewEventUsecase(mockRepo)
-			err := usecase.DeleteEvent(tt.eventID)
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			err := usecase.DeleteEvent(testActor, tt.eventID, tt.version)
 
 			if tt.expectedError {
 				if err == nil {
@@ -557,3 +1549,442 @@ func TestEventUsecase_DeleteEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestEventUsecase_ErrorCodes(t *testing.T) {
+	startTime, endTime := getTestTimes()
+
+	tests := []struct {
+		name          string
+		call          func(usecase domain.EventUsecase, mockRepo *mockEventRepository) error
+		expectedCode  string
+		expectedField string
+	}{
+		{
+			name: "missing event is not found",
+			call: func(usecase domain.EventUsecase, mockRepo *mockEventRepository) error {
+				_, err := usecase.GetEventByID(testActor, 999)
+				return err
+			},
+			expectedCode: constant.CodeNotFound,
+		},
+		{
+			name: "invalid time range is a validation error",
+			call: func(usecase domain.EventUsecase, mockRepo *mockEventRepository) error {
+				_, err := usecase.CreateEvent(testActor, createTestEventRequest("Test Event", "Test Description", "Test Location", false,
+					endTime, startTime))
+				return err
+			},
+			expectedCode:  constant.CodeValidationFailed,
+			expectedField: "endTime",
+		},
+		{
+			name: "unsupported sort key is a validation error",
+			call: func(usecase domain.EventUsecase, mockRepo *mockEventRepository) error {
+				_, err := usecase.GetEventList(testActor, &request.EventListRequest{
+					PaginationRequest: request.PaginationRequest{Page: 1, Limit: 10},
+					Sort:              "unknown",
+				})
+				return err
+			},
+			expectedCode:  constant.CodeValidationFailed,
+			expectedField: "sort",
+		},
+		{
+			name: "patched event breaking a binding rule",
+			call: func(usecase domain.EventUsecase, mockRepo *mockEventRepository) error {
+				mockRepo.getByIDResult = createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
+					startTime, endTime)
+				_, err := usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType, []byte(`{"title": ""}`))
+				return err
+			},
+			expectedCode:  constant.CodeValidationFailed,
+			expectedField: "title",
+		},
+		{
+			name: "stale version is a failed precondition",
+			call: func(usecase domain.EventUsecase, mockRepo *mockEventRepository) error {
+				mockRepo.events = []*models.Events{createTestEvent(1, "Test Event", "Test Description", "Test Location", false,
+					startTime, endTime)}
+				return usecase.DeleteEvent(testActor, 1, 5)
+			},
+			expectedCode: constant.CodePreconditionFailed,
+		},
+		{
+			name: "repository failure stays untyped",
+			call: func(usecase domain.EventUsecase, mockRepo *mockEventRepository) error {
+				mockRepo.getByIDError = errors.New("connection refused")
+				_, err := usecase.GetEventByID(testActor, 1)
+				return err
+			},
+			expectedCode: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockEventRepository()
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+			err := tt.call(usecase, mockRepo)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			var domainErr *domain.Error
+			code := ""
+			if errors.As(err, &domainErr) {
+				code = domainErr.Code
+			}
+			if code != tt.expectedCode {
+				t.Errorf("Expected code %q, got %q (%v)", tt.expectedCode, code, err)
+			}
+
+			if tt.expectedField != "" {
+				if len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != tt.expectedField {
+					t.Errorf("Expected a single error for field %q, got %+v", tt.expectedField, domainErr.Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestEventUsecase_Ownership(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	otherActor := domain.Actor{UserID: 2}
+
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{createTestEvent(1, "Mine", "Description", "Office", false, startTime, endTime)}
+	mockRepo.events[0].UID = stringPtr("shared@example.com")
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	if _, err := usecase.GetEventByID(otherActor, 1); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("Expected another user's event to be not found, got: %v", err)
+	}
+	if err := usecase.DeleteEvent(otherActor, 1, 0); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("Expected another user's event to be not found, got: %v", err)
+	}
+
+	req := createTestEventRequest("Theirs", "Description", "Office", false, startTime, endTime)
+	req.UID = "shared@example.com"
+	created, err := usecase.CreateEvent(otherActor, req)
+	if err != nil {
+		t.Fatalf("Expected uids to be unique per owner, got: %v", err)
+	}
+	if mockRepo.events[1].OwnerID != otherActor.UserID || created.ID != mockRepo.events[1].ID {
+		t.Errorf("Expected the event owned by user %d, got %d", otherActor.UserID, mockRepo.events[1].OwnerID)
+	}
+}
+
+func TestEventUsecase_Calendar(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	work := uint64(1)
+	other := uint64(2)
+
+	tests := []struct {
+		name           string
+		calendarID     *uint64
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name: "no calendar",
+		},
+		{
+			name:       "own calendar",
+			calendarID: &work,
+		},
+		{
+			name:           "another user's calendar",
+			calendarID:     &other,
+			expectedError:  true,
+			expectedErrMsg: "calendar not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockEventRepository()
+			calendars := &mockCalendarRepository{calendars: []*models.Calendars{
+				{ID: work, OwnerID: testActor.UserID, Name: "Work"},
+				{ID: other, OwnerID: 2, Name: "Theirs"},
+			}}
+			usecase := NewEventUsecase(mockRepo, calendars)
+
+			req := createTestEventRequest("Meeting", "Description", "Office", false, startTime, endTime)
+			req.CalendarID = tt.calendarID
+			result, err := usecase.CreateEvent(testActor, req)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !equalUint64Ptr(result.CalendarID, tt.calendarID) {
+				t.Errorf("Expected calendar %v, got %v", tt.calendarID, result.CalendarID)
+			}
+
+			// Patching it into the other user's calendar is refused too
+			_, err = usecase.PatchEvent(testActor, result.ID, 0, constant.MergePatchContentType, []byte(`{"calendarId": 2}`))
+			if err == nil || !strings.Contains(err.Error(), "calendar not found") {
+				t.Errorf("Expected calendar not found, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_Sharing(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	work := uint64(1)
+	viewer := domain.Actor{UserID: 2}
+	editor := domain.Actor{UserID: 3}
+	invited := domain.Actor{UserID: 4}
+
+	newUsecase := func() (domain.EventUsecase, *mockEventRepository) {
+		calendars := &mockCalendarRepository{
+			calendars: []*models.Calendars{{ID: work, OwnerID: testActor.UserID, Name: "Work"}},
+			shares: []*models.CalendarShares{
+				{ID: 1, CalendarID: work, UserID: viewer.UserID, Role: constant.CalendarRoleViewer, Status: constant.CalendarShareAccepted},
+				{ID: 2, CalendarID: work, UserID: editor.UserID, Role: constant.CalendarRoleEditor, Status: constant.CalendarShareAccepted},
+				{ID: 3, CalendarID: work, UserID: invited.UserID, Role: constant.CalendarRoleEditor, Status: constant.CalendarSharePending},
+			},
+		}
+		mockRepo := newMockEventRepository()
+		mockRepo.calendars = calendars
+		event := createTestEvent(1, "Standup", "Description", "Office", false, startTime, endTime)
+		event.CalendarID = &work
+		mockRepo.events = []*models.Events{event}
+		return NewEventUsecase(mockRepo, calendars), mockRepo
+	}
+
+	t.Run("listed with the access level", func(t *testing.T) {
+		usecase, _ := newUsecase()
+		for _, tt := range []struct {
+			actor  domain.Actor
+			access string
+		}{
+			{testActor, constant.CalendarRoleOwner},
+			{viewer, constant.CalendarRoleViewer},
+			{editor, constant.CalendarRoleEditor},
+		} {
+			actor, access := tt.actor, tt.access
+			result, err := usecase.GetEventList(actor, &request.EventListRequest{PaginationRequest: request.PaginationRequest{Page: 1, Limit: 10}})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(result.Data) != 1 || result.Data[0].Access != access {
+				t.Errorf("Expected user %d to see the event as %s, got %+v", actor.UserID, access, result.Data)
+			}
+		}
+	})
+
+	t.Run("viewers cannot write", func(t *testing.T) {
+		usecase, _ := newUsecase()
+		req := createTestEventRequest("Renamed", "Description", "Office", false, startTime, endTime)
+		req.CalendarID = &work
+
+		if _, err := usecase.UpdateEvent(viewer, 1, 0, req); !errors.Is(err, domain.ErrEventReadOnly) {
+			t.Errorf("Expected a viewer's update to be forbidden, got: %v", err)
+		}
+		if err := usecase.DeleteEvent(viewer, 1, 0); !errors.Is(err, domain.ErrEventReadOnly) {
+			t.Errorf("Expected a viewer's delete to be forbidden, got: %v", err)
+		}
+		if _, err := usecase.CreateEvent(viewer, req); !errors.Is(err, domain.ErrCalendarReadOnly) {
+			t.Errorf("Expected a viewer's create to be forbidden, got: %v", err)
+		}
+
+		var domainErr *domain.Error
+		_, err := usecase.UpdateEvent(viewer, 1, 0, req)
+		if !errors.As(err, &domainErr) || domainErr.Code != constant.CodeForbidden {
+			t.Errorf("Expected a forbidden error, got: %v", err)
+		}
+	})
+
+	t.Run("editors write on behalf of the owner", func(t *testing.T) {
+		usecase, mockRepo := newUsecase()
+		req := createTestEventRequest("Renamed", "Description", "Office", false, startTime, endTime)
+		req.CalendarID = &work
+
+		updated, err := usecase.UpdateEvent(editor, 1, 0, req)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if updated.Title != "Renamed" || updated.Access != constant.CalendarRoleEditor {
+			t.Errorf("Expected the event updated by an editor, got %+v", updated)
+		}
+
+		created, err := usecase.CreateEvent(editor, req)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if created.OwnerID != testActor.UserID {
+			t.Errorf("Expected the event owned by the calendar owner, got %d", created.OwnerID)
+		}
+
+		_, err = usecase.PatchEvent(editor, 1, 0, constant.MergePatchContentType, []byte(`{"calendarId": null}`))
+		if err == nil || !strings.Contains(err.Error(), "calendar belongs to another user") {
+			t.Errorf("Expected an editor not to take the event out of the calendar, got: %v", err)
+		}
+
+		if err := usecase.PurgeEvent(editor, 1, 0); !errors.Is(err, domain.ErrEventNotOwner) {
+			t.Errorf("Expected only the owner to purge the event, got: %v", err)
+		}
+		if err := usecase.DeleteEvent(editor, 1, 0); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(mockRepo.deleted) != 1 {
+			t.Errorf("Expected the event in the trash, got %d", len(mockRepo.deleted))
+		}
+	})
+
+	t.Run("pending invitations grant nothing", func(t *testing.T) {
+		usecase, _ := newUsecase()
+		if _, err := usecase.GetEventByID(invited, 1); !errors.Is(err, domain.ErrEventNotFound) {
+			t.Errorf("Expected the event to be not found before accepting, got: %v", err)
+		}
+	})
+}
+
+func TestEventUsecase_Workspaces(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	class := domain.Actor{UserID: testActor.UserID, WorkspaceID: 2}
+
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{createTestEvent(1, "Personal", "Description", "Office", false, startTime, endTime)}
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	req := createTestEventRequest("Lecture", "Description", "Hall", false, startTime, endTime)
+	req.UID = "lecture@example.com"
+	created, err := usecase.CreateEvent(class, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockRepo.events[1].WorkspaceID != class.WorkspaceID {
+		t.Errorf("Expected the event created in workspace %d, got %d", class.WorkspaceID, mockRepo.events[1].WorkspaceID)
+	}
+
+	// The same user sees nothing of one workspace from another
+	if _, err := usecase.GetEventByID(testActor, created.ID); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("Expected the workspace event to be not found in the personal space, got: %v", err)
+	}
+	if _, err := usecase.GetEventByID(class, 1); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("Expected the personal event to be not found in the workspace, got: %v", err)
+	}
+	if err := usecase.DeleteEvent(class, 1, 0); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("Expected the personal event not to be deleted from the workspace, got: %v", err)
+	}
+
+	// UIDs only clash within a workspace
+	if _, err := usecase.CreateEvent(testActor, req); err != nil {
+		t.Errorf("Expected the uid to be free in the personal space, got: %v", err)
+	}
+	if _, err := usecase.CreateEvent(class, req); !errors.Is(err, domain.ErrEventUIDConflict) {
+		t.Errorf("Expected a duplicate uid in the workspace to conflict, got: %v", err)
+	}
+}
+
+func TestEventUsecase_Changes(t *testing.T) {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	created, err := usecase.CreateEvent(testActor, createTestEventRequest("Meeting", "Description", "Office", false, startTime, endTime))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	steps := []struct {
+		name     string
+		write    func() error
+		expected []string
+	}{
+		{
+			name: "patch",
+			write: func() error {
+				_, err := usecase.PatchEvent(testActor, created.ID, 0, constant.MergePatchContentType, []byte(`{"title":"Standup"}`))
+				return err
+			},
+			expected: []string{constant.WebhookEventUpdated},
+		},
+		{
+			name: "patch without changes",
+			write: func() error {
+				_, err := usecase.PatchEvent(testActor, created.ID, 0, constant.MergePatchContentType, []byte(`{"title":"Standup"}`))
+				return err
+			},
+		},
+		{
+			name: "complete",
+			write: func() error {
+				_, err := usecase.PatchEvent(testActor, created.ID, 0, constant.MergePatchContentType, []byte(`{"complete":true}`))
+				return err
+			},
+			expected: []string{constant.WebhookEventCompleted},
+		},
+		{
+			name: "reopen",
+			write: func() error {
+				_, err := usecase.PatchEvent(testActor, created.ID, 0, constant.MergePatchContentType, []byte(`{"complete":false}`))
+				return err
+			},
+			expected: []string{constant.WebhookEventUpdated},
+		},
+		{
+			name: "move to a terminal state",
+			write: func() error {
+				_, err := usecase.MoveEvent(testActor, created.ID, 0, &request.EventMoveRequest{Status: constant.WorkflowDone})
+				return err
+			},
+			expected: []string{constant.WebhookEventCompleted},
+		},
+		{
+			name: "update",
+			write: func() error {
+				_, err := usecase.UpdateEvent(testActor, created.ID, 0, createTestEventRequest("Standup", "Description", "Office", true, startTime, endTime))
+				return err
+			},
+			expected: []string{constant.WebhookEventUpdated},
+		},
+		{
+			name: "restore",
+			write: func() error {
+				if err := usecase.DeleteEvent(testActor, created.ID, 0); err != nil {
+					return err
+				}
+				_, err := usecase.RestoreEvent(testActor, created.ID)
+				return err
+			},
+			expected: []string{constant.WebhookEventCreated},
+		},
+	}
+
+	if !slices.Equal(mockRepo.published, []string{constant.WebhookEventCreated}) {
+		t.Fatalf("Expected the new event published as created, got %v", mockRepo.published)
+	}
+	for _, step := range steps {
+		mockRepo.published = nil
+		if err := step.write(); err != nil {
+			t.Fatalf("%s: expected no error, got: %v", step.name, err)
+		}
+		if !slices.Equal(mockRepo.published, step.expected) {
+			t.Errorf("%s: expected %v published, got %v", step.name, step.expected, mockRepo.published)
+		}
+
+		// At the version the write saved
+		event, err := usecase.GetEventByID(testActor, created.ID)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", step.name, err)
+		}
+		if last := mockRepo.outbox[len(mockRepo.outbox)-1]; last.Version != event.Version {
+			t.Errorf("%s: expected the change published at version %d, got %d", step.name, event.Version, last.Version)
+		}
+	}
+}
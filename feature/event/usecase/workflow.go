@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+)
+
+func (u *eventUsecase) MoveEvent(actor domain.Actor, id, version uint64, req *request.EventMoveRequest) (*response.EventResponse, error) {
+	event, err := u.eventRepository.GetEventByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.MoveEvent]: Error getting event")
+	}
+
+	if event == nil {
+		return nil, errors.Wrap(domain.ErrEventNotFound, "[EventUsecase.MoveEvent]: Error getting event")
+	}
+
+	if version != 0 && event.Version != version {
+		return nil, errors.Wrap(domain.ErrEventVersionMismatch, "[EventUsecase.MoveEvent]: Error moving event")
+	}
+
+	if err := u.checkEditAccess(actor, event); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.MoveEvent]: Error checking access")
+	}
+
+	workflow, err := u.eventWorkflow(actor, event.CalendarID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.MoveEvent]: Error getting workflow")
+	}
+	state, ok := findState(workflow, req.Status)
+	if !ok {
+		return nil, errors.Wrap(unknownStatusError(req.Status), "[EventUsecase.MoveEvent]: Invalid status")
+	}
+
+	wasComplete := event.Complete
+	setState(event, state)
+	if err := checkBlocked(event, wasComplete); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.MoveEvent]: Error completing event")
+	}
+
+	if req.Position != nil {
+		event.Position = *req.Position
+	} else if event.Position, err = u.eventRepository.NextEventPosition(event); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.MoveEvent]: Error getting position")
+	}
+
+	publishChange(event, wasComplete)
+	if err := u.eventRepository.MoveEvent(event); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.MoveEvent]: Error moving event")
+	}
+
+	eventResponse := newEventResponse(event)
+	if err := u.setAccess(actor, eventResponse); err != nil {
+		return nil, errors.Wrap(err, "[EventUsecase.MoveEvent]: Error getting access")
+	}
+	return eventResponse, nil
+}
+
+// eventWorkflow is the workflow of the calendar calendarID, the default one
+// outside calendars and in those without their own.
+func (u *eventUsecase) eventWorkflow(actor domain.Actor, calendarID *uint64) ([]models.WorkflowState, error) {
+	if calendarID == nil {
+		return models.DefaultWorkflow, nil
+	}
+
+	calendar, err := u.calendarRepository.GetCalendarByID(actor, *calendarID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarNotFound) {
+			return models.DefaultWorkflow, nil
+		}
+		return nil, err
+	}
+	if calendar.Workflow == nil {
+		return models.DefaultWorkflow, nil
+	}
+	return calendar.Workflow, nil
+}
+
+// setEventStatus puts event in the state status of the workflow of its
+// calendar or, when status is empty, in the state agreeing with whether it
+// is complete: the one it is in if that still does, the first one that does
+// otherwise. An event that is new, changed state or, with moved, changed
+// calendar goes to the end of its column.
+func (u *eventUsecase) setEventStatus(actor domain.Actor, event *models.Events, status string, moved bool) error {
+	workflow, err := u.eventWorkflow(actor, event.CalendarID)
+	if err != nil {
+		return err
+	}
+
+	previous := event.Status
+	if status != "" {
+		state, ok := findState(workflow, status)
+		if !ok {
+			return unknownStatusError(status)
+		}
+		setState(event, state)
+	} else if state, ok := findState(workflow, event.Status); !ok || state.Terminal != event.Complete {
+		for _, state := range workflow {
+			if state.Terminal == event.Complete {
+				event.Status = state.Name
+				break
+			}
+		}
+	}
+
+	if event.ID == 0 || moved || event.Status != previous {
+		if event.Position, err = u.eventRepository.NextEventPosition(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findState looks the state name up in workflow, matching it the way state
+// names are stored.
+func findState(workflow []models.WorkflowState, name string) (models.WorkflowState, bool) {
+	name = utils.NormalizeStatus(name)
+	for _, state := range workflow {
+		if state.Name == name {
+			return state, true
+		}
+	}
+	return models.WorkflowState{}, false
+}
+
+// setState puts event in state, completing it when state is terminal.
+func setState(event *models.Events, state models.WorkflowState) {
+	event.Status = state.Name
+	setComplete(event, state.Terminal)
+}
+
+func unknownStatusError(status string) error {
+	message := fmt.Sprintf("status %q is not a state of the calendar's workflow", status)
+	return domain.NewValidationError(message, response.FieldError{
+		Field: "status", Rule: "oneof", Message: message,
+	})
+}
@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+// Helper function to create a board of count events in todo, in order
+func createTestBoardRepository(count int) *mockEventRepository {
+	startTime, endTime := getTestTimes()
+	mockRepo := newMockEventRepository()
+	for id := uint64(1); id <= uint64(count); id++ {
+		event := createTestEvent(id, "Card", "Description", "Office", false, startTime, endTime)
+		event.Position = int(id - 1)
+		mockRepo.events = append(mockRepo.events, event)
+	}
+	return mockRepo
+}
+
+// columnOrder lists the ids of the events in status by position
+func columnOrder(events []*models.Events, status string) []uint64 {
+	column := slices.DeleteFunc(slices.Clone(events), func(event *models.Events) bool { return event.Status != status })
+	slices.SortFunc(column, func(a, b *models.Events) int { return a.Position - b.Position })
+	ids := make([]uint64, 0, len(column))
+	for _, event := range column {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestEventUsecase_MoveEvent(t *testing.T) {
+	tests := []struct {
+		name             string
+		id               uint64
+		request          *request.EventMoveRequest
+		expectedStatus   string
+		expectedComplete bool
+		expectedTodo     []uint64
+		expectedShifted  []uint64
+		expectedError    bool
+		expectedErrMsg   string
+	}{
+		{
+			name:            "to the end of another column",
+			id:              2,
+			request:         &request.EventMoveRequest{Status: "In Progress"},
+			expectedStatus:  constant.WorkflowInProgress,
+			expectedTodo:    []uint64{1, 3},
+			expectedShifted: []uint64{3},
+		},
+		{
+			name:            "within its column",
+			id:              3,
+			request:         &request.EventMoveRequest{Status: constant.WorkflowTodo, Position: intPtr(0)},
+			expectedStatus:  constant.WorkflowTodo,
+			expectedTodo:    []uint64{3, 1, 2},
+			expectedShifted: []uint64{1, 2},
+		},
+		{
+			name:            "down within its column",
+			id:              1,
+			request:         &request.EventMoveRequest{Status: constant.WorkflowTodo, Position: intPtr(2)},
+			expectedStatus:  constant.WorkflowTodo,
+			expectedTodo:    []uint64{2, 3, 1},
+			expectedShifted: []uint64{2, 3},
+		},
+		{
+			name:             "to a terminal state",
+			id:               1,
+			request:          &request.EventMoveRequest{Status: constant.WorkflowDone},
+			expectedStatus:   constant.WorkflowDone,
+			expectedComplete: true,
+			expectedTodo:     []uint64{2, 3},
+			expectedShifted:  []uint64{2, 3},
+		},
+		{
+			name:           "unknown state",
+			id:             1,
+			request:        &request.EventMoveRequest{Status: "shipped"},
+			expectedError:  true,
+			expectedErrMsg: `status "shipped" is not a state`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := createTestBoardRepository(3)
+
+			usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+			result, err := usecase.MoveEvent(testActor, tt.id, 1, tt.request)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.Status != tt.expectedStatus || *result.Complete != tt.expectedComplete {
+				t.Errorf("Expected status %q complete %v, got %q complete %v", tt.expectedStatus, tt.expectedComplete, result.Status, *result.Complete)
+			}
+			if (result.CompletedAt != nil) != tt.expectedComplete {
+				t.Errorf("Expected completed at set %v, got %v", tt.expectedComplete, result.CompletedAt)
+			}
+			if order := columnOrder(mockRepo.events, constant.WorkflowTodo); !slices.Equal(order, tt.expectedTodo) {
+				t.Errorf("Expected todo %v, got %v", tt.expectedTodo, order)
+			}
+			if result.Version != 2 {
+				t.Errorf("Expected the version bumped to 2, got %d", result.Version)
+			}
+			// The events it shifts publish their new position
+			var shifted []uint64
+			for _, change := range mockRepo.outbox {
+				if change.EventID != tt.id {
+					shifted = append(shifted, change.EventID)
+				}
+			}
+			if !slices.Equal(shifted, tt.expectedShifted) {
+				t.Errorf("Expected %v shifted, got %v", tt.expectedShifted, shifted)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_MoveEvent_Checks(t *testing.T) {
+	mockRepo := createTestDependencyRepository(2, map[uint64][]uint64{1: {2}})
+	mockRepo.events[0].Status = constant.WorkflowTodo
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+
+	if _, err := usecase.MoveEvent(testActor, 1, 2, &request.EventMoveRequest{Status: constant.WorkflowReview}); !errors.Is(err, domain.ErrEventVersionMismatch) {
+		t.Errorf("Expected version mismatch, got: %v", err)
+	}
+	if _, err := usecase.MoveEvent(testActor, 1, 1, &request.EventMoveRequest{Status: constant.WorkflowDone}); !errors.Is(err, domain.ErrEventBlocked) {
+		t.Errorf("Expected moving a blocked event to done to fail, got: %v", err)
+	}
+	if _, err := usecase.MoveEvent(testActor, 9, 0, &request.EventMoveRequest{Status: constant.WorkflowTodo}); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("Expected event not found, got: %v", err)
+	}
+}
+
+func TestEventUsecase_UpdateEvent_Status(t *testing.T) {
+	calendarID := uint64(7)
+	calendars := &mockCalendarRepository{calendars: []*models.Calendars{{
+		ID: calendarID, OwnerID: testActor.UserID, Name: "Releases",
+		Workflow: []models.WorkflowState{{Name: "backlog"}, {Name: "shipped", Terminal: true}, {Name: "archived", Terminal: true}},
+	}}}
+
+	tests := []struct {
+		name             string
+		calendarID       *uint64
+		status           string
+		complete         bool
+		expectedStatus   string
+		expectedComplete bool
+		expectedError    bool
+	}{
+		{
+			name:           "keeps its state",
+			expectedStatus: constant.WorkflowReview,
+		},
+		{
+			name:             "completing picks the terminal state",
+			complete:         true,
+			expectedStatus:   constant.WorkflowDone,
+			expectedComplete: true,
+		},
+		{
+			name:             "status decides complete",
+			status:           "Done",
+			expectedStatus:   constant.WorkflowDone,
+			expectedComplete: true,
+		},
+		{
+			name:           "calendar workflow",
+			calendarID:     &calendarID,
+			expectedStatus: "backlog",
+		},
+		{
+			name:             "first terminal state of the calendar",
+			calendarID:       &calendarID,
+			complete:         true,
+			expectedStatus:   "shipped",
+			expectedComplete: true,
+		},
+		{
+			name:          "state of another workflow",
+			calendarID:    &calendarID,
+			status:        constant.WorkflowReview,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := createTestBoardRepository(1)
+			mockRepo.events[0].Status = constant.WorkflowReview
+
+			startTime, endTime := getTestTimes()
+			req := createTestEventRequest("Card", "Description", "Office", tt.complete, startTime, endTime)
+			req.CalendarID = tt.calendarID
+			req.Status = tt.status
+
+			usecase := NewEventUsecase(mockRepo, calendars)
+			result, err := usecase.UpdateEvent(testActor, 1, 0, req)
+
+			if tt.expectedError {
+				if err == nil || !strings.Contains(err.Error(), "is not a state") {
+					t.Errorf("Expected an unknown status error, got: %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+			if result.Status != tt.expectedStatus || *result.Complete != tt.expectedComplete {
+				t.Errorf("Expected status %q complete %v, got %q complete %v", tt.expectedStatus, tt.expectedComplete, result.Status, *result.Complete)
+			}
+		})
+	}
+}
+
+func TestEventUsecase_PatchEvent_Status(t *testing.T) {
+	mockRepo := createTestBoardRepository(2)
+	mockRepo.events[1].Status = constant.WorkflowDone
+	mockRepo.events[1].Complete = true
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.PatchEvent(testActor, 1, 0, constant.MergePatchContentType, []byte(`{"status":"done"}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !*result.Complete || result.Position != 2 {
+		t.Errorf("Expected the event complete at the end of done, got complete %v position %d", *result.Complete, result.Position)
+	}
+	if !slices.Equal(mockRepo.lastColumns, []string{"status", "position", "complete", "completed_at"}) {
+		t.Errorf("Expected status, position and completion saved, got %v", mockRepo.lastColumns)
+	}
+}
+
+func TestEventUsecase_CreateEvent_Status(t *testing.T) {
+	mockRepo := createTestBoardRepository(2)
+
+	startTime, endTime := getTestTimes()
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	result, err := usecase.CreateEvent(testActor, createTestEventRequest("Card", "Description", "Office", false, startTime, endTime))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Status != constant.WorkflowTodo || result.Position != 2 {
+		t.Errorf("Expected the event last in todo, got %q at %d", result.Status, result.Position)
+	}
+}
+
+func TestEventUsecase_EventItems_Status(t *testing.T) {
+	event := createTestChecklistEvent(true, false)
+	event.Status = constant.WorkflowInProgress
+	event.AutoComplete = true
+	mockRepo := newMockEventRepository()
+	mockRepo.events = []*models.Events{event}
+
+	usecase := NewEventUsecase(mockRepo, &mockCalendarRepository{})
+	if _, err := usecase.UpdateEventItem(testActor, 1, 2, &request.EventItemRequest{Text: "Item B", Done: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if event.Status != constant.WorkflowDone {
+		t.Errorf("Expected the auto-completed event done, got %q", event.Status)
+	}
+
+	// Reopened events go to the first open state
+	if _, err := usecase.UpdateEventItem(testActor, 1, 1, &request.EventItemRequest{Text: "Item A"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if event.Status != constant.WorkflowTodo {
+		t.Errorf("Expected the reopened event in todo, got %q", event.Status)
+	}
+}
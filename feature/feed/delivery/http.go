@@ -0,0 +1,241 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type feedHandler struct {
+	feedUsecase domain.FeedUsecase
+}
+
+func NewFeedHandler(feedUsecase domain.FeedUsecase) *feedHandler {
+	return &feedHandler{feedUsecase: feedUsecase}
+}
+
+func (h *feedHandler) GetFeedList(c *gin.Context) {
+	feeds, err := h.feedUsecase.GetFeedList(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[FeedHandler.GetFeedList]: Error getting feed list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.FeedResponse]{
+		Status:  constant.Success,
+		Message: "List feeds successfully",
+		Data:    feeds,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *feedHandler) CreateFeed(c *gin.Context) {
+	var req request.FeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[FeedHandler.CreateFeed]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	feed, err := h.feedUsecase.CreateFeed(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[FeedHandler.CreateFeed]: Error creating feed")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	setFeedURL(c, feed)
+	resp := response.Response[*response.FeedResponse]{
+		Status:  constant.Success,
+		Message: "Feed created successfully",
+		Data:    feed,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *feedHandler) RotateFeedToken(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[FeedHandler.RotateFeedToken]: Error parsing feed ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	feed, err := h.feedUsecase.RotateFeedToken(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[FeedHandler.RotateFeedToken]: Error rotating feed token")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	setFeedURL(c, feed)
+	resp := response.Response[*response.FeedResponse]{
+		Status:  constant.Success,
+		Message: "Feed token rotated successfully",
+		Data:    feed,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *feedHandler) DeleteFeed(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[FeedHandler.DeleteFeed]: Error parsing feed ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.feedUsecase.DeleteFeed(middlewares.GetActor(c), id); err != nil {
+		err = errors.Wrap(err, "[FeedHandler.DeleteFeed]: Error deleting feed")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Feed deleted successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFeedCalendar serves /feeds/{token}.ics to calendar apps. The token is
+// the only credential, and conditional requests are answered with 304 so
+// polling stays cheap.
+func (h *feedHandler) GetFeedCalendar(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("token"), ".ics")
+	if !ok || token == "" {
+		err := errors.Wrap(domain.ErrFeedNotFound, "[FeedHandler.GetFeedCalendar]: Error parsing feed token")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeNotFound,
+			Data:    nil,
+		}
+		c.JSON(http.StatusNotFound, resp)
+		return
+	}
+
+	calendar, err := h.feedUsecase.GetFeedCalendar(token)
+	if err != nil {
+		err = errors.Wrap(err, "[FeedHandler.GetFeedCalendar]: Error getting feed calendar")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	etag := utils.ContentETag(calendar.Calendar)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if calendar.LastModified != nil {
+		c.Header("Last-Modified", calendar.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-Modified-Since only counts without If-None-Match (RFC 9110 13.1.3)
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if utils.IfNoneMatch(ifNoneMatch, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if ifModifiedSince := c.GetHeader("If-Modified-Since"); ifModifiedSince != "" && calendar.LastModified != nil {
+		if utils.NotModifiedSince(ifModifiedSince, *calendar.LastModified) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, constant.ICalendarContentType+"; charset=utf-8", calendar.Calendar)
+}
+
+// setFeedURL fills in the subscription URL of a freshly issued token.
+func setFeedURL(c *gin.Context, feed *response.FeedResponse) {
+	if feed.Token == nil {
+		return
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	url := scheme + "://" + c.Request.Host + "/v1/feeds/" + *feed.Token + ".ics"
+	feed.URL = &url
+}
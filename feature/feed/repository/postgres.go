@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+)
+
+type feedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) domain.FeedRepository {
+	return &feedRepository{db: db}
+}
+
+func (r *feedRepository) GetFeedList(actor domain.Actor) ([]*models.Feeds, error) {
+	var feeds []*models.Feeds
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Where("owner_id = ?", actor.UserID).Order("id").Find(&feeds).Error; err != nil {
+		return nil, errors.Wrap(err, "[FeedRepository.GetFeedList]: Error getting feeds")
+	}
+	return feeds, nil
+}
+
+func (r *feedRepository) GetFeedByID(actor domain.Actor, id uint64) (*models.Feeds, error) {
+	var feed models.Feeds
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Where("id = ? AND owner_id = ?", id, actor.UserID).First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrFeedNotFound, "[FeedRepository.GetFeedByID]: Error getting feed")
+		}
+		return nil, errors.Wrap(err, "[FeedRepository.GetFeedByID]: Error getting feed")
+	}
+	return &feed, nil
+}
+
+func (r *feedRepository) GetFeedByTokenHash(tokenHash string) (*models.Feeds, error) {
+	// The token alone picks the feed, whatever workspace it is in
+	var feed models.Feeds
+	if err := database.AllWorkspaces(r.db).Where("token_hash = ?", tokenHash).First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrFeedNotFound, "[FeedRepository.GetFeedByTokenHash]: Error getting feed")
+		}
+		return nil, errors.Wrap(err, "[FeedRepository.GetFeedByTokenHash]: Error getting feed")
+	}
+	return &feed, nil
+}
+
+func (r *feedRepository) CreateFeed(feed *models.Feeds) error {
+	now := time.Now()
+	feed.CreatedAt = &now
+	feed.UpdatedAt = &now
+
+	if err := database.WithWorkspace(r.db, feed.WorkspaceID).Create(feed).Error; err != nil {
+		return errors.Wrap(err, "[FeedRepository.CreateFeed]: Error creating feed")
+	}
+	return nil
+}
+
+func (r *feedRepository) UpdateFeedToken(feed *models.Feeds) error {
+	now := time.Now()
+	feed.UpdatedAt = &now
+
+	result := database.WithWorkspace(r.db, feed.WorkspaceID).Model(feed).Where("owner_id = ?", feed.OwnerID).Select("token_hash", "updated_at").Updates(feed)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[FeedRepository.UpdateFeedToken]: Error updating feed token")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrFeedNotFound, "[FeedRepository.UpdateFeedToken]: Error updating feed token")
+	}
+	return nil
+}
+
+func (r *feedRepository) DeleteFeed(actor domain.Actor, id uint64) error {
+	result := database.WithWorkspace(r.db, actor.WorkspaceID).Where("id = ? AND owner_id = ?", id, actor.UserID).Delete(&models.Feeds{})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[FeedRepository.DeleteFeed]: Error soft deleting feed")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrFeedNotFound, "[FeedRepository.DeleteFeed]: Error soft deleting feed")
+	}
+	return nil
+}
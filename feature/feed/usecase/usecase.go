@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+type feedUsecase struct {
+	feedRepository   domain.FeedRepository
+	eventUsecase     domain.EventUsecase
+	workspaceUsecase domain.WorkspaceUsecase
+}
+
+func NewFeedUsecase(feedRepository domain.FeedRepository, eventUsecase domain.EventUsecase, workspaceUsecase domain.WorkspaceUsecase) domain.FeedUsecase {
+	return &feedUsecase{
+		feedRepository:   feedRepository,
+		eventUsecase:     eventUsecase,
+		workspaceUsecase: workspaceUsecase,
+	}
+}
+
+func (u *feedUsecase) GetFeedList(actor domain.Actor) ([]*response.FeedResponse, error) {
+	feeds, err := u.feedRepository.GetFeedList(actor)
+	if err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.GetFeedList]: Error getting feeds")
+	}
+
+	feedResponses := make([]*response.FeedResponse, 0, len(feeds))
+	for _, feed := range feeds {
+		feedResponses = append(feedResponses, newFeedResponse(feed))
+	}
+	return feedResponses, nil
+}
+
+func (u *feedUsecase) CreateFeed(actor domain.Actor, req *request.FeedRequest) (*response.FeedResponse, error) {
+	token, tokenHash, err := newFeedToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.CreateFeed]: Error generating token")
+	}
+
+	feed := &models.Feeds{
+		OwnerID:     actor.UserID,
+		WorkspaceID: actor.WorkspaceID,
+		Name:        req.Name,
+		TokenHash:   tokenHash,
+		Complete:    req.Complete,
+		Location:    req.Location,
+	}
+	if err := u.feedRepository.CreateFeed(feed); err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.CreateFeed]: Error creating feed")
+	}
+
+	feedResponse := newFeedResponse(feed)
+	feedResponse.Token = &token
+	return feedResponse, nil
+}
+
+func (u *feedUsecase) RotateFeedToken(actor domain.Actor, id uint64) (*response.FeedResponse, error) {
+	feed, err := u.feedRepository.GetFeedByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.RotateFeedToken]: Error getting feed")
+	}
+
+	token, tokenHash, err := newFeedToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.RotateFeedToken]: Error generating token")
+	}
+	feed.TokenHash = tokenHash
+	if err := u.feedRepository.UpdateFeedToken(feed); err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.RotateFeedToken]: Error updating feed token")
+	}
+
+	feedResponse := newFeedResponse(feed)
+	feedResponse.Token = &token
+	return feedResponse, nil
+}
+
+func (u *feedUsecase) DeleteFeed(actor domain.Actor, id uint64) error {
+	if err := u.feedRepository.DeleteFeed(actor, id); err != nil {
+		return errors.Wrap(err, "[FeedUsecase.DeleteFeed]: Error deleting feed")
+	}
+	return nil
+}
+
+func (u *feedUsecase) GetFeedCalendar(token string) (*response.EventExportResponse, error) {
+	feed, err := u.feedRepository.GetFeedByTokenHash(hashFeedToken(token))
+	if err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.GetFeedCalendar]: Error getting feed")
+	}
+
+	// A feed stops showing a workspace once its owner leaves it
+	actor, err := u.workspaceUsecase.ResolveWorkspace(domain.Actor{UserID: feed.OwnerID}, feed.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkspaceNotFound) {
+			err = domain.ErrFeedNotFound
+		}
+		return nil, errors.Wrap(err, "[FeedUsecase.GetFeedCalendar]: Error resolving workspace")
+	}
+
+	export, err := u.eventUsecase.ExportEvents(actor, &request.EventListRequest{
+		Complete: feed.Complete,
+		Location: feed.Location,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[FeedUsecase.GetFeedCalendar]: Error exporting events")
+	}
+
+	// Changing the feed changes what it shows too
+	if export.LastModified == nil || (feed.UpdatedAt != nil && feed.UpdatedAt.After(*export.LastModified)) {
+		export.LastModified = feed.UpdatedAt
+	}
+	return export, nil
+}
+
+// newFeedToken returns a random URL-safe token and the hash stored for it.
+func newFeedToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashFeedToken(token), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newFeedResponse(feed *models.Feeds) *response.FeedResponse {
+	return &response.FeedResponse{
+		ID:        feed.ID,
+		Name:      feed.Name,
+		Complete:  feed.Complete,
+		Location:  feed.Location,
+		CreatedAt: feed.CreatedAt,
+		UpdatedAt: feed.UpdatedAt,
+	}
+}
@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// Mock repository for testing
+type mockFeedRepository struct {
+	feeds       []*models.Feeds
+	shouldError bool
+}
+
+func (m *mockFeedRepository) GetFeedList(actor domain.Actor) ([]*models.Feeds, error) {
+	if m.shouldError {
+		return nil, errors.New("database error")
+	}
+	var feeds []*models.Feeds
+	for _, feed := range m.feeds {
+		if feed.OwnerID == actor.UserID && feed.WorkspaceID == actor.WorkspaceID {
+			feeds = append(feeds, feed)
+		}
+	}
+	return feeds, nil
+}
+
+func (m *mockFeedRepository) GetFeedByID(actor domain.Actor, id uint64) (*models.Feeds, error) {
+	for _, feed := range m.feeds {
+		if feed.ID == id && feed.OwnerID == actor.UserID && feed.WorkspaceID == actor.WorkspaceID {
+			return feed, nil
+		}
+	}
+	return nil, domain.ErrFeedNotFound
+}
+
+func (m *mockFeedRepository) GetFeedByTokenHash(tokenHash string) (*models.Feeds, error) {
+	for _, feed := range m.feeds {
+		if feed.TokenHash == tokenHash {
+			return feed, nil
+		}
+	}
+	return nil, domain.ErrFeedNotFound
+}
+
+func (m *mockFeedRepository) CreateFeed(feed *models.Feeds) error {
+	if m.shouldError {
+		return errors.New("database error")
+	}
+	feed.ID = uint64(len(m.feeds) + 1)
+	now := time.Now()
+	feed.CreatedAt = &now
+	feed.UpdatedAt = &now
+	m.feeds = append(m.feeds, feed)
+	return nil
+}
+
+func (m *mockFeedRepository) UpdateFeedToken(feed *models.Feeds) error {
+	now := time.Now()
+	feed.UpdatedAt = &now
+	return nil
+}
+
+func (m *mockFeedRepository) DeleteFeed(actor domain.Actor, id uint64) error {
+	for i, feed := range m.feeds {
+		if feed.ID == id && feed.OwnerID == actor.UserID && feed.WorkspaceID == actor.WorkspaceID {
+			m.feeds = append(m.feeds[:i], m.feeds[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrFeedNotFound
+}
+
+// Mock event usecase, only exporting is used by feeds
+type mockEventUsecase struct {
+	domain.EventUsecase
+	export    *response.EventExportResponse
+	lastActor domain.Actor
+	lastReq   *request.EventListRequest
+}
+
+// Mock workspace usecase, resolving members of workspaces to the users in
+// members
+type mockWorkspaceUsecase struct {
+	domain.WorkspaceUsecase
+	members map[uint64][]uint64
+}
+
+func (m *mockWorkspaceUsecase) ResolveWorkspace(actor domain.Actor, id uint64) (domain.Actor, error) {
+	if id != constant.PersonalWorkspaceID && !slices.Contains(m.members[id], actor.UserID) {
+		return domain.Actor{}, domain.ErrWorkspaceNotFound
+	}
+	actor.WorkspaceID = id
+	return actor, nil
+}
+
+// testActor owns the feeds in these tests
+var testActor = domain.Actor{UserID: 1}
+
+func (m *mockEventUsecase) ExportEvents(actor domain.Actor, req *request.EventListRequest) (*response.EventExportResponse, error) {
+	m.lastActor = actor
+	m.lastReq = req
+	return m.export, nil
+}
+
+func TestFeedUsecase_CreateFeed(t *testing.T) {
+	complete := false
+	mockRepo := &mockFeedRepository{}
+	usecase := NewFeedUsecase(mockRepo, &mockEventUsecase{}, &mockWorkspaceUsecase{})
+
+	feed, err := usecase.CreateFeed(testActor, &request.FeedRequest{Name: "Phone", Complete: &complete, Location: "Office"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if feed.Token == nil || len(*feed.Token) < 40 {
+		t.Fatalf("Expected a long random token, got %v", feed.Token)
+	}
+	if mockRepo.feeds[0].TokenHash == *feed.Token || mockRepo.feeds[0].TokenHash != hashFeedToken(*feed.Token) {
+		t.Error("Expected only the token hash to be stored")
+	}
+	if feed.Name != "Phone" || *feed.Complete != complete || feed.Location != "Office" {
+		t.Errorf("Expected the requested feed, got %+v", feed)
+	}
+
+	feeds, err := usecase.GetFeedList(testActor)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(feeds) != 1 || feeds[0].Token != nil {
+		t.Errorf("Expected one feed listed without its token, got %+v", feeds)
+	}
+
+	if feeds, _ := usecase.GetFeedList(domain.Actor{UserID: 2}); len(feeds) != 0 {
+		t.Errorf("Expected no feeds for another user, got %+v", feeds)
+	}
+}
+
+func TestFeedUsecase_RotateFeedToken(t *testing.T) {
+	mockRepo := &mockFeedRepository{}
+	usecase := NewFeedUsecase(mockRepo, &mockEventUsecase{export: &response.EventExportResponse{}}, &mockWorkspaceUsecase{})
+
+	created, err := usecase.CreateFeed(testActor, &request.FeedRequest{Name: "Phone"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rotated, err := usecase.RotateFeedToken(testActor, created.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if *rotated.Token == *created.Token {
+		t.Error("Expected a new token")
+	}
+
+	if _, err := usecase.GetFeedCalendar(*created.Token); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Errorf("Expected the old token to stop working, got: %v", err)
+	}
+	if _, err := usecase.GetFeedCalendar(*rotated.Token); err != nil {
+		t.Errorf("Expected the new token to work, got: %v", err)
+	}
+
+	if _, err := usecase.RotateFeedToken(domain.Actor{UserID: 2}, created.ID); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Errorf("Expected another user's feed to be not found, got: %v", err)
+	}
+	if _, err := usecase.RotateFeedToken(testActor, 99); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Errorf("Expected feed not found, got: %v", err)
+	}
+}
+
+func TestFeedUsecase_DeleteFeed(t *testing.T) {
+	mockRepo := &mockFeedRepository{}
+	usecase := NewFeedUsecase(mockRepo, &mockEventUsecase{export: &response.EventExportResponse{}}, &mockWorkspaceUsecase{})
+
+	created, err := usecase.CreateFeed(testActor, &request.FeedRequest{Name: "Phone"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := usecase.DeleteFeed(testActor, created.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.GetFeedCalendar(*created.Token); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Errorf("Expected a revoked token to stop working, got: %v", err)
+	}
+	if err := usecase.DeleteFeed(testActor, created.ID); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Errorf("Expected feed not found, got: %v", err)
+	}
+}
+
+func TestFeedUsecase_GetFeedCalendar(t *testing.T) {
+	complete := true
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		eventsModified       *time.Time
+		feedUpdated          time.Time
+		expectedLastModified *time.Time
+	}{
+		{
+			name:                 "events changed last",
+			eventsModified:       &newer,
+			feedUpdated:          older,
+			expectedLastModified: &newer,
+		},
+		{
+			name:                 "feed changed last",
+			eventsModified:       &older,
+			feedUpdated:          newer,
+			expectedLastModified: &newer,
+		},
+		{
+			name:                 "no events",
+			feedUpdated:          older,
+			expectedLastModified: &older,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedUpdated := tt.feedUpdated
+			mockRepo := &mockFeedRepository{feeds: []*models.Feeds{{
+				ID:        1,
+				OwnerID:   7,
+				Name:      "Phone",
+				TokenHash: hashFeedToken("secret"),
+				Complete:  &complete,
+				Location:  "Office",
+				UpdatedAt: &feedUpdated,
+			}}}
+			mockEvents := &mockEventUsecase{export: &response.EventExportResponse{
+				Calendar:     []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"),
+				LastModified: tt.eventsModified,
+			}}
+			usecase := NewFeedUsecase(mockRepo, mockEvents, &mockWorkspaceUsecase{})
+
+			calendar, err := usecase.GetFeedCalendar("secret")
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if mockEvents.lastActor.UserID != 7 {
+				t.Errorf("Expected the events of the feed owner, got user %d", mockEvents.lastActor.UserID)
+			}
+			if mockEvents.lastReq.Complete != &complete || mockEvents.lastReq.Location != "Office" {
+				t.Errorf("Expected the feed filters to be applied, got %+v", mockEvents.lastReq)
+			}
+			if calendar.LastModified == nil || !calendar.LastModified.Equal(*tt.expectedLastModified) {
+				t.Errorf("Expected last modified %v, got %v", tt.expectedLastModified, calendar.LastModified)
+			}
+		})
+	}
+
+	usecase := NewFeedUsecase(&mockFeedRepository{}, &mockEventUsecase{}, &mockWorkspaceUsecase{})
+	if _, err := usecase.GetFeedCalendar("unknown"); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Errorf("Expected feed not found, got: %v", err)
+	}
+}
+
+func TestFeedUsecase_Workspaces(t *testing.T) {
+	mockRepo := &mockFeedRepository{feeds: []*models.Feeds{{
+		ID:          1,
+		OwnerID:     testActor.UserID,
+		WorkspaceID: 3,
+		Name:        "Class",
+		TokenHash:   hashFeedToken("secret"),
+	}}}
+	mockEvents := &mockEventUsecase{export: &response.EventExportResponse{}}
+	workspaces := &mockWorkspaceUsecase{members: map[uint64][]uint64{3: {testActor.UserID}}}
+	usecase := NewFeedUsecase(mockRepo, mockEvents, workspaces)
+
+	if feeds, _ := usecase.GetFeedList(testActor); len(feeds) != 0 {
+		t.Errorf("Expected no feeds in the personal space, got %d", len(feeds))
+	}
+
+	if _, err := usecase.GetFeedCalendar("secret"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockEvents.lastActor.WorkspaceID != 3 {
+		t.Errorf("Expected the events of the feed's workspace, got workspace %d", mockEvents.lastActor.WorkspaceID)
+	}
+
+	// Leaving the workspace turns the feed off
+	workspaces.members[3] = nil
+	if _, err := usecase.GetFeedCalendar("secret"); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Errorf("Expected feed not found once the owner left, got: %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type tagHandler struct {
+	tagUsecase domain.TagUsecase
+}
+
+func NewTagHandler(tagUsecase domain.TagUsecase) *tagHandler {
+	return &tagHandler{tagUsecase: tagUsecase}
+}
+
+func (h *tagHandler) GetTagList(c *gin.Context) {
+	tags, err := h.tagUsecase.GetTagList(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[TagHandler.GetTagList]: Error getting tag list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.TagResponse]{
+		Status:  constant.Success,
+		Message: "List tags successfully",
+		Data:    tags,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *tagHandler) CreateTag(c *gin.Context) {
+	var req request.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[TagHandler.CreateTag]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	tag, err := h.tagUsecase.CreateTag(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[TagHandler.CreateTag]: Error creating tag")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.TagResponse]{
+		Status:  constant.Success,
+		Message: "Tag created successfully",
+		Data:    tag,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *tagHandler) UpdateTag(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[TagHandler.UpdateTag]: Error parsing tag ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[TagHandler.UpdateTag]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	tag, err := h.tagUsecase.UpdateTag(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[TagHandler.UpdateTag]: Error updating tag")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.TagResponse]{
+		Status:  constant.Success,
+		Message: "Tag updated successfully",
+		Data:    tag,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *tagHandler) DeleteTag(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[TagHandler.DeleteTag]: Error parsing tag ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.tagUsecase.DeleteTag(middlewares.GetActor(c), id); err != nil {
+		err = errors.Wrap(err, "[TagHandler.DeleteTag]: Error deleting tag")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Tag deleted successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *tagHandler) MergeTag(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[TagHandler.MergeTag]: Error parsing tag ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.TagMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[TagHandler.MergeTag]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	tag, err := h.tagUsecase.MergeTag(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[TagHandler.MergeTag]: Error merging tags")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.TagResponse]{
+		Status:  constant.Success,
+		Message: "Tags merged successfully",
+		Data:    tag,
+	}
+	c.JSON(http.StatusOK, resp)
+}
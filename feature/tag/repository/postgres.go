@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) domain.TagRepository {
+	return &tagRepository{db: db}
+}
+
+// scopeTags limits query to the tags of actor's workspace that actor owns.
+func scopeTags(query *gorm.DB, actor domain.Actor) *gorm.DB {
+	return database.WithWorkspace(query, actor.WorkspaceID).Where("tags.owner_id = ?", actor.UserID)
+}
+
+func (r *tagRepository) GetTagList(actor domain.Actor) ([]*models.Tags, error) {
+	var tags []*models.Tags
+	if err := scopeTags(r.db, actor).Order("name").Find(&tags).Error; err != nil {
+		return nil, errors.Wrap(err, "[TagRepository.GetTagList]: Error getting tags")
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) GetTagByID(actor domain.Actor, id uint64) (*models.Tags, error) {
+	var tag models.Tags
+	if err := scopeTags(r.db, actor).Where("id = ?", id).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrTagNotFound, "[TagRepository.GetTagByID]: Error getting tag")
+		}
+		return nil, errors.Wrap(err, "[TagRepository.GetTagByID]: Error getting tag")
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) GetTagByName(actor domain.Actor, name string) (*models.Tags, error) {
+	var tag models.Tags
+	if err := scopeTags(r.db, actor).Where("name = ?", name).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrTagNotFound, "[TagRepository.GetTagByName]: Error getting tag")
+		}
+		return nil, errors.Wrap(err, "[TagRepository.GetTagByName]: Error getting tag")
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) CountTagEvents(actor domain.Actor, ids []uint64) (map[uint64]int64, error) {
+	counts := make(map[uint64]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	// Trashed events don't count
+	var rows []struct {
+		TagID uint64
+		Count int64
+	}
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Model(&models.Events{}).
+		Select("event_tags.tag_id, COUNT(*) AS count").
+		Joins("JOIN event_tags ON event_tags.event_id = events.id").
+		Where("event_tags.tag_id IN ?", ids).
+		Group("event_tags.tag_id").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "[TagRepository.CountTagEvents]: Error counting tagged events")
+	}
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *tagRepository) CreateTag(tag *models.Tags) error {
+	now := time.Now()
+	tag.CreatedAt = &now
+	tag.UpdatedAt = &now
+
+	if err := database.WithWorkspace(r.db, tag.WorkspaceID).Create(tag).Error; err != nil {
+		return errors.Wrap(err, "[TagRepository.CreateTag]: Error creating tag")
+	}
+	return nil
+}
+
+func (r *tagRepository) UpdateTag(tag *models.Tags) error {
+	now := time.Now()
+	tag.UpdatedAt = &now
+
+	err := database.WithWorkspace(r.db, tag.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(tag).
+			Where("owner_id = ?", tag.OwnerID).
+			Select("name", "updated_at").
+			Updates(tag)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTagNotFound
+		}
+		changes, err := touchTaggedEvents(tx, tag.ID, now)
+		if err != nil {
+			return err
+		}
+		return database.CreateOutboxEvents(tx, changes...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[TagRepository.UpdateTag]: Error updating tag")
+	}
+	return nil
+}
+
+func (r *tagRepository) DeleteTag(tag *models.Tags) error {
+	err := database.WithWorkspace(r.db, tag.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		changes, err := touchTaggedEvents(tx, tag.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&models.EventTags{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ? AND owner_id = ?", tag.ID, tag.OwnerID).Delete(&models.Tags{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTagNotFound
+		}
+		return database.CreateOutboxEvents(tx, changes...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[TagRepository.DeleteTag]: Error deleting tag")
+	}
+	return nil
+}
+
+func (r *tagRepository) MergeTag(source, target *models.Tags) error {
+	err := database.WithWorkspace(r.db, source.WorkspaceID).Transaction(func(tx *gorm.DB) error {
+		changes, err := touchTaggedEvents(tx, source.ID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// Events carrying both tags keep a single link to target
+		if err := tx.Exec("INSERT INTO event_tags (event_id, tag_id) "+
+			"SELECT event_id, ? FROM event_tags WHERE tag_id = ? ON CONFLICT DO NOTHING",
+			target.ID, source.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", source.ID).Delete(&models.EventTags{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ? AND owner_id = ?", source.ID, source.OwnerID).Delete(&models.Tags{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTagNotFound
+		}
+		return database.CreateOutboxEvents(tx, changes...)
+	})
+	if err != nil {
+		return errors.Wrap(err, "[TagRepository.MergeTag]: Error merging tags")
+	}
+	return nil
+}
+
+// touchTaggedEvents bumps the version of every event carrying the tag
+// tagID, trashed ones included, since what they show changes with it. It
+// returns the updates to publish once the tag is saved, for the events not
+// in the trash; the others were already published as deleted.
+func touchTaggedEvents(tx *gorm.DB, tagID uint64, now time.Time) ([]*models.OutboxEvents, error) {
+	tagged := tx.Session(&gorm.Session{NewDB: true}).Model(&models.EventTags{}).
+		Select("event_id").
+		Where("tag_id = ?", tagID)
+	var events []*models.Events
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "owner_id", "calendar_id", "version", "delete_at").
+		Where("id IN (?)", tagged).
+		Order("id").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	if err := tx.Unscoped().Model(&models.Events{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	var changes []*models.OutboxEvents
+	for _, event := range events {
+		if event.DeleteAt.Valid {
+			continue
+		}
+		event.Version++
+		changes = append(changes, database.NewOutboxEvent(event, constant.WebhookEventUpdated))
+	}
+	return changes, nil
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+)
+
+type tagUsecase struct {
+	tagRepository domain.TagRepository
+}
+
+func NewTagUsecase(tagRepository domain.TagRepository) domain.TagUsecase {
+	return &tagUsecase{tagRepository: tagRepository}
+}
+
+func (u *tagUsecase) GetTagList(actor domain.Actor) ([]*response.TagResponse, error) {
+	tags, err := u.tagRepository.GetTagList(actor)
+	if err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.GetTagList]: Error getting tags")
+	}
+
+	ids := make([]uint64, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	counts, err := u.tagRepository.CountTagEvents(actor, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.GetTagList]: Error counting tagged events")
+	}
+
+	tagResponses := make([]*response.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		tagResponses = append(tagResponses, newTagResponse(tag, counts[tag.ID]))
+	}
+	return tagResponses, nil
+}
+
+func (u *tagUsecase) CreateTag(actor domain.Actor, req *request.TagRequest) (*response.TagResponse, error) {
+	name, err := u.validateName(actor, req.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.CreateTag]: Invalid name")
+	}
+
+	tag := &models.Tags{
+		WorkspaceID: actor.WorkspaceID,
+		OwnerID:     actor.UserID,
+		Name:        name,
+	}
+	if err := u.tagRepository.CreateTag(tag); err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.CreateTag]: Error creating tag")
+	}
+	return newTagResponse(tag, 0), nil
+}
+
+func (u *tagUsecase) UpdateTag(actor domain.Actor, id uint64, req *request.TagRequest) (*response.TagResponse, error) {
+	tag, err := u.tagRepository.GetTagByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.UpdateTag]: Error getting tag")
+	}
+
+	if utils.NormalizeTag(req.Name) != tag.Name {
+		name, err := u.validateName(actor, req.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "[TagUsecase.UpdateTag]: Invalid name")
+		}
+		tag.Name = name
+		if err := u.tagRepository.UpdateTag(tag); err != nil {
+			return nil, errors.Wrap(err, "[TagUsecase.UpdateTag]: Error updating tag")
+		}
+	}
+
+	tagResponse, err := u.getTagResponse(actor, tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.UpdateTag]: Error counting tagged events")
+	}
+	return tagResponse, nil
+}
+
+func (u *tagUsecase) DeleteTag(actor domain.Actor, id uint64) error {
+	tag, err := u.tagRepository.GetTagByID(actor, id)
+	if err != nil {
+		return errors.Wrap(err, "[TagUsecase.DeleteTag]: Error getting tag")
+	}
+
+	if err := u.tagRepository.DeleteTag(tag); err != nil {
+		return errors.Wrap(err, "[TagUsecase.DeleteTag]: Error deleting tag")
+	}
+	return nil
+}
+
+func (u *tagUsecase) MergeTag(actor domain.Actor, id uint64, req *request.TagMergeRequest) (*response.TagResponse, error) {
+	if req.TargetID == id {
+		return nil, errors.Wrap(domain.NewValidationError("a tag cannot be merged into itself", response.FieldError{
+			Field: "targetId", Rule: "ne", Message: "targetId must be another tag",
+		}), "[TagUsecase.MergeTag]: Invalid target")
+	}
+
+	source, err := u.tagRepository.GetTagByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.MergeTag]: Error getting tag")
+	}
+
+	target, err := u.tagRepository.GetTagByID(actor, req.TargetID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			err = domain.NewValidationError("target tag not found", response.FieldError{
+				Field: "targetId", Rule: "exists", Message: "targetId must be one of your tags",
+			})
+		}
+		return nil, errors.Wrap(err, "[TagUsecase.MergeTag]: Error getting target tag")
+	}
+
+	if err := u.tagRepository.MergeTag(source, target); err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.MergeTag]: Error merging tags")
+	}
+
+	tagResponse, err := u.getTagResponse(actor, target)
+	if err != nil {
+		return nil, errors.Wrap(err, "[TagUsecase.MergeTag]: Error counting tagged events")
+	}
+	return tagResponse, nil
+}
+
+// validateName normalizes name and checks that actor has no tag by it yet.
+func (u *tagUsecase) validateName(actor domain.Actor, name string) (string, error) {
+	name = utils.NormalizeTag(name)
+	if name == "" {
+		return "", domain.NewValidationError("name must not be blank", response.FieldError{
+			Field: "name", Rule: "required", Message: "name must not be blank",
+		})
+	}
+	// Event lists filter on comma separated tags
+	if strings.Contains(name, ",") {
+		return "", domain.NewValidationError("name must not contain commas", response.FieldError{
+			Field: "name", Rule: "excludes", Message: "name must not contain commas",
+		})
+	}
+
+	_, err := u.tagRepository.GetTagByName(actor, name)
+	if err == nil {
+		return "", domain.ErrTagExists
+	}
+	if !errors.Is(err, domain.ErrTagNotFound) {
+		return "", err
+	}
+	return name, nil
+}
+
+// getTagResponse renders tag with the number of events carrying it.
+func (u *tagUsecase) getTagResponse(actor domain.Actor, tag *models.Tags) (*response.TagResponse, error) {
+	counts, err := u.tagRepository.CountTagEvents(actor, []uint64{tag.ID})
+	if err != nil {
+		return nil, err
+	}
+	return newTagResponse(tag, counts[tag.ID]), nil
+}
+
+func newTagResponse(tag *models.Tags, eventCount int64) *response.TagResponse {
+	return &response.TagResponse{
+		ID:         tag.ID,
+		Name:       tag.Name,
+		EventCount: eventCount,
+		CreatedAt:  tag.CreatedAt,
+		UpdatedAt:  tag.UpdatedAt,
+	}
+}
@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+// Mock repository for testing
+type mockTagRepository struct {
+	tags   []*models.Tags
+	events map[uint64][]uint64 // ids of the events carrying each tag
+}
+
+func newMockTagRepository(tags ...*models.Tags) *mockTagRepository {
+	return &mockTagRepository{tags: tags, events: make(map[uint64][]uint64)}
+}
+
+func (m *mockTagRepository) owns(actor domain.Actor, tag *models.Tags) bool {
+	return tag.OwnerID == actor.UserID && tag.WorkspaceID == actor.WorkspaceID
+}
+
+func (m *mockTagRepository) GetTagList(actor domain.Actor) ([]*models.Tags, error) {
+	var tags []*models.Tags
+	for _, tag := range m.tags {
+		if m.owns(actor, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (m *mockTagRepository) GetTagByID(actor domain.Actor, id uint64) (*models.Tags, error) {
+	for _, tag := range m.tags {
+		if tag.ID == id && m.owns(actor, tag) {
+			return tag, nil
+		}
+	}
+	return nil, domain.ErrTagNotFound
+}
+
+func (m *mockTagRepository) GetTagByName(actor domain.Actor, name string) (*models.Tags, error) {
+	for _, tag := range m.tags {
+		if tag.Name == name && m.owns(actor, tag) {
+			return tag, nil
+		}
+	}
+	return nil, domain.ErrTagNotFound
+}
+
+func (m *mockTagRepository) CountTagEvents(actor domain.Actor, ids []uint64) (map[uint64]int64, error) {
+	counts := make(map[uint64]int64)
+	for _, id := range ids {
+		counts[id] = int64(len(m.events[id]))
+	}
+	return counts, nil
+}
+
+func (m *mockTagRepository) CreateTag(tag *models.Tags) error {
+	tag.ID = uint64(len(m.tags) + 1)
+	now := time.Now()
+	tag.CreatedAt = &now
+	tag.UpdatedAt = &now
+	m.tags = append(m.tags, tag)
+	return nil
+}
+
+func (m *mockTagRepository) UpdateTag(tag *models.Tags) error {
+	now := time.Now()
+	tag.UpdatedAt = &now
+	return nil
+}
+
+func (m *mockTagRepository) DeleteTag(tag *models.Tags) error {
+	m.tags = slices.DeleteFunc(m.tags, func(t *models.Tags) bool { return t.ID == tag.ID })
+	delete(m.events, tag.ID)
+	return nil
+}
+
+func (m *mockTagRepository) MergeTag(source, target *models.Tags) error {
+	for _, eventID := range m.events[source.ID] {
+		if !slices.Contains(m.events[target.ID], eventID) {
+			m.events[target.ID] = append(m.events[target.ID], eventID)
+		}
+	}
+	return m.DeleteTag(source)
+}
+
+var testActor = domain.Actor{UserID: 1}
+
+func createTestTag(id uint64, ownerID uint64, name string) *models.Tags {
+	now := time.Now()
+	return &models.Tags{ID: id, OwnerID: ownerID, Name: name, CreatedAt: &now, UpdatedAt: &now}
+}
+
+func TestTagUsecase_GetTagList(t *testing.T) {
+	mockRepo := newMockTagRepository(createTestTag(1, 1, "exam"), createTestTag(2, 2, "other"), createTestTag(3, 1, "math"))
+	mockRepo.events[1] = []uint64{10, 11}
+
+	usecase := NewTagUsecase(mockRepo)
+	tags, err := usecase.GetTagList(testActor)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(tags) != 2 || tags[0].Name != "exam" || tags[1].Name != "math" {
+		t.Fatalf("Expected the actor's 2 tags, got %+v", tags)
+	}
+	if tags[0].EventCount != 2 || tags[1].EventCount != 0 {
+		t.Errorf("Expected event counts 2 and 0, got %d and %d", tags[0].EventCount, tags[1].EventCount)
+	}
+}
+
+func TestTagUsecase_CreateTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		tagName        string
+		expectedName   string
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name:         "name is normalized",
+			tagName:      "  Final   Exam ",
+			expectedName: "final exam",
+		},
+		{
+			name:           "name taken in another case",
+			tagName:        "MATH",
+			expectedError:  true,
+			expectedErrMsg: domain.ErrTagExists.Error(),
+		},
+		{
+			name:           "blank name",
+			tagName:        "   ",
+			expectedError:  true,
+			expectedErrMsg: "name must not be blank",
+		},
+		{
+			name:           "name with a comma",
+			tagName:        "exam,math",
+			expectedError:  true,
+			expectedErrMsg: "name must not contain commas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockTagRepository(createTestTag(1, 1, "math"))
+			usecase := NewTagUsecase(mockRepo)
+			result, err := usecase.CreateTag(testActor, &request.TagRequest{Name: tt.tagName})
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.Name != tt.expectedName {
+				t.Errorf("Expected name %q, got %q", tt.expectedName, result.Name)
+			}
+			if tag := mockRepo.tags[len(mockRepo.tags)-1]; tag.OwnerID != testActor.UserID {
+				t.Errorf("Expected a tag of the actor, got owner %d", tag.OwnerID)
+			}
+		})
+	}
+}
+
+func TestTagUsecase_UpdateTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             uint64
+		tagName        string
+		expectedName   string
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name:         "rename",
+			id:           1,
+			tagName:      "Exams",
+			expectedName: "exams",
+		},
+		{
+			name:         "same name in another case",
+			id:           1,
+			tagName:      "EXAM",
+			expectedName: "exam",
+		},
+		{
+			name:           "rename onto another tag",
+			id:             1,
+			tagName:        "math",
+			expectedError:  true,
+			expectedErrMsg: domain.ErrTagExists.Error(),
+		},
+		{
+			name:           "tag of another user",
+			id:             3,
+			tagName:        "anything",
+			expectedError:  true,
+			expectedErrMsg: domain.ErrTagNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockTagRepository(createTestTag(1, 1, "exam"), createTestTag(2, 1, "math"), createTestTag(3, 2, "other"))
+			mockRepo.events[1] = []uint64{10}
+
+			usecase := NewTagUsecase(mockRepo)
+			result, err := usecase.UpdateTag(testActor, tt.id, &request.TagRequest{Name: tt.tagName})
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.Name != tt.expectedName || result.EventCount != 1 {
+				t.Errorf("Expected %q on 1 event, got %q on %d", tt.expectedName, result.Name, result.EventCount)
+			}
+		})
+	}
+}
+
+func TestTagUsecase_MergeTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             uint64
+		targetID       uint64
+		expectedError  bool
+		expectedErrMsg string
+	}{
+		{
+			name:     "merge into another tag",
+			id:       1,
+			targetID: 2,
+		},
+		{
+			name:           "merge into itself",
+			id:             1,
+			targetID:       1,
+			expectedError:  true,
+			expectedErrMsg: "a tag cannot be merged into itself",
+		},
+		{
+			name:           "target of another user",
+			id:             1,
+			targetID:       3,
+			expectedError:  true,
+			expectedErrMsg: "target tag not found",
+		},
+		{
+			name:           "source not found",
+			id:             9,
+			targetID:       2,
+			expectedError:  true,
+			expectedErrMsg: domain.ErrTagNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newMockTagRepository(createTestTag(1, 1, "exams"), createTestTag(2, 1, "exam"), createTestTag(3, 2, "other"))
+			mockRepo.events[1] = []uint64{10, 11}
+			mockRepo.events[2] = []uint64{11, 12}
+
+			usecase := NewTagUsecase(mockRepo)
+			result, err := usecase.MergeTag(testActor, tt.id, &request.TagMergeRequest{TargetID: tt.targetID})
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.expectedErrMsg) {
+					t.Errorf("Expected error message to contain '%s', got: %v", tt.expectedErrMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+
+			if result.ID != 2 || result.EventCount != 3 {
+				t.Errorf("Expected tag 2 on 3 events, got tag %d on %d", result.ID, result.EventCount)
+			}
+			if _, err := mockRepo.GetTagByID(testActor, 1); err == nil {
+				t.Error("Expected the merged tag deleted")
+			}
+		})
+	}
+}
+
+func TestTagUsecase_DeleteTag(t *testing.T) {
+	mockRepo := newMockTagRepository(createTestTag(1, 1, "exam"), createTestTag(2, 2, "other"))
+	usecase := NewTagUsecase(mockRepo)
+
+	if err := usecase.DeleteTag(testActor, 2); err == nil || !strings.Contains(err.Error(), domain.ErrTagNotFound.Error()) {
+		t.Errorf("Expected another user's tag not found, got: %v", err)
+	}
+	if err := usecase.DeleteTag(testActor, 1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mockRepo.tags) != 1 || mockRepo.tags[0].ID != 2 {
+		t.Errorf("Expected only the other user's tag left, got %+v", mockRepo.tags)
+	}
+}
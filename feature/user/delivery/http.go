@@ -0,0 +1,207 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type userHandler struct {
+	userUsecase domain.UserUsecase
+}
+
+func NewUserHandler(userUsecase domain.UserUsecase) *userHandler {
+	return &userHandler{userUsecase: userUsecase}
+}
+
+func (h *userHandler) Register(c *gin.Context) {
+	var req request.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[UserHandler.Register]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	auth, err := h.userUsecase.Register(&req)
+	if err != nil {
+		err = errors.Wrap(err, "[UserHandler.Register]: Error registering user")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.AuthResponse]{
+		Status:  constant.Success,
+		Message: "User registered successfully",
+		Data:    auth,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *userHandler) Login(c *gin.Context) {
+	var req request.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[UserHandler.Login]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	auth, err := h.userUsecase.Login(&req)
+	if err != nil {
+		err = errors.Wrap(err, "[UserHandler.Login]: Error logging in")
+		log.Warn(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.AuthResponse]{
+		Status:  constant.Success,
+		Message: "Logged in successfully",
+		Data:    auth,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *userHandler) RefreshToken(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[UserHandler.RefreshToken]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	auth, err := h.userUsecase.RefreshToken(&req)
+	if err != nil {
+		err = errors.Wrap(err, "[UserHandler.RefreshToken]: Error refreshing token")
+		log.Warn(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.AuthResponse]{
+		Status:  constant.Success,
+		Message: "Token refreshed successfully",
+		Data:    auth,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *userHandler) Logout(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[UserHandler.Logout]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.userUsecase.Logout(&req); err != nil {
+		err = errors.Wrap(err, "[UserHandler.Logout]: Error logging out")
+		log.Warn(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Logged out successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *userHandler) GetMe(c *gin.Context) {
+	user, err := h.userUsecase.GetMe(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[UserHandler.GetMe]: Error getting user")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.UserResponse]{
+		Status:  constant.Success,
+		Message: "Get user successfully",
+		Data:    user,
+	}
+	c.JSON(http.StatusOK, resp)
+}
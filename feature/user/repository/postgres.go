@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) domain.UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) GetUserByID(id uint64) (*models.Users, error) {
+	var user models.Users
+	if err := r.db.Where("id = ?", id).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrUserNotFound, "[UserRepository.GetUserByID]: Error getting user")
+		}
+		return nil, errors.Wrap(err, "[UserRepository.GetUserByID]: Error getting user")
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetUserByEmail(email string) (*models.Users, error) {
+	var user models.Users
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrUserNotFound, "[UserRepository.GetUserByEmail]: Error getting user")
+		}
+		return nil, errors.Wrap(err, "[UserRepository.GetUserByEmail]: Error getting user")
+	}
+	return &user, nil
+}
+
+func (r *userRepository) CreateUser(user *models.Users) error {
+	now := time.Now()
+	user.CreatedAt = &now
+	user.UpdatedAt = &now
+
+	if err := r.db.Create(user).Error; err != nil {
+		return errors.Wrap(err, "[UserRepository.CreateUser]: Error creating user")
+	}
+	return nil
+}
+
+func (r *userRepository) CreateRefreshToken(token *models.RefreshTokens) error {
+	now := time.Now()
+	token.CreatedAt = &now
+
+	if err := r.db.Create(token).Error; err != nil {
+		return errors.Wrap(err, "[UserRepository.CreateRefreshToken]: Error creating refresh token")
+	}
+	return nil
+}
+
+func (r *userRepository) GetRefreshTokenByHash(tokenHash string) (*models.RefreshTokens, error) {
+	var token models.RefreshTokens
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrInvalidToken, "[UserRepository.GetRefreshTokenByHash]: Error getting refresh token")
+		}
+		return nil, errors.Wrap(err, "[UserRepository.GetRefreshTokenByHash]: Error getting refresh token")
+	}
+	return &token, nil
+}
+
+func (r *userRepository) RevokeRefreshToken(id uint64) error {
+	result := r.db.Model(&models.RefreshTokens{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[UserRepository.RevokeRefreshToken]: Error revoking refresh token")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrInvalidToken, "[UserRepository.RevokeRefreshToken]: Error revoking refresh token")
+	}
+	return nil
+}
+
+func (r *userRepository) RevokeUserRefreshTokens(userID uint64) error {
+	if err := r.db.Model(&models.RefreshTokens{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return errors.Wrap(err, "[UserRepository.RevokeUserRefreshTokens]: Error revoking refresh tokens")
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	tokenIssuer = "g12-todo"
+	tokenType   = "Bearer"
+)
+
+// dummyPasswordHash is compared against when logging in with an unknown
+// email, so both failures take as long as a wrong password.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("g12-todo-dummy-password"), bcrypt.DefaultCost)
+
+type userUsecase struct {
+	userRepository  domain.UserRepository
+	jwtSecret       []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// NewUserUsecase signs access tokens with jwtSecret (HS256). Access tokens
+// live for accessTokenTTL and refresh tokens for refreshTokenTTL.
+func NewUserUsecase(userRepository domain.UserRepository, jwtSecret []byte, accessTokenTTL, refreshTokenTTL time.Duration) domain.UserUsecase {
+	return &userUsecase{
+		userRepository:  userRepository,
+		jwtSecret:       jwtSecret,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+func (u *userUsecase) Register(req *request.RegisterRequest) (*response.AuthResponse, error) {
+	email := normalizeEmail(req.Email)
+	if _, err := u.userRepository.GetUserByEmail(email); err == nil {
+		return nil, errors.Wrap(domain.ErrEmailTaken, "[UserUsecase.Register]: Duplicate email")
+	} else if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, errors.Wrap(err, "[UserUsecase.Register]: Error checking email")
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.Register]: Error hashing password")
+	}
+
+	user := &models.Users{
+		Email:        email,
+		Name:         strings.TrimSpace(req.Name),
+		PasswordHash: string(passwordHash),
+	}
+	if err := u.userRepository.CreateUser(user); err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.Register]: Error creating user")
+	}
+
+	auth, err := u.issueTokens(user)
+	if err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.Register]: Error issuing tokens")
+	}
+	return auth, nil
+}
+
+func (u *userUsecase) Login(req *request.LoginRequest) (*response.AuthResponse, error) {
+	user, err := u.userRepository.GetUserByEmail(normalizeEmail(req.Email))
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, errors.Wrap(err, "[UserUsecase.Login]: Error getting user")
+	}
+
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = []byte(user.PasswordHash)
+	}
+	if err := bcrypt.CompareHashAndPassword(passwordHash, []byte(req.Password)); err != nil || user == nil {
+		return nil, errors.Wrap(domain.ErrInvalidCredentials, "[UserUsecase.Login]: Error checking password")
+	}
+
+	auth, err := u.issueTokens(user)
+	if err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.Login]: Error issuing tokens")
+	}
+	return auth, nil
+}
+
+func (u *userUsecase) RefreshToken(req *request.RefreshTokenRequest) (*response.AuthResponse, error) {
+	token, err := u.userRepository.GetRefreshTokenByHash(hashToken(req.RefreshToken))
+	if err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.RefreshToken]: Error getting refresh token")
+	}
+
+	// A revoked token coming back means it leaked: whoever refreshed it
+	// first may not be the user, so end every session of theirs
+	if token.RevokedAt != nil {
+		if err := u.userRepository.RevokeUserRefreshTokens(token.UserID); err != nil {
+			return nil, errors.Wrap(err, "[UserUsecase.RefreshToken]: Error revoking refresh tokens")
+		}
+		return nil, errors.Wrap(domain.ErrInvalidToken, "[UserUsecase.RefreshToken]: Refresh token reused")
+	}
+	if !time.Now().Before(token.ExpiresAt) {
+		return nil, errors.Wrap(domain.ErrInvalidToken, "[UserUsecase.RefreshToken]: Refresh token expired")
+	}
+
+	if err := u.userRepository.RevokeRefreshToken(token.ID); err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.RefreshToken]: Error revoking refresh token")
+	}
+
+	user, err := u.userRepository.GetUserByID(token.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, errors.Wrap(domain.ErrInvalidToken, "[UserUsecase.RefreshToken]: Error getting user")
+		}
+		return nil, errors.Wrap(err, "[UserUsecase.RefreshToken]: Error getting user")
+	}
+
+	auth, err := u.issueTokens(user)
+	if err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.RefreshToken]: Error issuing tokens")
+	}
+	return auth, nil
+}
+
+func (u *userUsecase) Logout(req *request.RefreshTokenRequest) error {
+	token, err := u.userRepository.GetRefreshTokenByHash(hashToken(req.RefreshToken))
+	if err != nil {
+		return errors.Wrap(err, "[UserUsecase.Logout]: Error getting refresh token")
+	}
+
+	// Logging out twice is fine
+	if token.RevokedAt != nil {
+		return nil
+	}
+	if err := u.userRepository.RevokeRefreshToken(token.ID); err != nil && !errors.Is(err, domain.ErrInvalidToken) {
+		return errors.Wrap(err, "[UserUsecase.Logout]: Error revoking refresh token")
+	}
+	return nil
+}
+
+func (u *userUsecase) GetMe(actor domain.Actor) (*response.UserResponse, error) {
+	user, err := u.userRepository.GetUserByID(actor.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.GetMe]: Error getting user")
+	}
+	return newUserResponse(user), nil
+}
+
+func (u *userUsecase) Authenticate(accessToken string) (domain.Actor, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(*jwt.Token) (interface{}, error) {
+		return u.jwtSecret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return domain.Actor{}, errors.Wrap(domain.ErrInvalidToken, "[UserUsecase.Authenticate]: "+err.Error())
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || userID == 0 {
+		return domain.Actor{}, errors.Wrap(domain.ErrInvalidToken, "[UserUsecase.Authenticate]: Invalid subject")
+	}
+	return domain.Actor{UserID: userID}, nil
+}
+
+// issueTokens signs an access token for user and stores a new refresh token.
+func (u *userUsecase) issueTokens(user *models.Users) (*response.AuthResponse, error) {
+	now := time.Now()
+	accessTokenExpiresAt := now.Add(u.accessTokenTTL)
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    tokenIssuer,
+		Subject:   strconv.FormatUint(user.ID, 10),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(accessTokenExpiresAt),
+	}).SignedString(u.jwtSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error signing access token")
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating refresh token")
+	}
+	token := &models.RefreshTokens{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(u.refreshTokenTTL),
+	}
+	if err := u.userRepository.CreateRefreshToken(token); err != nil {
+		return nil, err
+	}
+
+	return &response.AuthResponse{
+		TokenType:             tokenType,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: token.ExpiresAt,
+		User:                  newUserResponse(user),
+	}, nil
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func newUserResponse(user *models.Users) *response.UserResponse {
+	return &response.UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
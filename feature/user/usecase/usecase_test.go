@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+// Mock repository for testing
+type mockUserRepository struct {
+	users  []*models.Users
+	tokens []*models.RefreshTokens
+}
+
+func (m *mockUserRepository) GetUserByID(id uint64) (*models.Users, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (m *mockUserRepository) GetUserByEmail(email string) (*models.Users, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (m *mockUserRepository) CreateUser(user *models.Users) error {
+	user.ID = uint64(len(m.users) + 1)
+	now := time.Now()
+	user.CreatedAt = &now
+	user.UpdatedAt = &now
+	m.users = append(m.users, user)
+	return nil
+}
+
+func (m *mockUserRepository) CreateRefreshToken(token *models.RefreshTokens) error {
+	token.ID = uint64(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *mockUserRepository) GetRefreshTokenByHash(tokenHash string) (*models.RefreshTokens, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, domain.ErrInvalidToken
+}
+
+func (m *mockUserRepository) RevokeRefreshToken(id uint64) error {
+	for _, token := range m.tokens {
+		if token.ID == id && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrInvalidToken
+}
+
+func (m *mockUserRepository) RevokeUserRefreshTokens(userID uint64) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+var testSecret = []byte("test-secret")
+
+func newTestUserUsecase(mockRepo *mockUserRepository) domain.UserUsecase {
+	return NewUserUsecase(mockRepo, testSecret, 15*time.Minute, time.Hour)
+}
+
+func registerTestUser(t *testing.T, usecase domain.UserUsecase) {
+	t.Helper()
+	if _, err := usecase.Register(&request.RegisterRequest{
+		Email:    " Alex@Example.com",
+		Name:     "Alex",
+		Password: "correct horse",
+	}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
+
+func TestUserUsecase_Register(t *testing.T) {
+	mockRepo := &mockUserRepository{}
+	usecase := newTestUserUsecase(mockRepo)
+
+	auth, err := usecase.Register(&request.RegisterRequest{Email: "Alex@Example.com", Name: "Alex", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	user := mockRepo.users[0]
+	if user.Email != "alex@example.com" || auth.User.Email != user.Email {
+		t.Errorf("Expected the email to be stored lower-cased, got %q", user.Email)
+	}
+	if user.PasswordHash == "correct horse" || !strings.HasPrefix(user.PasswordHash, "$2") {
+		t.Errorf("Expected a bcrypt password hash, got %q", user.PasswordHash)
+	}
+	if auth.TokenType != "Bearer" || auth.AccessToken == "" || auth.RefreshToken == "" {
+		t.Errorf("Expected a pair of tokens, got %+v", auth)
+	}
+	if mockRepo.tokens[0].TokenHash == auth.RefreshToken {
+		t.Error("Expected only the refresh token hash to be stored")
+	}
+
+	_, err = usecase.Register(&request.RegisterRequest{Email: "alex@example.com", Name: "Alex", Password: "another one"})
+	if !errors.Is(err, domain.ErrEmailTaken) {
+		t.Errorf("Expected email taken, got: %v", err)
+	}
+}
+
+func TestUserUsecase_Login(t *testing.T) {
+	tests := []struct {
+		name          string
+		email         string
+		password      string
+		expectedError bool
+	}{
+		{
+			name:     "valid credentials",
+			email:    "ALEX@example.com",
+			password: "correct horse",
+		},
+		{
+			name:          "wrong password",
+			email:         "alex@example.com",
+			password:      "wrong horse",
+			expectedError: true,
+		},
+		{
+			name:          "unknown email",
+			email:         "sam@example.com",
+			password:      "correct horse",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := newTestUserUsecase(&mockUserRepository{})
+			registerTestUser(t, usecase)
+
+			auth, err := usecase.Login(&request.LoginRequest{Email: tt.email, Password: tt.password})
+
+			if tt.expectedError {
+				if !errors.Is(err, domain.ErrInvalidCredentials) {
+					t.Errorf("Expected invalid credentials, got: %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			actor, err := usecase.Authenticate(auth.AccessToken)
+			if err != nil || actor.UserID != auth.User.ID {
+				t.Errorf("Expected the access token to authenticate user %d, got %+v (%v)", auth.User.ID, actor, err)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_RefreshToken(t *testing.T) {
+	mockRepo := &mockUserRepository{}
+	usecase := newTestUserUsecase(mockRepo)
+	registerTestUser(t, usecase)
+	first := mockRepo.tokens[0]
+
+	login, err := usecase.Login(&request.LoginRequest{Email: "alex@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	refreshed, err := usecase.RefreshToken(&request.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if refreshed.RefreshToken == login.RefreshToken {
+		t.Error("Expected a new refresh token")
+	}
+
+	// Presenting the spent token again revokes every session of the user
+	_, err = usecase.RefreshToken(&request.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected a reused token to be invalid, got: %v", err)
+	}
+	if _, err := usecase.RefreshToken(&request.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken}); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected the rotated token to be revoked after reuse, got: %v", err)
+	}
+	if first.RevokedAt == nil {
+		t.Error("Expected the other sessions of the user to be revoked")
+	}
+
+	if _, err := usecase.RefreshToken(&request.RefreshTokenRequest{RefreshToken: "unknown"}); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected an unknown token to be invalid, got: %v", err)
+	}
+}
+
+func TestUserUsecase_RefreshToken_Expired(t *testing.T) {
+	mockRepo := &mockUserRepository{}
+	usecase := NewUserUsecase(mockRepo, testSecret, 15*time.Minute, -time.Minute)
+	registerTestUser(t, usecase)
+
+	login, err := usecase.Login(&request.LoginRequest{Email: "alex@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.RefreshToken(&request.RefreshTokenRequest{RefreshToken: login.RefreshToken}); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected an expired token to be invalid, got: %v", err)
+	}
+}
+
+func TestUserUsecase_Logout(t *testing.T) {
+	mockRepo := &mockUserRepository{}
+	usecase := newTestUserUsecase(mockRepo)
+	registerTestUser(t, usecase)
+
+	login, err := usecase.Login(&request.LoginRequest{Email: "alex@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	req := &request.RefreshTokenRequest{RefreshToken: login.RefreshToken}
+	if err := usecase.Logout(req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := usecase.Logout(req); err != nil {
+		t.Errorf("Expected logging out twice to succeed, got: %v", err)
+	}
+	if mockRepo.tokens[0].RevokedAt != nil {
+		t.Error("Expected other sessions to stay signed in")
+	}
+}
+
+func TestUserUsecase_Authenticate(t *testing.T) {
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return token
+	}
+	valid := jwt.RegisteredClaims{
+		Issuer:    tokenIssuer,
+		Subject:   "42",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	noSubject := valid
+	noSubject.Subject = ""
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedError bool
+	}{
+		{name: "valid token", token: sign(jwt.SigningMethodHS256, testSecret, valid)},
+		{name: "expired token", token: sign(jwt.SigningMethodHS256, testSecret, expired), expectedError: true},
+		{name: "other secret", token: sign(jwt.SigningMethodHS256, []byte("other"), valid), expectedError: true},
+		{name: "other algorithm", token: sign(jwt.SigningMethodHS512, testSecret, valid), expectedError: true},
+		{name: "unsigned token", token: sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid), expectedError: true},
+		{name: "missing subject", token: sign(jwt.SigningMethodHS256, testSecret, noSubject), expectedError: true},
+		{name: "garbage", token: "not a token", expectedError: true},
+	}
+
+	usecase := newTestUserUsecase(&mockUserRepository{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor, err := usecase.Authenticate(tt.token)
+
+			if tt.expectedError {
+				if !errors.Is(err, domain.ErrInvalidToken) {
+					t.Errorf("Expected invalid token, got %+v (%v)", actor, err)
+				}
+				return
+			}
+
+			if err != nil || actor.UserID != 42 {
+				t.Errorf("Expected user 42, got %+v (%v)", actor, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type webhookHandler struct {
+	webhookUsecase domain.WebhookUsecase
+}
+
+func NewWebhookHandler(webhookUsecase domain.WebhookUsecase) *webhookHandler {
+	return &webhookHandler{webhookUsecase: webhookUsecase}
+}
+
+func (h *webhookHandler) GetWebhookList(c *gin.Context) {
+	webhooks, err := h.webhookUsecase.GetWebhookList(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.GetWebhookList]: Error getting webhook list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.WebhookResponse]{
+		Status:  constant.Success,
+		Message: "List webhooks successfully",
+		Data:    webhooks,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *webhookHandler) GetWebhookByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.GetWebhookByID]: Error parsing webhook ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	webhook, err := h.webhookUsecase.GetWebhookByID(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.GetWebhookByID]: Error getting webhook")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WebhookResponse]{
+		Status:  constant.Success,
+		Message: "Webhook retrieved successfully",
+		Data:    webhook,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *webhookHandler) CreateWebhook(c *gin.Context) {
+	var req request.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.CreateWebhook]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	webhook, err := h.webhookUsecase.CreateWebhook(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.CreateWebhook]: Error creating webhook")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WebhookResponse]{
+		Status:  constant.Success,
+		Message: "Webhook created successfully",
+		Data:    webhook,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *webhookHandler) UpdateWebhook(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.UpdateWebhook]: Error parsing webhook ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.UpdateWebhook]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	webhook, err := h.webhookUsecase.UpdateWebhook(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.UpdateWebhook]: Error updating webhook")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WebhookResponse]{
+		Status:  constant.Success,
+		Message: "Webhook updated successfully",
+		Data:    webhook,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *webhookHandler) DeleteWebhook(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.DeleteWebhook]: Error parsing webhook ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.webhookUsecase.DeleteWebhook(middlewares.GetActor(c), id); err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.DeleteWebhook]: Error deleting webhook")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Webhook deleted successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *webhookHandler) GetWebhookDeliveryList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.GetWebhookDeliveryList]: Error parsing webhook ID")
+		log.Warn(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.WebhookDeliveryListRequest
+
+	// Set default values
+	req.Page = 1
+	req.Limit = 10
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&req); err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.GetWebhookDeliveryList]: Error binding query parameters")
+		log.Warn(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	deliveries, err := h.webhookUsecase.GetWebhookDeliveryList(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.GetWebhookDeliveryList]: Error getting delivery list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.PaginatedResponse[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.PaginatedResponse[*response.WebhookDeliveryResponse]{
+		Status:     constant.Success,
+		Message:    "List webhook deliveries successfully",
+		Data:       deliveries.Data,
+		Pagination: deliveries.Pagination,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *webhookHandler) RedeliverWebhookDelivery(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.RedeliverWebhookDelivery]: Error parsing webhook ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	deliveryIDStr := c.Param("deliveryId")
+	deliveryID, err := strconv.ParseUint(deliveryIDStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.RedeliverWebhookDelivery]: Error parsing delivery ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	delivery, err := h.webhookUsecase.RedeliverWebhookDelivery(middlewares.GetActor(c), id, deliveryID)
+	if err != nil {
+		err = errors.Wrap(err, "[WebhookHandler.RedeliverWebhookDelivery]: Error redelivering delivery")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WebhookDeliveryResponse]{
+		Status:  constant.Success,
+		Message: "Webhook delivery queued successfully",
+		Data:    delivery,
+	}
+	c.JSON(http.StatusOK, resp)
+}
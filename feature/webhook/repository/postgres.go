@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) domain.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) GetWebhookList(actor domain.Actor) ([]*models.Webhooks, error) {
+	var webhooks []*models.Webhooks
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Where("owner_id = ?", actor.UserID).Order("id").Find(&webhooks).Error; err != nil {
+		return nil, errors.Wrap(err, "[WebhookRepository.GetWebhookList]: Error getting webhooks")
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) GetWebhookByID(actor domain.Actor, id uint64) (*models.Webhooks, error) {
+	var webhook models.Webhooks
+	if err := database.WithWorkspace(r.db, actor.WorkspaceID).Where("id = ? AND owner_id = ?", id, actor.UserID).First(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrWebhookNotFound, "[WebhookRepository.GetWebhookByID]: Error getting webhook")
+		}
+		return nil, errors.Wrap(err, "[WebhookRepository.GetWebhookByID]: Error getting webhook")
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) CreateWebhook(webhook *models.Webhooks) error {
+	now := time.Now()
+	webhook.CreatedAt = &now
+	webhook.UpdatedAt = &now
+
+	if err := database.WithWorkspace(r.db, webhook.WorkspaceID).Create(webhook).Error; err != nil {
+		return errors.Wrap(err, "[WebhookRepository.CreateWebhook]: Error creating webhook")
+	}
+	return nil
+}
+
+func (r *webhookRepository) UpdateWebhook(webhook *models.Webhooks) error {
+	now := time.Now()
+	webhook.UpdatedAt = &now
+
+	result := database.WithWorkspace(r.db, webhook.WorkspaceID).Model(webhook).Where("owner_id = ?", webhook.OwnerID).
+		Select("url", "events", "active", "updated_at").
+		Updates(webhook)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[WebhookRepository.UpdateWebhook]: Error updating webhook")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrWebhookNotFound, "[WebhookRepository.UpdateWebhook]: Error updating webhook")
+	}
+	return nil
+}
+
+// DeleteWebhook leaves the deliveries of the webhook alone; they are not
+// sent any more.
+func (r *webhookRepository) DeleteWebhook(actor domain.Actor, id uint64) error {
+	result := database.WithWorkspace(r.db, actor.WorkspaceID).Where("id = ? AND owner_id = ?", id, actor.UserID).Delete(&models.Webhooks{})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[WebhookRepository.DeleteWebhook]: Error soft deleting webhook")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrWebhookNotFound, "[WebhookRepository.DeleteWebhook]: Error soft deleting webhook")
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetWebhookDeliveryList(webhook *models.Webhooks, filter *domain.WebhookDeliveryFilter) ([]*models.WebhookDeliveries, int64, error) {
+	var deliveries []*models.WebhookDeliveries
+	var total int64
+
+	query := database.WithWorkspace(r.db, webhook.WorkspaceID).Model(&models.WebhookDeliveries{}).Where("webhook_id = ?", webhook.ID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[WebhookRepository.GetWebhookDeliveryList]: Error counting deliveries")
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	if err := query.Order("id DESC").Offset(offset).Limit(filter.Limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[WebhookRepository.GetWebhookDeliveryList]: Error getting deliveries")
+	}
+	return deliveries, total, nil
+}
+
+func (r *webhookRepository) GetWebhookDeliveryByID(webhook *models.Webhooks, id uint64) (*models.WebhookDeliveries, error) {
+	var delivery models.WebhookDeliveries
+	if err := database.WithWorkspace(r.db, webhook.WorkspaceID).Where("id = ? AND webhook_id = ?", id, webhook.ID).First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrWebhookDeliveryNotFound, "[WebhookRepository.GetWebhookDeliveryByID]: Error getting delivery")
+		}
+		return nil, errors.Wrap(err, "[WebhookRepository.GetWebhookDeliveryByID]: Error getting delivery")
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) SaveWebhookDelivery(delivery *models.WebhookDeliveries) error {
+	if err := saveDelivery(database.WithWorkspace(r.db, delivery.WorkspaceID), delivery); err != nil {
+		return errors.Wrap(err, "[WebhookRepository.SaveWebhookDelivery]: Error saving delivery")
+	}
+	return nil
+}
+
+func saveDelivery(db *gorm.DB, delivery *models.WebhookDeliveries) error {
+	now := time.Now()
+	delivery.UpdatedAt = &now
+	return db.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "response_status", "last_error", "delivered_at", "updated_at").
+		Updates(delivery).Error
+}
+
+func (r *webhookRepository) ProcessOutbox(limit int, process func(change *models.OutboxEvents, webhooks []*models.Webhooks) ([]*models.WebhookDeliveries, error)) error {
+	// The dispatcher serves every workspace
+	err := database.AllWorkspaces(r.db).Transaction(func(tx *gorm.DB) error {
+		var changes []*models.OutboxEvents
+		if err := tx.Where("dispatched_at IS NULL").
+			Order("id").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&changes).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, change := range changes {
+			// The owner of the event and whoever it is shared with through
+			// the calendar it is in or leaves, or the one user the change
+			// was made for
+			query := tx.Where("workspace_id = ? AND active", change.WorkspaceID)
+			var calendarIDs []uint64
+			for _, id := range []*uint64{change.CalendarID, change.PreviousCalendarID} {
+				if id != nil {
+					calendarIDs = append(calendarIDs, *id)
+				}
+			}
+			switch {
+			case change.UserID != nil:
+				query = query.Where("owner_id = ?", *change.UserID)
+			case len(calendarIDs) == 0:
+				query = query.Where("owner_id = ?", change.OwnerID)
+			default:
+				shared := tx.Session(&gorm.Session{NewDB: true}).Model(&models.CalendarShares{}).
+					Select("user_id").
+					Where("calendar_id IN ? AND status = ?", calendarIDs, constant.CalendarShareAccepted)
+				query = query.Where("(owner_id = ? OR owner_id IN (?))", change.OwnerID, shared)
+			}
+			var webhooks []*models.Webhooks
+			if err := query.Order("id").Find(&webhooks).Error; err != nil {
+				return err
+			}
+
+			deliveries, err := process(change, webhooks)
+			if err != nil {
+				return err
+			}
+			for _, delivery := range deliveries {
+				delivery.WorkspaceID = change.WorkspaceID
+				delivery.OutboxEventID = change.ID
+				delivery.CreatedAt = &now
+				delivery.UpdatedAt = &now
+			}
+			if len(deliveries) > 0 {
+				if err := tx.Create(&deliveries).Error; err != nil {
+					return err
+				}
+			}
+
+			change.DispatchedAt = &now
+			if err := tx.Model(change).Select("dispatched_at").Updates(change).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "[WebhookRepository.ProcessOutbox]: Error processing outbox")
+	}
+	return nil
+}
+
+func (r *webhookRepository) ProcessDueDeliveries(now time.Time, limit int, process func(delivery *models.WebhookDeliveries) error) error {
+	// The dispatcher serves every workspace
+	db := database.AllWorkspaces(r.db)
+
+	// Claimed deliveries are due again once their lease runs out, so they
+	// are sent without holding locks and retried if this process dies
+	// sending them. Postgres keeps microseconds.
+	leasedUntil := now.Add(constant.WebhookLease).Truncate(time.Microsecond)
+	var deliveries []*models.WebhookDeliveries
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id AND webhooks.active AND webhooks.delete_at IS NULL").
+			Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", constant.WebhookDeliveryPending, now).
+			Order("webhook_deliveries.next_attempt_at").Order("webhook_deliveries.id").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "webhook_deliveries"}, Options: "SKIP LOCKED"}).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&models.WebhookDeliveries{}).Where("id IN ?", ids).Update("next_attempt_at", leasedUntil).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "[WebhookRepository.ProcessDueDeliveries]: Error claiming due deliveries")
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	webhookIDs := make([]uint64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		webhookIDs = append(webhookIDs, delivery.WebhookID)
+	}
+	var webhooks []*models.Webhooks
+	if err := db.Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+		return errors.Wrap(err, "[WebhookRepository.ProcessDueDeliveries]: Error getting webhooks")
+	}
+	for _, delivery := range deliveries {
+		for _, webhook := range webhooks {
+			if webhook.ID == delivery.WebhookID {
+				delivery.Webhook = webhook
+			}
+		}
+	}
+
+	for _, delivery := range deliveries {
+		if err := process(delivery); err != nil {
+			return errors.Wrap(err, "[WebhookRepository.ProcessDueDeliveries]: Error processing due deliveries")
+		}
+		// Unless it was redelivered meanwhile
+		if err := saveDelivery(db.Where("next_attempt_at = ?", leasedUntil), delivery); err != nil {
+			return errors.Wrap(err, "[WebhookRepository.ProcessDueDeliveries]: Error saving delivery")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// maxLastErrorLength bounds what the delivery log keeps of a failure.
+const maxLastErrorLength = 500
+
+type webhookUsecase struct {
+	webhookRepository domain.WebhookRepository
+	eventUsecase      domain.EventUsecase
+	workspaceUsecase  domain.WorkspaceUsecase
+	client            *http.Client
+	// lookupHost resolves the hosts of webhook URLs and allowAddress says
+	// whether deliveries may be posted to an address, when a webhook is
+	// saved and again whenever a delivery dials one.
+	lookupHost   func(ctx context.Context, host string) ([]netip.Addr, error)
+	allowAddress func(addr netip.Addr) bool
+}
+
+func NewWebhookUsecase(webhookRepository domain.WebhookRepository, eventUsecase domain.EventUsecase, workspaceUsecase domain.WorkspaceUsecase) domain.WebhookUsecase {
+	u := &webhookUsecase{
+		webhookRepository: webhookRepository,
+		eventUsecase:      eventUsecase,
+		workspaceUsecase:  workspaceUsecase,
+		lookupHost: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+		allowAddress: publicAddress,
+	}
+
+	// Checking the address dialed, rather than the one the URL resolved to
+	// when saved, keeps a host that later resolves elsewhere, or a redirect,
+	// from reaching internal services. Deliveries aren't proxied, so that
+	// is the receiver's.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: constant.WebhookTimeout, Control: u.checkDial}).DialContext
+	u.client = &http.Client{Timeout: constant.WebhookTimeout, Transport: transport}
+	return u
+}
+
+func (u *webhookUsecase) GetWebhookList(actor domain.Actor) ([]*response.WebhookResponse, error) {
+	webhooks, err := u.webhookRepository.GetWebhookList(actor)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.GetWebhookList]: Error getting webhooks")
+	}
+
+	webhookResponses := make([]*response.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, newWebhookResponse(webhook))
+	}
+	return webhookResponses, nil
+}
+
+func (u *webhookUsecase) GetWebhookByID(actor domain.Actor, id uint64) (*response.WebhookResponse, error) {
+	webhook, err := u.webhookRepository.GetWebhookByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.GetWebhookByID]: Error getting webhook")
+	}
+	return newWebhookResponse(webhook), nil
+}
+
+func (u *webhookUsecase) CreateWebhook(actor domain.Actor, req *request.WebhookRequest) (*response.WebhookResponse, error) {
+	if err := u.validateWebhookURL(req.URL); err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.CreateWebhook]: Invalid url")
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.CreateWebhook]: Error generating secret")
+	}
+
+	webhook := &models.Webhooks{
+		OwnerID:     actor.UserID,
+		WorkspaceID: actor.WorkspaceID,
+		Secret:      secret,
+	}
+	setWebhook(webhook, req)
+	if err := u.webhookRepository.CreateWebhook(webhook); err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.CreateWebhook]: Error creating webhook")
+	}
+
+	webhookResponse := newWebhookResponse(webhook)
+	webhookResponse.Secret = &secret
+	return webhookResponse, nil
+}
+
+func (u *webhookUsecase) UpdateWebhook(actor domain.Actor, id uint64, req *request.WebhookRequest) (*response.WebhookResponse, error) {
+	if err := u.validateWebhookURL(req.URL); err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.UpdateWebhook]: Invalid url")
+	}
+
+	webhook, err := u.webhookRepository.GetWebhookByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.UpdateWebhook]: Error getting webhook")
+	}
+
+	setWebhook(webhook, req)
+	if err := u.webhookRepository.UpdateWebhook(webhook); err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.UpdateWebhook]: Error updating webhook")
+	}
+	return newWebhookResponse(webhook), nil
+}
+
+func (u *webhookUsecase) DeleteWebhook(actor domain.Actor, id uint64) error {
+	if err := u.webhookRepository.DeleteWebhook(actor, id); err != nil {
+		return errors.Wrap(err, "[WebhookUsecase.DeleteWebhook]: Error deleting webhook")
+	}
+	return nil
+}
+
+func (u *webhookUsecase) GetWebhookDeliveryList(actor domain.Actor, id uint64, req *request.WebhookDeliveryListRequest) (*response.PaginatedResponse[*response.WebhookDeliveryResponse], error) {
+	webhook, err := u.webhookRepository.GetWebhookByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.GetWebhookDeliveryList]: Error getting webhook")
+	}
+
+	filter := &domain.WebhookDeliveryFilter{Page: req.Page, Limit: req.Limit, Status: req.Status}
+	deliveries, total, err := u.webhookRepository.GetWebhookDeliveryList(webhook, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.GetWebhookDeliveryList]: Error getting deliveries")
+	}
+
+	deliveryResponses := make([]*response.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, newWebhookDeliveryResponse(delivery))
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	return &response.PaginatedResponse[*response.WebhookDeliveryResponse]{
+		Data: deliveryResponses,
+		Pagination: response.Pagination{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+func (u *webhookUsecase) RedeliverWebhookDelivery(actor domain.Actor, id, deliveryID uint64) (*response.WebhookDeliveryResponse, error) {
+	webhook, err := u.webhookRepository.GetWebhookByID(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.RedeliverWebhookDelivery]: Error getting webhook")
+	}
+
+	delivery, err := u.webhookRepository.GetWebhookDeliveryByID(webhook, deliveryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.RedeliverWebhookDelivery]: Error getting delivery")
+	}
+
+	now := time.Now()
+	delivery.Status = constant.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	if err := u.webhookRepository.SaveWebhookDelivery(delivery); err != nil {
+		return nil, errors.Wrap(err, "[WebhookUsecase.RedeliverWebhookDelivery]: Error saving delivery")
+	}
+	return newWebhookDeliveryResponse(delivery), nil
+}
+
+// DispatchWebhooks only fails when the outbox or the deliveries can't be
+// worked through; failed deliveries are retried, then dead-lettered.
+func (u *webhookUsecase) DispatchWebhooks(ctx context.Context) (int, error) {
+	if err := u.webhookRepository.ProcessOutbox(constant.WebhookBatchSize, u.fanOut); err != nil {
+		return 0, errors.Wrap(err, "[WebhookUsecase.DispatchWebhooks]: Error fanning out changes")
+	}
+
+	delivered := 0
+	err := u.webhookRepository.ProcessDueDeliveries(time.Now(), constant.WebhookBatchSize, func(delivery *models.WebhookDeliveries) error {
+		if u.deliver(ctx, delivery) {
+			delivered++
+		}
+		return nil
+	})
+	if err != nil {
+		return delivered, errors.Wrap(err, "[WebhookUsecase.DispatchWebhooks]: Error sending deliveries")
+	}
+	return delivered, nil
+}
+
+// fanOut makes a delivery of change for each of webhooks subscribed to it
+// whose owner is still in its workspace, carrying the event as they see it.
+func (u *webhookUsecase) fanOut(change *models.OutboxEvents, webhooks []*models.Webhooks) ([]*models.WebhookDeliveries, error) {
+	now := time.Now()
+	var deliveries []*models.WebhookDeliveries
+	for _, webhook := range webhooks {
+		if !slices.Contains(webhook.Events, change.Type) {
+			continue
+		}
+
+		actor, err := u.workspaceUsecase.ResolveWorkspace(domain.Actor{UserID: webhook.OwnerID}, change.WorkspaceID)
+		if errors.Is(err, domain.ErrWorkspaceNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		payload := &response.WebhookPayload{
+			ID:          change.ID,
+			Type:        change.Type,
+			OccurredAt:  change.CreatedAt,
+			WorkspaceID: change.WorkspaceID,
+			EventID:     change.EventID,
+			Version:     change.Version,
+		}
+		if change.Type != constant.WebhookEventDeleted {
+			payload.Event, err = u.eventUsecase.GetEventByID(actor, change.EventID)
+			if err != nil && !errors.Is(err, domain.ErrEventNotFound) {
+				return nil, err
+			}
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, &models.WebhookDeliveries{
+			WebhookID:     webhook.ID,
+			Type:          change.Type,
+			Payload:       string(body),
+			Status:        constant.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		})
+	}
+	return deliveries, nil
+}
+
+// deliver posts delivery to its webhook and records the outcome: delivered,
+// retried later or, out of attempts, dead. It reports whether it was
+// delivered.
+func (u *webhookUsecase) deliver(ctx context.Context, delivery *models.WebhookDeliveries) bool {
+	status, err := u.post(ctx, delivery)
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = constant.WebhookDeliveryDelivered
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return true
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > maxLastErrorLength {
+		delivery.LastError = delivery.LastError[:maxLastErrorLength]
+	}
+	if delivery.Attempts >= constant.MaxWebhookAttempts {
+		delivery.Status = constant.WebhookDeliveryDead
+		delivery.NextAttemptAt = nil
+		return false
+	}
+	next := now.Add(retryDelay(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+	return false
+}
+
+// post sends delivery, signed, and returns the status it was answered
+// with, 0 without an answer; anything but a 2xx status is an error.
+func (u *webhookUsecase) post(ctx context.Context, delivery *models.WebhookDeliveries) (int, error) {
+	if delivery.Webhook == nil {
+		return 0, errors.New("webhook not found")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constant.WebhookEventHeader, delivery.Type)
+	req.Header.Set(constant.WebhookDeliveryHeader, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(constant.WebhookSignatureHeader, signWebhookPayload(delivery.Webhook.Secret, time.Now(), []byte(delivery.Payload)))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drained so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// signWebhookPayload is the signature header value of body sent at
+// timestamp, keyed with secret, in the format constant.WebhookSignatureHeader
+// describes. Signing the timestamp lets receivers turn away replays.
+func signWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// retryDelay is how long to wait after the attempts-th failed attempt:
+// doubling from constant.WebhookRetryDelay, up to MaxWebhookRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := constant.WebhookRetryDelay
+	for i := 1; i < attempts && delay < constant.MaxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, constant.MaxWebhookRetryDelay)
+}
+
+// validateWebhookURL checks that deliveries can be posted to rawURL: an
+// http or https URL whose host resolves only to addresses allowAddress
+// allows.
+func (u *webhookUsecase) validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.NewValidationError("url must be an http or https URL", response.FieldError{
+			Field: "url", Rule: "url", Message: "url must be an http or https URL",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constant.WebhookTimeout)
+	defer cancel()
+	addrs, err := u.lookupHost(ctx, parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return domain.NewValidationError("url host could not be resolved", response.FieldError{
+			Field: "url", Rule: "resolvable", Message: "url must have a host that resolves",
+		})
+	}
+	for _, addr := range addrs {
+		if !u.allowAddress(addr.Unmap()) {
+			return domain.NewValidationError("url must not point to a private or local address", response.FieldError{
+				Field: "url", Rule: "public", Message: "url must have a host with only public addresses",
+			})
+		}
+	}
+	return nil
+}
+
+// checkDial refuses to connect deliveries to addresses allowAddress does
+// not allow; it is the net.Dialer Control of the client.
+func (u *webhookUsecase) checkDial(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !u.allowAddress(addrPort.Addr().Unmap()) {
+		return errors.Errorf("address %s is not allowed for webhooks", addrPort.Addr())
+	}
+	return nil
+}
+
+// publicAddress reports whether addr is neither loopback, private,
+// link-local, multicast nor unspecified.
+func publicAddress(addr netip.Addr) bool {
+	return addr.IsValid() && !addr.IsLoopback() && !addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() && !addr.IsLinkLocalMulticast() && !addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() && !addr.IsUnspecified()
+}
+
+// setWebhook copies req onto webhook; webhooks are active unless req says
+// otherwise.
+func setWebhook(webhook *models.Webhooks, req *request.WebhookRequest) {
+	events := slices.Clone(req.Events)
+	slices.Sort(events)
+	webhook.URL = req.URL
+	webhook.Events = slices.Compact(events)
+	webhook.Active = req.Active == nil || *req.Active
+}
+
+// newWebhookSecret returns a random secret to sign deliveries with.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func newWebhookResponse(webhook *models.Webhooks) *response.WebhookResponse {
+	return &response.WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(delivery *models.WebhookDeliveries) *response.WebhookDeliveryResponse {
+	return &response.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Type:           delivery.Type,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+		Payload:        json.RawMessage(delivery.Payload),
+	}
+}
@@ -0,0 +1,632 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// Mock repository for testing, handing every change the active webhooks of
+// its workspace
+type mockWebhookRepository struct {
+	webhooks   []*models.Webhooks
+	outbox     []*models.OutboxEvents
+	deliveries []*models.WebhookDeliveries
+}
+
+func (m *mockWebhookRepository) GetWebhookList(actor domain.Actor) ([]*models.Webhooks, error) {
+	var webhooks []*models.Webhooks
+	for _, webhook := range m.webhooks {
+		if webhook.OwnerID == actor.UserID && webhook.WorkspaceID == actor.WorkspaceID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *mockWebhookRepository) GetWebhookByID(actor domain.Actor, id uint64) (*models.Webhooks, error) {
+	for _, webhook := range m.webhooks {
+		if webhook.ID == id && webhook.OwnerID == actor.UserID && webhook.WorkspaceID == actor.WorkspaceID {
+			return webhook, nil
+		}
+	}
+	return nil, domain.ErrWebhookNotFound
+}
+
+func (m *mockWebhookRepository) CreateWebhook(webhook *models.Webhooks) error {
+	webhook.ID = uint64(len(m.webhooks) + 1)
+	now := time.Now()
+	webhook.CreatedAt = &now
+	webhook.UpdatedAt = &now
+	m.webhooks = append(m.webhooks, webhook)
+	return nil
+}
+
+func (m *mockWebhookRepository) UpdateWebhook(webhook *models.Webhooks) error {
+	now := time.Now()
+	webhook.UpdatedAt = &now
+	return nil
+}
+
+func (m *mockWebhookRepository) DeleteWebhook(actor domain.Actor, id uint64) error {
+	for i, webhook := range m.webhooks {
+		if webhook.ID == id && webhook.OwnerID == actor.UserID && webhook.WorkspaceID == actor.WorkspaceID {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrWebhookNotFound
+}
+
+func (m *mockWebhookRepository) GetWebhookDeliveryList(webhook *models.Webhooks, filter *domain.WebhookDeliveryFilter) ([]*models.WebhookDeliveries, int64, error) {
+	var deliveries []*models.WebhookDeliveries
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhook.ID && (filter.Status == "" || delivery.Status == filter.Status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	slices.Reverse(deliveries)
+	return deliveries, int64(len(deliveries)), nil
+}
+
+func (m *mockWebhookRepository) GetWebhookDeliveryByID(webhook *models.Webhooks, id uint64) (*models.WebhookDeliveries, error) {
+	for _, delivery := range m.deliveries {
+		if delivery.ID == id && delivery.WebhookID == webhook.ID {
+			return delivery, nil
+		}
+	}
+	return nil, domain.ErrWebhookDeliveryNotFound
+}
+
+func (m *mockWebhookRepository) SaveWebhookDelivery(delivery *models.WebhookDeliveries) error {
+	now := time.Now()
+	delivery.UpdatedAt = &now
+	return nil
+}
+
+func (m *mockWebhookRepository) ProcessOutbox(limit int, process func(change *models.OutboxEvents, webhooks []*models.Webhooks) ([]*models.WebhookDeliveries, error)) error {
+	for _, change := range m.outbox {
+		if change.DispatchedAt != nil {
+			continue
+		}
+
+		var webhooks []*models.Webhooks
+		for _, webhook := range m.webhooks {
+			if webhook.Active && webhook.WorkspaceID == change.WorkspaceID {
+				webhooks = append(webhooks, webhook)
+			}
+		}
+		deliveries, err := process(change, webhooks)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, delivery := range deliveries {
+			delivery.ID = uint64(len(m.deliveries) + 1)
+			delivery.WorkspaceID = change.WorkspaceID
+			delivery.OutboxEventID = change.ID
+			delivery.CreatedAt = &now
+			m.deliveries = append(m.deliveries, delivery)
+		}
+		change.DispatchedAt = &now
+	}
+	return nil
+}
+
+func (m *mockWebhookRepository) ProcessDueDeliveries(now time.Time, limit int, process func(delivery *models.WebhookDeliveries) error) error {
+	for _, delivery := range m.deliveries {
+		if delivery.Status != constant.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		index := slices.IndexFunc(m.webhooks, func(webhook *models.Webhooks) bool { return webhook.ID == delivery.WebhookID })
+		if index < 0 || !m.webhooks[index].Active {
+			continue
+		}
+
+		delivery.Webhook = m.webhooks[index]
+		if err := process(delivery); err != nil {
+			return err
+		}
+		if err := m.SaveWebhookDelivery(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Mock event usecase, only getting events is used by webhooks; the users
+// in viewers see the event of each id
+type mockEventUsecase struct {
+	domain.EventUsecase
+	events  map[uint64]*response.EventResponse
+	viewers map[uint64][]uint64
+}
+
+func (m *mockEventUsecase) GetEventByID(actor domain.Actor, id uint64) (*response.EventResponse, error) {
+	if event, ok := m.events[id]; ok && slices.Contains(m.viewers[id], actor.UserID) {
+		return event, nil
+	}
+	return nil, domain.ErrEventNotFound
+}
+
+// Mock workspace usecase, resolving members of workspaces to the users in
+// members
+type mockWorkspaceUsecase struct {
+	domain.WorkspaceUsecase
+	members map[uint64][]uint64
+}
+
+func (m *mockWorkspaceUsecase) ResolveWorkspace(actor domain.Actor, id uint64) (domain.Actor, error) {
+	if id != constant.PersonalWorkspaceID && !slices.Contains(m.members[id], actor.UserID) {
+		return domain.Actor{}, domain.ErrWorkspaceNotFound
+	}
+	actor.WorkspaceID = id
+	return actor, nil
+}
+
+// testActor owns the webhooks in these tests
+var testActor = domain.Actor{UserID: 1}
+
+// receivedDelivery is a delivery as the test receiver got it
+type receivedDelivery struct {
+	event      string
+	deliveryID string
+	signature  string
+	payload    *response.WebhookPayload
+}
+
+// testHosts are what hosts resolve to in tests, rather than through DNS
+var testHosts = map[string][]netip.Addr{
+	"bot.example.com":      {netip.MustParseAddr("93.184.215.14")},
+	"internal.example.com": {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("10.0.0.7")},
+	"localhost":            {netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("::1")},
+}
+
+// newTestWebhookUsecase is NewWebhookUsecase resolving hosts from
+// testHosts, and addresses to themselves
+func newTestWebhookUsecase(webhookRepository domain.WebhookRepository, eventUsecase domain.EventUsecase) *webhookUsecase {
+	usecase := NewWebhookUsecase(webhookRepository, eventUsecase, &mockWorkspaceUsecase{}).(*webhookUsecase)
+	usecase.lookupHost = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		if addr, err := netip.ParseAddr(host); err == nil {
+			return []netip.Addr{addr}, nil
+		}
+		if addrs, found := testHosts[host]; found {
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	return usecase
+}
+
+// allowReceiver lets deliveries reach test receivers, which listen on
+// loopback
+func allowReceiver(addr netip.Addr) bool {
+	return addr.IsLoopback() || publicAddress(addr)
+}
+
+// testReceiver is a webhook endpoint answering with status, recording the
+// deliveries it gets
+type testReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	received []*receivedDelivery
+	bodies   [][]byte
+}
+
+func newTestReceiver(t *testing.T, status int) *testReceiver {
+	receiver := &testReceiver{status: status}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Expected a readable body, got: %v", err)
+		}
+		var payload response.WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Expected a JSON payload, got %q: %v", body, err)
+		}
+
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.received = append(receiver.received, &receivedDelivery{
+			event:      r.Header.Get(constant.WebhookEventHeader),
+			deliveryID: r.Header.Get(constant.WebhookDeliveryHeader),
+			signature:  r.Header.Get(constant.WebhookSignatureHeader),
+			payload:    &payload,
+		})
+		receiver.bodies = append(receiver.bodies, body)
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+// verifySignature checks signature the way a receiver holding secret would
+func verifySignature(secret, signature string, body []byte) bool {
+	var timestamp, sum string
+	for _, part := range strings.Split(signature, ",") {
+		if value, ok := strings.CutPrefix(part, "t="); ok {
+			timestamp = value
+		} else if value, ok := strings.CutPrefix(part, "v1="); ok {
+			sum = value
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(unix, 0)) > time.Minute {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(sum), []byte(expected))
+}
+
+func newTestChange(id uint64, workspaceID uint64, changeType string, eventID uint64) *models.OutboxEvents {
+	now := time.Now()
+	return &models.OutboxEvents{
+		ID:          id,
+		WorkspaceID: workspaceID,
+		Type:        changeType,
+		EventID:     eventID,
+		OwnerID:     testActor.UserID,
+		Version:     1,
+		CreatedAt:   &now,
+	}
+}
+
+func TestWebhookUsecase_CreateWebhook(t *testing.T) {
+	mockRepo := &mockWebhookRepository{}
+	usecase := newTestWebhookUsecase(mockRepo, &mockEventUsecase{})
+
+	created, err := usecase.CreateWebhook(testActor, &request.WebhookRequest{
+		URL:    "https://bot.example.com/hooks",
+		Events: []string{constant.WebhookEventUpdated, constant.WebhookEventCreated, constant.WebhookEventUpdated},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if created.Secret == nil || !strings.HasPrefix(*created.Secret, "whsec_") || *created.Secret != mockRepo.webhooks[0].Secret {
+		t.Fatalf("Expected the stored signing secret returned, got %v", created.Secret)
+	}
+	if !slices.Equal(created.Events, []string{constant.WebhookEventCreated, constant.WebhookEventUpdated}) || !created.Active {
+		t.Errorf("Expected an active webhook with the events sorted and once each, got %+v", created)
+	}
+
+	webhooks, err := usecase.GetWebhookList(testActor)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].Secret != nil {
+		t.Errorf("Expected one webhook listed without its secret, got %+v", webhooks)
+	}
+
+	if _, err := usecase.GetWebhookByID(domain.Actor{UserID: 2}, created.ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("Expected another user's webhook to be not found, got: %v", err)
+	}
+}
+
+func TestWebhookUsecase_UpdateWebhook(t *testing.T) {
+	mockRepo := &mockWebhookRepository{}
+	usecase := newTestWebhookUsecase(mockRepo, &mockEventUsecase{})
+
+	created, err := usecase.CreateWebhook(testActor, &request.WebhookRequest{URL: "https://bot.example.com/hooks", Events: []string{constant.WebhookEventCreated}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	active := false
+	updated, err := usecase.UpdateWebhook(testActor, created.ID, &request.WebhookRequest{
+		URL:    "https://bot.example.com/v2/hooks",
+		Events: []string{constant.WebhookEventDeleted},
+		Active: &active,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.URL != "https://bot.example.com/v2/hooks" || !slices.Equal(updated.Events, []string{constant.WebhookEventDeleted}) || updated.Active {
+		t.Errorf("Expected the webhook updated and paused, got %+v", updated)
+	}
+	if updated.Secret != nil || mockRepo.webhooks[0].Secret != *created.Secret {
+		t.Error("Expected the secret kept and not returned")
+	}
+}
+
+func TestWebhookUsecase_ValidateURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		expectedError bool
+	}{
+		{name: "https", url: "https://bot.example.com/hooks"},
+		{name: "http", url: "http://bot.example.com:8080/hooks"},
+		{name: "public address", url: "http://93.184.215.14/hooks"},
+		{name: "other scheme", url: "ftp://bot.example.com/hooks", expectedError: true},
+		{name: "no host", url: "https:///hooks", expectedError: true},
+		{name: "relative", url: "/hooks", expectedError: true},
+		{name: "unresolvable", url: "https://nowhere.example.com/hooks", expectedError: true},
+		{name: "localhost", url: "http://localhost:8080/hooks", expectedError: true},
+		{name: "loopback", url: "http://127.0.0.1:8080/hooks", expectedError: true},
+		{name: "loopback v6", url: "http://[::1]/hooks", expectedError: true},
+		{name: "mapped loopback", url: "http://[::ffff:127.0.0.1]/hooks", expectedError: true},
+		{name: "private", url: "http://10.1.2.3/hooks", expectedError: true},
+		{name: "private by name", url: "https://internal.example.com/hooks", expectedError: true},
+		{name: "link-local", url: "http://169.254.169.254/latest/meta-data", expectedError: true},
+		{name: "unspecified", url: "http://0.0.0.0:8080/hooks", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := newTestWebhookUsecase(&mockWebhookRepository{}, &mockEventUsecase{})
+			_, err := usecase.CreateWebhook(testActor, &request.WebhookRequest{URL: tt.url, Events: []string{constant.WebhookEventCreated}})
+
+			if tt.expectedError {
+				var domainErr *domain.Error
+				if !errors.As(err, &domainErr) || domainErr.Code != constant.CodeValidationFailed || domainErr.Fields[0].Field != "url" {
+					t.Errorf("Expected a validation error on url, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookUsecase_DispatchWebhooks(t *testing.T) {
+	receiver := newTestReceiver(t, http.StatusOK)
+	mockRepo := &mockWebhookRepository{}
+	events := &mockEventUsecase{
+		events:  map[uint64]*response.EventResponse{5: {ID: 5, Title: "Standup"}},
+		viewers: map[uint64][]uint64{5: {testActor.UserID}},
+	}
+	usecase := newTestWebhookUsecase(mockRepo, events)
+	usecase.allowAddress = allowReceiver
+
+	created, err := usecase.CreateWebhook(testActor, &request.WebhookRequest{
+		URL:    receiver.URL,
+		Events: []string{constant.WebhookEventCreated, constant.WebhookEventDeleted},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Not subscribed to creations
+	if _, err := usecase.CreateWebhook(testActor, &request.WebhookRequest{URL: receiver.URL, Events: []string{constant.WebhookEventCompleted}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Its owner left the workspace
+	mockRepo.webhooks = append(mockRepo.webhooks, &models.Webhooks{
+		ID: 3, WorkspaceID: 3, OwnerID: 2, URL: receiver.URL, Events: []string{constant.WebhookEventCreated}, Active: true,
+	})
+
+	mockRepo.outbox = []*models.OutboxEvents{
+		newTestChange(1, constant.PersonalWorkspaceID, constant.WebhookEventCreated, 5),
+		newTestChange(2, 3, constant.WebhookEventCreated, 6),
+		newTestChange(3, constant.PersonalWorkspaceID, constant.WebhookEventDeleted, 5),
+	}
+
+	delivered, err := usecase.DispatchWebhooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if delivered != 2 || len(receiver.received) != 2 {
+		t.Fatalf("Expected the creation and the deletion delivered, got %d delivered, %d received", delivered, len(receiver.received))
+	}
+
+	for i, received := range receiver.received {
+		if !verifySignature(*created.Secret, received.signature, receiver.bodies[i]) {
+			t.Errorf("Expected delivery %d signed with the webhook secret, got %q", i, received.signature)
+		}
+		if received.event != received.payload.Type || received.deliveryID != strconv.FormatUint(mockRepo.deliveries[i].ID, 10) {
+			t.Errorf("Expected the event and delivery headers, got %q and %q", received.event, received.deliveryID)
+		}
+		if verifySignature("whsec_other", received.signature, receiver.bodies[i]) {
+			t.Error("Expected the signature not to verify with another secret")
+		}
+	}
+
+	creation, deletion := receiver.received[0].payload, receiver.received[1].payload
+	if creation.ID != 1 || creation.Type != constant.WebhookEventCreated || creation.EventID != 5 || creation.Event == nil || creation.Event.Title != "Standup" {
+		t.Errorf("Expected the creation with the event, got %+v", creation)
+	}
+	if deletion.ID != 3 || deletion.Type != constant.WebhookEventDeleted || deletion.EventID != 5 || deletion.Event != nil {
+		t.Errorf("Expected the deletion without an event, got %+v", deletion)
+	}
+
+	for _, delivery := range mockRepo.deliveries {
+		if delivery.Status != constant.WebhookDeliveryDelivered || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusOK || delivery.DeliveredAt == nil {
+			t.Errorf("Expected the delivery delivered on its first attempt, got %+v", delivery)
+		}
+	}
+	for _, change := range mockRepo.outbox {
+		if change.DispatchedAt == nil {
+			t.Errorf("Expected change %d dispatched", change.ID)
+		}
+	}
+
+	// Nothing is sent twice
+	if delivered, err := usecase.DispatchWebhooks(context.Background()); err != nil || delivered != 0 || len(receiver.received) != 2 {
+		t.Errorf("Expected nothing left to deliver, got %d delivered, %d received: %v", delivered, len(receiver.received), err)
+	}
+}
+
+func TestWebhookUsecase_DispatchWebhooks_Hidden(t *testing.T) {
+	receiver := newTestReceiver(t, http.StatusOK)
+	mockRepo := &mockWebhookRepository{}
+	events := &mockEventUsecase{
+		events:  map[uint64]*response.EventResponse{5: {ID: 5, Title: "Standup"}},
+		viewers: map[uint64][]uint64{5: {2}},
+	}
+	usecase := newTestWebhookUsecase(mockRepo, events)
+	usecase.allowAddress = allowReceiver
+
+	if _, err := usecase.CreateWebhook(testActor, &request.WebhookRequest{URL: receiver.URL, Events: []string{constant.WebhookEventUpdated}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	mockRepo.outbox = []*models.OutboxEvents{newTestChange(1, constant.PersonalWorkspaceID, constant.WebhookEventUpdated, 5)}
+
+	if _, err := usecase.DispatchWebhooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(receiver.received) != 1 || receiver.received[0].payload.Event != nil {
+		t.Errorf("Expected the change delivered without the event its owner can't see, got %+v", receiver.received)
+	}
+}
+
+func TestWebhookUsecase_DispatchWebhooks_Retries(t *testing.T) {
+	receiver := newTestReceiver(t, http.StatusInternalServerError)
+	mockRepo := &mockWebhookRepository{}
+	usecase := newTestWebhookUsecase(mockRepo, &mockEventUsecase{})
+	usecase.allowAddress = allowReceiver
+
+	created, err := usecase.CreateWebhook(testActor, &request.WebhookRequest{URL: receiver.URL, Events: []string{constant.WebhookEventDeleted}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	mockRepo.outbox = []*models.OutboxEvents{newTestChange(1, constant.PersonalWorkspaceID, constant.WebhookEventDeleted, 5)}
+
+	start := time.Now()
+	if delivered, err := usecase.DispatchWebhooks(context.Background()); err != nil || delivered != 0 {
+		t.Fatalf("Expected nothing delivered without an error, got %d: %v", delivered, err)
+	}
+	delivery := mockRepo.deliveries[0]
+	if delivery.Status != constant.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusInternalServerError {
+		t.Fatalf("Expected the failed delivery pending after one attempt, got %+v", delivery)
+	}
+	if !strings.Contains(delivery.LastError, "500") {
+		t.Errorf("Expected the status in the last error, got %q", delivery.LastError)
+	}
+	if retry := delivery.NextAttemptAt.Sub(start); retry < constant.WebhookRetryDelay || retry > constant.WebhookRetryDelay+time.Minute {
+		t.Errorf("Expected a retry in %v, got one in %v", constant.WebhookRetryDelay, retry)
+	}
+
+	// Not due yet
+	if _, err := usecase.DispatchWebhooks(context.Background()); err != nil || len(receiver.received) != 1 {
+		t.Fatalf("Expected no attempt before the retry is due, got %d: %v", len(receiver.received), err)
+	}
+
+	for attempt := 2; attempt <= constant.MaxWebhookAttempts; attempt++ {
+		past := time.Now().Add(-time.Second)
+		delivery.NextAttemptAt = &past
+		if _, err := usecase.DispatchWebhooks(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if delivery.Status != constant.WebhookDeliveryDead || delivery.NextAttemptAt != nil || len(receiver.received) != constant.MaxWebhookAttempts {
+		t.Fatalf("Expected the delivery dead after %d attempts, got %+v after %d", constant.MaxWebhookAttempts, delivery, len(receiver.received))
+	}
+
+	// Redelivering starts over
+	receiver.status = http.StatusNoContent
+	redelivered, err := usecase.RedeliverWebhookDelivery(testActor, created.ID, delivery.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if redelivered.Status != constant.WebhookDeliveryPending || redelivered.Attempts != 0 {
+		t.Errorf("Expected the delivery pending with no attempts, got %+v", redelivered)
+	}
+	if delivered, err := usecase.DispatchWebhooks(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("Expected the redelivery delivered, got %d: %v", delivered, err)
+	}
+	if delivery.Status != constant.WebhookDeliveryDelivered || delivery.LastError != "" || delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("Expected the delivery delivered, got %+v", delivery)
+	}
+	if received := receiver.bodies[len(receiver.bodies)-1]; string(received) != string(receiver.bodies[0]) {
+		t.Errorf("Expected every attempt to carry the same payload, got %s and %s", receiver.bodies[0], received)
+	}
+}
+
+func TestWebhookUsecase_DispatchWebhooks_InternalAddress(t *testing.T) {
+	receiver := newTestReceiver(t, http.StatusOK)
+	mockRepo := &mockWebhookRepository{
+		// Saved while its host resolved to a public address, now loopback
+		webhooks: []*models.Webhooks{{
+			ID: 1, WorkspaceID: constant.PersonalWorkspaceID, OwnerID: testActor.UserID, URL: receiver.URL,
+			Events: []string{constant.WebhookEventDeleted}, Active: true,
+		}},
+		outbox: []*models.OutboxEvents{newTestChange(1, constant.PersonalWorkspaceID, constant.WebhookEventDeleted, 5)},
+	}
+	usecase := newTestWebhookUsecase(mockRepo, &mockEventUsecase{})
+
+	if delivered, err := usecase.DispatchWebhooks(context.Background()); err != nil || delivered != 0 {
+		t.Fatalf("Expected nothing delivered without an error, got %d: %v", delivered, err)
+	}
+	if len(receiver.received) != 0 {
+		t.Fatalf("Expected the receiver not reached, got %d deliveries", len(receiver.received))
+	}
+	if delivery := mockRepo.deliveries[0]; delivery.ResponseStatus != 0 || !strings.Contains(delivery.LastError, "not allowed") {
+		t.Errorf("Expected the delivery refused before connecting, got %+v", delivery)
+	}
+}
+
+func TestWebhookUsecase_GetWebhookDeliveryList(t *testing.T) {
+	mockRepo := &mockWebhookRepository{
+		webhooks: []*models.Webhooks{{ID: 1, OwnerID: testActor.UserID, Active: true}},
+		deliveries: []*models.WebhookDeliveries{
+			{ID: 1, WebhookID: 1, Status: constant.WebhookDeliveryDelivered, Payload: `{"id":1}`},
+			{ID: 2, WebhookID: 1, Status: constant.WebhookDeliveryDead, Payload: `{"id":2}`},
+			{ID: 3, WebhookID: 1, Status: constant.WebhookDeliveryDelivered, Payload: `{"id":3}`},
+		},
+	}
+	usecase := newTestWebhookUsecase(mockRepo, &mockEventUsecase{})
+
+	req := &request.WebhookDeliveryListRequest{PaginationRequest: request.PaginationRequest{Page: 1, Limit: 10}, Status: constant.WebhookDeliveryDelivered}
+	result, err := usecase.GetWebhookDeliveryList(testActor, 1, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Data) != 2 || result.Data[0].ID != 3 || result.Pagination.Total != 2 || result.Pagination.TotalPages != 1 {
+		t.Errorf("Expected the delivered deliveries newest first, got %+v", result)
+	}
+	if string(result.Data[0].Payload) != `{"id":3}` {
+		t.Errorf("Expected the payload as sent, got %s", result.Data[0].Payload)
+	}
+
+	if _, err := usecase.GetWebhookDeliveryList(domain.Actor{UserID: 2}, 1, req); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("Expected another user's webhook to be not found, got: %v", err)
+	}
+	if _, err := usecase.RedeliverWebhookDelivery(testActor, 1, 9); !errors.Is(err, domain.ErrWebhookDeliveryNotFound) {
+		t.Errorf("Expected delivery not found, got: %v", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: constant.WebhookRetryDelay},
+		{attempts: 2, expected: 2 * constant.WebhookRetryDelay},
+		{attempts: 4, expected: 8 * constant.WebhookRetryDelay},
+		{attempts: 20, expected: constant.MaxWebhookRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if delay := retryDelay(tt.attempts); delay != tt.expected {
+			t.Errorf("Expected a delay of %v after %d attempts, got %v", tt.expected, tt.attempts, delay)
+		}
+	}
+}
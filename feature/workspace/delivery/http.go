@@ -0,0 +1,372 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type workspaceHandler struct {
+	workspaceUsecase domain.WorkspaceUsecase
+}
+
+func NewWorkspaceHandler(workspaceUsecase domain.WorkspaceUsecase) *workspaceHandler {
+	return &workspaceHandler{workspaceUsecase: workspaceUsecase}
+}
+
+func (h *workspaceHandler) GetWorkspaceList(c *gin.Context) {
+	workspaces, err := h.workspaceUsecase.GetWorkspaceList(middlewares.GetActor(c))
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.GetWorkspaceList]: Error getting workspace list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.WorkspaceResponse]{
+		Status:  constant.Success,
+		Message: "List workspaces successfully",
+		Data:    workspaces,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *workspaceHandler) GetWorkspaceByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.GetWorkspaceByID]: Error parsing workspace ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	workspace, err := h.workspaceUsecase.GetWorkspaceByID(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.GetWorkspaceByID]: Error getting workspace")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WorkspaceResponse]{
+		Status:  constant.Success,
+		Message: "Get workspace successfully",
+		Data:    workspace,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *workspaceHandler) CreateWorkspace(c *gin.Context) {
+	var req request.WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.CreateWorkspace]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	workspace, err := h.workspaceUsecase.CreateWorkspace(middlewares.GetActor(c), &req)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.CreateWorkspace]: Error creating workspace")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WorkspaceResponse]{
+		Status:  constant.Success,
+		Message: "Workspace created successfully",
+		Data:    workspace,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *workspaceHandler) UpdateWorkspace(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.UpdateWorkspace]: Error parsing workspace ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.UpdateWorkspace]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	workspace, err := h.workspaceUsecase.UpdateWorkspace(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.UpdateWorkspace]: Error updating workspace")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WorkspaceResponse]{
+		Status:  constant.Success,
+		Message: "Workspace updated successfully",
+		Data:    workspace,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *workspaceHandler) DeleteWorkspace(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.DeleteWorkspace]: Error parsing workspace ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.workspaceUsecase.DeleteWorkspace(middlewares.GetActor(c), id); err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.DeleteWorkspace]: Error deleting workspace")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Workspace deleted successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *workspaceHandler) GetWorkspaceMemberList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.GetWorkspaceMemberList]: Error parsing workspace ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	members, err := h.workspaceUsecase.GetWorkspaceMemberList(middlewares.GetActor(c), id)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.GetWorkspaceMemberList]: Error getting workspace member list")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[[]*response.WorkspaceMemberResponse]{
+		Status:  constant.Success,
+		Message: "List workspace members successfully",
+		Data:    members,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *workspaceHandler) AddWorkspaceMember(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.AddWorkspaceMember]: Error parsing workspace ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req request.WorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.AddWorkspaceMember]: Error binding request body")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	member, err := h.workspaceUsecase.AddWorkspaceMember(middlewares.GetActor(c), id, &req)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.AddWorkspaceMember]: Error adding workspace member")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[*response.WorkspaceMemberResponse]{
+		Status:  constant.Success,
+		Message: "Workspace member added successfully",
+		Data:    member,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *workspaceHandler) RemoveWorkspaceMember(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.RemoveWorkspaceMember]: Error parsing workspace ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.RemoveWorkspaceMember]: Error parsing user ID")
+		log.Warn(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    constant.CodeBadRequest,
+			Data:    nil,
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := h.workspaceUsecase.RemoveWorkspaceMember(middlewares.GetActor(c), id, userID); err != nil {
+		err = errors.Wrap(err, "[WorkspaceHandler.RemoveWorkspaceMember]: Error removing workspace member")
+		log.Error(err)
+		status, code := utils.HTTPError(err)
+		resp := response.Response[interface{}]{
+			Status:  constant.Failed,
+			Message: utils.StandardError(err),
+			Code:    code,
+			Errors:  utils.FieldErrors(err),
+			Data:    nil,
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	resp := response.Response[interface{}]{
+		Status:  constant.Success,
+		Message: "Workspace member removed successfully",
+		Data:    nil,
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"gorm.io/gorm"
+)
+
+type workspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) domain.WorkspaceRepository {
+	return &workspaceRepository{db: db}
+}
+
+// Members of deleted workspaces are deleted with them, so joining on
+// workspaces is not needed to leave those out.
+
+func (r *workspaceRepository) GetUserWorkspaceList(userID uint64) ([]*models.WorkspaceMembers, error) {
+	var members []*models.WorkspaceMembers
+	if err := r.db.Preload("Workspace").Preload("User").
+		Where("user_id = ?", userID).Order("workspace_id").Find(&members).Error; err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceRepository.GetUserWorkspaceList]: Error getting workspaces")
+	}
+	return members, nil
+}
+
+func (r *workspaceRepository) GetWorkspaceMember(workspaceID, userID uint64) (*models.WorkspaceMembers, error) {
+	var member models.WorkspaceMembers
+	if err := r.db.Preload("Workspace").Preload("User").
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(domain.ErrWorkspaceMemberNotFound, "[WorkspaceRepository.GetWorkspaceMember]: Error getting workspace member")
+		}
+		return nil, errors.Wrap(err, "[WorkspaceRepository.GetWorkspaceMember]: Error getting workspace member")
+	}
+	return &member, nil
+}
+
+func (r *workspaceRepository) GetWorkspaceMemberList(workspaceID uint64) ([]*models.WorkspaceMembers, error) {
+	var members []*models.WorkspaceMembers
+	if err := r.db.Preload("Workspace").Preload("User").
+		Where("workspace_id = ?", workspaceID).Order("id").Find(&members).Error; err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceRepository.GetWorkspaceMemberList]: Error getting workspace members")
+	}
+	return members, nil
+}
+
+func (r *workspaceRepository) CreateWorkspace(workspace *models.Workspaces, admin *models.WorkspaceMembers) error {
+	now := time.Now()
+	workspace.CreatedAt = &now
+	workspace.UpdatedAt = &now
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		admin.WorkspaceID = workspace.ID
+		admin.CreatedAt = &now
+		return tx.Omit("Workspace", "User").Create(admin).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "[WorkspaceRepository.CreateWorkspace]: Error creating workspace")
+	}
+	return nil
+}
+
+func (r *workspaceRepository) UpdateWorkspace(workspace *models.Workspaces) error {
+	now := time.Now()
+	workspace.UpdatedAt = &now
+
+	result := r.db.Model(workspace).Select("name", "updated_at").Updates(workspace)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[WorkspaceRepository.UpdateWorkspace]: Error updating workspace")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrWorkspaceNotFound, "[WorkspaceRepository.UpdateWorkspace]: Error updating workspace")
+	}
+	return nil
+}
+
+func (r *workspaceRepository) DeleteWorkspace(id uint64) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.Workspaces{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrWorkspaceNotFound
+		}
+		return tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceMembers{}).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "[WorkspaceRepository.DeleteWorkspace]: Error deleting workspace")
+	}
+	return nil
+}
+
+func (r *workspaceRepository) CreateWorkspaceMember(member *models.WorkspaceMembers) error {
+	now := time.Now()
+	member.CreatedAt = &now
+
+	if err := r.db.Omit("Workspace", "User").Create(member).Error; err != nil {
+		return errors.Wrap(err, "[WorkspaceRepository.CreateWorkspaceMember]: Error creating workspace member")
+	}
+	return nil
+}
+
+func (r *workspaceRepository) DeleteWorkspaceMember(workspaceID, userID uint64) error {
+	result := r.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&models.WorkspaceMembers{})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[WorkspaceRepository.DeleteWorkspaceMember]: Error deleting workspace member")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ErrWorkspaceMemberNotFound, "[WorkspaceRepository.DeleteWorkspaceMember]: Error deleting workspace member")
+	}
+	return nil
+}
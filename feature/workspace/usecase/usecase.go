@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+type workspaceUsecase struct {
+	workspaceRepository domain.WorkspaceRepository
+	userRepository      domain.UserRepository
+}
+
+func NewWorkspaceUsecase(workspaceRepository domain.WorkspaceRepository, userRepository domain.UserRepository) domain.WorkspaceUsecase {
+	return &workspaceUsecase{
+		workspaceRepository: workspaceRepository,
+		userRepository:      userRepository,
+	}
+}
+
+func (u *workspaceUsecase) GetWorkspaceList(actor domain.Actor) ([]*response.WorkspaceResponse, error) {
+	members, err := u.workspaceRepository.GetUserWorkspaceList(actor.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.GetWorkspaceList]: Error getting workspaces")
+	}
+
+	workspaceResponses := make([]*response.WorkspaceResponse, 0, len(members))
+	for _, member := range members {
+		if member.Workspace == nil {
+			continue
+		}
+		workspaceResponses = append(workspaceResponses, newWorkspaceResponse(member))
+	}
+	return workspaceResponses, nil
+}
+
+func (u *workspaceUsecase) GetWorkspaceByID(actor domain.Actor, id uint64) (*response.WorkspaceResponse, error) {
+	member, err := u.getMembership(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.GetWorkspaceByID]: Error getting workspace")
+	}
+	return newWorkspaceResponse(member), nil
+}
+
+func (u *workspaceUsecase) CreateWorkspace(actor domain.Actor, req *request.WorkspaceRequest) (*response.WorkspaceResponse, error) {
+	workspace := &models.Workspaces{
+		Name:      strings.TrimSpace(req.Name),
+		CreatedBy: actor.UserID,
+	}
+	admin := &models.WorkspaceMembers{
+		UserID: actor.UserID,
+		Role:   constant.WorkspaceRoleAdmin,
+	}
+	if err := u.workspaceRepository.CreateWorkspace(workspace, admin); err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.CreateWorkspace]: Error creating workspace")
+	}
+
+	admin.Workspace = workspace
+	return newWorkspaceResponse(admin), nil
+}
+
+func (u *workspaceUsecase) UpdateWorkspace(actor domain.Actor, id uint64, req *request.WorkspaceRequest) (*response.WorkspaceResponse, error) {
+	member, err := u.getAdminMembership(actor, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.UpdateWorkspace]: Error getting workspace")
+	}
+
+	member.Workspace.Name = strings.TrimSpace(req.Name)
+	if err := u.workspaceRepository.UpdateWorkspace(member.Workspace); err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.UpdateWorkspace]: Error updating workspace")
+	}
+	return newWorkspaceResponse(member), nil
+}
+
+func (u *workspaceUsecase) DeleteWorkspace(actor domain.Actor, id uint64) error {
+	if _, err := u.getAdminMembership(actor, id); err != nil {
+		return errors.Wrap(err, "[WorkspaceUsecase.DeleteWorkspace]: Error getting workspace")
+	}
+
+	if err := u.workspaceRepository.DeleteWorkspace(id); err != nil {
+		return errors.Wrap(err, "[WorkspaceUsecase.DeleteWorkspace]: Error deleting workspace")
+	}
+	return nil
+}
+
+func (u *workspaceUsecase) GetWorkspaceMemberList(actor domain.Actor, id uint64) ([]*response.WorkspaceMemberResponse, error) {
+	if _, err := u.getMembership(actor, id); err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.GetWorkspaceMemberList]: Error getting workspace")
+	}
+
+	members, err := u.workspaceRepository.GetWorkspaceMemberList(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.GetWorkspaceMemberList]: Error getting workspace members")
+	}
+
+	memberResponses := make([]*response.WorkspaceMemberResponse, 0, len(members))
+	for _, member := range members {
+		memberResponses = append(memberResponses, newWorkspaceMemberResponse(member))
+	}
+	return memberResponses, nil
+}
+
+func (u *workspaceUsecase) AddWorkspaceMember(actor domain.Actor, id uint64, req *request.WorkspaceMemberRequest) (*response.WorkspaceMemberResponse, error) {
+	if _, err := u.getAdminMembership(actor, id); err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.AddWorkspaceMember]: Error getting workspace")
+	}
+
+	user, err := u.userRepository.GetUserByEmail(strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			err = domain.NewValidationError("user not found", response.FieldError{
+				Field: "email", Rule: "exists", Message: "email must belong to a registered user",
+			})
+		}
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.AddWorkspaceMember]: Error getting user")
+	}
+
+	_, err = u.workspaceRepository.GetWorkspaceMember(id, user.ID)
+	if err == nil {
+		return nil, errors.Wrap(domain.ErrWorkspaceMemberExists, "[WorkspaceUsecase.AddWorkspaceMember]: Duplicate member")
+	}
+	if !errors.Is(err, domain.ErrWorkspaceMemberNotFound) {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.AddWorkspaceMember]: Error checking workspace member")
+	}
+
+	member := &models.WorkspaceMembers{
+		WorkspaceID: id,
+		UserID:      user.ID,
+		Role:        req.Role,
+	}
+	if err := u.workspaceRepository.CreateWorkspaceMember(member); err != nil {
+		return nil, errors.Wrap(err, "[WorkspaceUsecase.AddWorkspaceMember]: Error creating workspace member")
+	}
+
+	member.User = user
+	return newWorkspaceMemberResponse(member), nil
+}
+
+func (u *workspaceUsecase) RemoveWorkspaceMember(actor domain.Actor, id, userID uint64) error {
+	// Anyone may leave a workspace
+	if userID != actor.UserID {
+		if _, err := u.getAdminMembership(actor, id); err != nil {
+			return errors.Wrap(err, "[WorkspaceUsecase.RemoveWorkspaceMember]: Error getting workspace")
+		}
+	} else if _, err := u.getMembership(actor, id); err != nil {
+		return errors.Wrap(err, "[WorkspaceUsecase.RemoveWorkspaceMember]: Error getting workspace")
+	}
+
+	members, err := u.workspaceRepository.GetWorkspaceMemberList(id)
+	if err != nil {
+		return errors.Wrap(err, "[WorkspaceUsecase.RemoveWorkspaceMember]: Error getting workspace members")
+	}
+	var removed *models.WorkspaceMembers
+	admins := 0
+	for _, member := range members {
+		if member.UserID == userID {
+			removed = member
+		}
+		if member.Role == constant.WorkspaceRoleAdmin {
+			admins++
+		}
+	}
+	if removed == nil {
+		return errors.Wrap(domain.ErrWorkspaceMemberNotFound, "[WorkspaceUsecase.RemoveWorkspaceMember]: Error getting workspace member")
+	}
+	if removed.Role == constant.WorkspaceRoleAdmin && admins == 1 && len(members) > 1 {
+		return errors.Wrap(domain.ErrWorkspaceLastAdmin, "[WorkspaceUsecase.RemoveWorkspaceMember]: Removing the last admin")
+	}
+
+	if err := u.workspaceRepository.DeleteWorkspaceMember(id, userID); err != nil {
+		return errors.Wrap(err, "[WorkspaceUsecase.RemoveWorkspaceMember]: Error deleting workspace member")
+	}
+	return nil
+}
+
+func (u *workspaceUsecase) ResolveWorkspace(actor domain.Actor, id uint64) (domain.Actor, error) {
+	if id != constant.PersonalWorkspaceID {
+		if _, err := u.getMembership(actor, id); err != nil {
+			return domain.Actor{}, errors.Wrap(err, "[WorkspaceUsecase.ResolveWorkspace]: Error getting workspace")
+		}
+	}
+
+	actor.WorkspaceID = id
+	return actor, nil
+}
+
+// getMembership returns the membership of actor in the workspace id, failing
+// with ErrWorkspaceNotFound when there is none.
+func (u *workspaceUsecase) getMembership(actor domain.Actor, id uint64) (*models.WorkspaceMembers, error) {
+	member, err := u.workspaceRepository.GetWorkspaceMember(id, actor.UserID)
+	if errors.Is(err, domain.ErrWorkspaceMemberNotFound) {
+		return nil, domain.ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if member.Workspace == nil {
+		return nil, domain.ErrWorkspaceNotFound
+	}
+	return member, nil
+}
+
+// getAdminMembership is getMembership for actions only admins may take.
+func (u *workspaceUsecase) getAdminMembership(actor domain.Actor, id uint64) (*models.WorkspaceMembers, error) {
+	member, err := u.getMembership(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != constant.WorkspaceRoleAdmin {
+		return nil, domain.ErrWorkspaceNotAdmin
+	}
+	return member, nil
+}
+
+func newWorkspaceResponse(member *models.WorkspaceMembers) *response.WorkspaceResponse {
+	return &response.WorkspaceResponse{
+		ID:        member.Workspace.ID,
+		Name:      member.Workspace.Name,
+		CreatedBy: member.Workspace.CreatedBy,
+		Role:      member.Role,
+		CreatedAt: member.Workspace.CreatedAt,
+		UpdatedAt: member.Workspace.UpdatedAt,
+	}
+}
+
+func newWorkspaceMemberResponse(member *models.WorkspaceMembers) *response.WorkspaceMemberResponse {
+	memberResponse := &response.WorkspaceMemberResponse{
+		WorkspaceID: member.WorkspaceID,
+		UserID:      member.UserID,
+		Role:        member.Role,
+		CreatedAt:   member.CreatedAt,
+	}
+	if member.User != nil {
+		memberResponse.Email = member.User.Email
+		memberResponse.Name = member.User.Name
+	}
+	return memberResponse
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/models"
+	"github.com/pubestpubest/g12-todo-backend/request"
+)
+
+// Mock repository for testing
+type mockWorkspaceRepository struct {
+	workspaces []*models.Workspaces
+	members    []*models.WorkspaceMembers
+}
+
+func (m *mockWorkspaceRepository) GetUserWorkspaceList(userID uint64) ([]*models.WorkspaceMembers, error) {
+	var members []*models.WorkspaceMembers
+	for _, member := range m.members {
+		if member.UserID == userID {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (m *mockWorkspaceRepository) GetWorkspaceMember(workspaceID, userID uint64) (*models.WorkspaceMembers, error) {
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			return member, nil
+		}
+	}
+	return nil, domain.ErrWorkspaceMemberNotFound
+}
+
+func (m *mockWorkspaceRepository) GetWorkspaceMemberList(workspaceID uint64) ([]*models.WorkspaceMembers, error) {
+	var members []*models.WorkspaceMembers
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (m *mockWorkspaceRepository) CreateWorkspace(workspace *models.Workspaces, admin *models.WorkspaceMembers) error {
+	workspace.ID = uint64(len(m.workspaces) + 1)
+	now := time.Now()
+	workspace.CreatedAt = &now
+	workspace.UpdatedAt = &now
+	m.workspaces = append(m.workspaces, workspace)
+
+	admin.WorkspaceID = workspace.ID
+	admin.Workspace = workspace
+	return m.CreateWorkspaceMember(admin)
+}
+
+func (m *mockWorkspaceRepository) UpdateWorkspace(workspace *models.Workspaces) error {
+	now := time.Now()
+	workspace.UpdatedAt = &now
+	return nil
+}
+
+func (m *mockWorkspaceRepository) DeleteWorkspace(id uint64) error {
+	for i, workspace := range m.workspaces {
+		if workspace.ID == id {
+			m.workspaces = append(m.workspaces[:i], m.workspaces[i+1:]...)
+			var kept []*models.WorkspaceMembers
+			for _, member := range m.members {
+				if member.WorkspaceID != id {
+					kept = append(kept, member)
+				}
+			}
+			m.members = kept
+			return nil
+		}
+	}
+	return domain.ErrWorkspaceNotFound
+}
+
+func (m *mockWorkspaceRepository) CreateWorkspaceMember(member *models.WorkspaceMembers) error {
+	member.ID = uint64(len(m.members) + 1)
+	now := time.Now()
+	member.CreatedAt = &now
+	for _, workspace := range m.workspaces {
+		if workspace.ID == member.WorkspaceID {
+			member.Workspace = workspace
+		}
+	}
+	m.members = append(m.members, member)
+	return nil
+}
+
+func (m *mockWorkspaceRepository) DeleteWorkspaceMember(workspaceID, userID uint64) error {
+	for i, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrWorkspaceMemberNotFound
+}
+
+// Mock user repository, only lookups by email are used by workspaces
+type mockUserRepository struct {
+	domain.UserRepository
+	users []*models.Users
+}
+
+func (m *mockUserRepository) GetUserByEmail(email string) (*models.Users, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+var testActor = domain.Actor{UserID: 1}
+
+func TestWorkspaceUsecase_CreateWorkspace(t *testing.T) {
+	mockRepo := &mockWorkspaceRepository{}
+	usecase := NewWorkspaceUsecase(mockRepo, &mockUserRepository{})
+
+	workspace, err := usecase.CreateWorkspace(testActor, &request.WorkspaceRequest{Name: " Physics 101 "})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if workspace.Name != "Physics 101" || workspace.CreatedBy != testActor.UserID || workspace.Role != constant.WorkspaceRoleAdmin {
+		t.Errorf("Expected a workspace administered by its creator, got %+v", workspace)
+	}
+
+	workspaces, err := usecase.GetWorkspaceList(testActor)
+	if err != nil || len(workspaces) != 1 || workspaces[0].ID != workspace.ID {
+		t.Errorf("Expected the new workspace listed, got %+v (%v)", workspaces, err)
+	}
+	if workspaces, _ := usecase.GetWorkspaceList(domain.Actor{UserID: 2}); len(workspaces) != 0 {
+		t.Errorf("Expected no workspaces for another user, got %+v", workspaces)
+	}
+}
+
+func TestWorkspaceUsecase_Members(t *testing.T) {
+	sam := &models.Users{ID: 2, Email: "sam@example.com", Name: "Sam"}
+	kim := &models.Users{ID: 3, Email: "kim@example.com", Name: "Kim"}
+	samActor := domain.Actor{UserID: sam.ID}
+	kimActor := domain.Actor{UserID: kim.ID}
+
+	mockRepo := &mockWorkspaceRepository{}
+	usecase := NewWorkspaceUsecase(mockRepo, &mockUserRepository{users: []*models.Users{sam, kim}})
+	workspace, err := usecase.CreateWorkspace(testActor, &request.WorkspaceRequest{Name: "Physics 101"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	member, err := usecase.AddWorkspaceMember(testActor, workspace.ID, &request.WorkspaceMemberRequest{Email: " Sam@Example.com", Role: constant.WorkspaceRoleMember})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if member.UserID != sam.ID || member.Email != sam.Email {
+		t.Errorf("Expected Sam added, got %+v", member)
+	}
+	if _, err := usecase.AddWorkspaceMember(testActor, workspace.ID, &request.WorkspaceMemberRequest{Email: sam.Email, Role: constant.WorkspaceRoleAdmin}); !errors.Is(err, domain.ErrWorkspaceMemberExists) {
+		t.Errorf("Expected adding a member twice to conflict, got: %v", err)
+	}
+	if _, err := usecase.AddWorkspaceMember(testActor, workspace.ID, &request.WorkspaceMemberRequest{Email: "nobody@example.com", Role: constant.WorkspaceRoleMember}); err == nil {
+		t.Error("Expected adding an unknown email to fail")
+	}
+
+	// Members may look but not manage
+	if members, err := usecase.GetWorkspaceMemberList(samActor, workspace.ID); err != nil || len(members) != 2 {
+		t.Errorf("Expected two members, got %+v (%v)", members, err)
+	}
+	if _, err := usecase.AddWorkspaceMember(samActor, workspace.ID, &request.WorkspaceMemberRequest{Email: kim.Email, Role: constant.WorkspaceRoleMember}); !errors.Is(err, domain.ErrWorkspaceNotAdmin) {
+		t.Errorf("Expected a member not to add members, got: %v", err)
+	}
+	if _, err := usecase.UpdateWorkspace(samActor, workspace.ID, &request.WorkspaceRequest{Name: "Renamed"}); !errors.Is(err, domain.ErrWorkspaceNotAdmin) {
+		t.Errorf("Expected a member not to rename the workspace, got: %v", err)
+	}
+
+	// Outsiders do not learn the workspace exists
+	if _, err := usecase.GetWorkspaceByID(kimActor, workspace.ID); !errors.Is(err, domain.ErrWorkspaceNotFound) {
+		t.Errorf("Expected the workspace to be not found for an outsider, got: %v", err)
+	}
+	if err := usecase.RemoveWorkspaceMember(kimActor, workspace.ID, sam.ID); !errors.Is(err, domain.ErrWorkspaceNotFound) {
+		t.Errorf("Expected an outsider not to remove members, got: %v", err)
+	}
+
+	if err := usecase.RemoveWorkspaceMember(testActor, workspace.ID, testActor.UserID); !errors.Is(err, domain.ErrWorkspaceLastAdmin) {
+		t.Errorf("Expected the last admin not to leave, got: %v", err)
+	}
+	if err := usecase.RemoveWorkspaceMember(samActor, workspace.ID, sam.ID); err != nil {
+		t.Fatalf("Expected a member to leave, got: %v", err)
+	}
+	if _, err := usecase.GetWorkspaceByID(samActor, workspace.ID); !errors.Is(err, domain.ErrWorkspaceNotFound) {
+		t.Errorf("Expected the workspace to be not found after leaving, got: %v", err)
+	}
+}
+
+func TestWorkspaceUsecase_ResolveWorkspace(t *testing.T) {
+	mockRepo := &mockWorkspaceRepository{}
+	usecase := NewWorkspaceUsecase(mockRepo, &mockUserRepository{})
+	workspace, err := usecase.CreateWorkspace(testActor, &request.WorkspaceRequest{Name: "Physics 101"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		actor         domain.Actor
+		id            uint64
+		expectedError error
+	}{
+		{name: "personal space", actor: domain.Actor{UserID: 2}, id: constant.PersonalWorkspaceID},
+		{name: "member", actor: testActor, id: workspace.ID},
+		{name: "not a member", actor: domain.Actor{UserID: 2}, id: workspace.ID, expectedError: domain.ErrWorkspaceNotFound},
+		{name: "unknown workspace", actor: testActor, id: 99, expectedError: domain.ErrWorkspaceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor, err := usecase.ResolveWorkspace(tt.actor, tt.id)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected %v, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if actor.UserID != tt.actor.UserID || actor.WorkspaceID != tt.id {
+				t.Errorf("Expected user %d in workspace %d, got %+v", tt.actor.UserID, tt.id, actor)
+			}
+		})
+	}
+
+	if err := usecase.DeleteWorkspace(testActor, workspace.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := usecase.ResolveWorkspace(testActor, workspace.ID); !errors.Is(err, domain.ErrWorkspaceNotFound) {
+		t.Errorf("Expected a deleted workspace to be not found, got: %v", err)
+	}
+}
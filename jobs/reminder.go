@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	calendarRepository "github.com/pubestpubest/g12-todo-backend/feature/calendar/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/event/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/event/usecase"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultReminderInterval = time.Minute
+
+// StartReminders sends the reminders that are due through notifier, every
+// interval, until ctx is done. interval is a duration such as "30s"; an
+// empty one takes the default. Reminders sent more than two intervals after
+// they were due, after downtime for instance, are flagged late.
+func StartReminders(ctx context.Context, notifier domain.Notifier, interval string) error {
+	reminderInterval, err := utils.ParseDuration(interval, defaultReminderInterval)
+	if err != nil {
+		return errors.Wrap(err, "[Jobs.StartReminders]: Invalid REMINDER_INTERVAL")
+	}
+	if reminderInterval <= 0 {
+		return errors.New("[Jobs.StartReminders]: REMINDER_INTERVAL must be positive")
+	}
+
+	eventUsecase := usecase.NewEventUsecase(
+		repository.NewEventRepository(database.DB),
+		calendarRepository.NewCalendarRepository(database.DB))
+	go runReminders(ctx, eventUsecase, notifier, reminderInterval)
+
+	log.Infof("[Jobs.StartReminders]: Sending reminders every %s", reminderInterval)
+	return nil
+}
+
+func runReminders(ctx context.Context, eventUsecase domain.EventUsecase, notifier domain.Notifier, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := eventUsecase.SendDueReminders(ctx, notifier, 2*interval)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Jobs.runReminders]: Error sending reminders"))
+		}
+		if sent > 0 {
+			log.Infof("[Jobs.runReminders]: Sent %d reminders", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
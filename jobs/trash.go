@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	calendarRepository "github.com/pubestpubest/g12-todo-backend/feature/calendar/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/event/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/event/usecase"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTrashRetention     = 30 * 24 * time.Hour
+	defaultTrashPurgeInterval = time.Hour
+)
+
+// StartTrashPurge permanently deletes events that have been in the trash
+// longer than retention, every interval, until ctx is done. retention and
+// interval are durations such as "720h"; empty ones take the defaults and
+// a retention of "0" keeps the trash forever.
+func StartTrashPurge(ctx context.Context, retention, interval string) error {
+	retentionPeriod, err := utils.ParseDuration(retention, defaultTrashRetention)
+	if err != nil {
+		return errors.Wrap(err, "[Jobs.StartTrashPurge]: Invalid TRASH_RETENTION")
+	}
+	purgeInterval, err := utils.ParseDuration(interval, defaultTrashPurgeInterval)
+	if err != nil {
+		return errors.Wrap(err, "[Jobs.StartTrashPurge]: Invalid TRASH_PURGE_INTERVAL")
+	}
+	if purgeInterval <= 0 {
+		return errors.New("[Jobs.StartTrashPurge]: TRASH_PURGE_INTERVAL must be positive")
+	}
+	if retentionPeriod == 0 {
+		log.Info("[Jobs.StartTrashPurge]: Trash retention disabled")
+		return nil
+	}
+
+	eventUsecase := usecase.NewEventUsecase(
+		repository.NewEventRepository(database.DB),
+		calendarRepository.NewCalendarRepository(database.DB))
+	go runTrashPurge(ctx, eventUsecase, retentionPeriod, purgeInterval)
+
+	log.Infof("[Jobs.StartTrashPurge]: Purging trash older than %s every %s", retentionPeriod, purgeInterval)
+	return nil
+}
+
+func runTrashPurge(ctx context.Context, eventUsecase domain.EventUsecase, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := eventUsecase.PurgeDeletedEvents(retention)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Jobs.runTrashPurge]: Error purging trash"))
+		} else if purged > 0 {
+			log.Infof("[Jobs.runTrashPurge]: Purged %d events from the trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	calendarRepository "github.com/pubestpubest/g12-todo-backend/feature/calendar/repository"
+	eventRepository "github.com/pubestpubest/g12-todo-backend/feature/event/repository"
+	eventUsecase "github.com/pubestpubest/g12-todo-backend/feature/event/usecase"
+	userRepository "github.com/pubestpubest/g12-todo-backend/feature/user/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/webhook/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/webhook/usecase"
+	workspaceRepository "github.com/pubestpubest/g12-todo-backend/feature/workspace/repository"
+	workspaceUsecase "github.com/pubestpubest/g12-todo-backend/feature/workspace/usecase"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultWebhookInterval = 5 * time.Second
+
+// StartWebhooks sends the changes to events in the outbox to the webhooks
+// subscribed to them and retries failed deliveries, every interval, until
+// ctx is done. interval is a duration such as "10s"; an empty one takes
+// the default.
+func StartWebhooks(ctx context.Context, interval string) error {
+	dispatchInterval, err := utils.ParseDuration(interval, defaultWebhookInterval)
+	if err != nil {
+		return errors.Wrap(err, "[Jobs.StartWebhooks]: Invalid WEBHOOK_INTERVAL")
+	}
+	if dispatchInterval <= 0 {
+		return errors.New("[Jobs.StartWebhooks]: WEBHOOK_INTERVAL must be positive")
+	}
+
+	webhookUsecase := usecase.NewWebhookUsecase(
+		repository.NewWebhookRepository(database.DB),
+		eventUsecase.NewEventUsecase(
+			eventRepository.NewEventRepository(database.DB),
+			calendarRepository.NewCalendarRepository(database.DB)),
+		workspaceUsecase.NewWorkspaceUsecase(
+			workspaceRepository.NewWorkspaceRepository(database.DB),
+			userRepository.NewUserRepository(database.DB)))
+	go runWebhooks(ctx, webhookUsecase, dispatchInterval)
+
+	log.Infof("[Jobs.StartWebhooks]: Dispatching webhooks every %s", dispatchInterval)
+	return nil
+}
+
+func runWebhooks(ctx context.Context, webhookUsecase domain.WebhookUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		delivered, err := webhookUsecase.DispatchWebhooks(ctx)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Jobs.runWebhooks]: Error dispatching webhooks"))
+		}
+		if delivered > 0 {
+			log.Infof("[Jobs.runWebhooks]: Delivered %d webhooks", delivered)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
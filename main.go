@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	// Calendars and recurring events name IANA zones, which the runtime
+	// image has no database of
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/jobs"
 	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/notifier"
 	"github.com/pubestpubest/g12-todo-backend/routes"
 	log "github.com/sirupsen/logrus"
 )
@@ -54,7 +60,8 @@ func init() {
 func main() {
 	fmt.Println("Hello, World from main()")
 
-	app := gin.Default()
+	app := gin.New()
+	app.Use(middlewares.Logger(), gin.Recovery())
 
 	app.Use(middlewares.CORSMiddleware())
 
@@ -76,7 +83,42 @@ func main() {
 	})
 
 	v1 := app.Group("/v1")
-	routes.EventRoutes(v1)
+	auth, err := routes.UserRoutes(v1, os.Getenv("JWT_SECRET"), os.Getenv("ACCESS_TOKEN_TTL"), os.Getenv("REFRESH_TOKEN_TTL"))
+	if err != nil {
+		log.Fatal("[main]: Register user routes error: ", err.Error())
+	}
+	routes.EventRoutes(v1, auth)
+	routes.CalendarRoutes(v1, auth)
+	routes.TagRoutes(v1, auth)
+	routes.FeedRoutes(v1, auth)
+	routes.APIKeyRoutes(v1, auth)
+	routes.WebhookRoutes(v1, auth)
+
+	if err := jobs.StartTrashPurge(context.Background(), os.Getenv("TRASH_RETENTION"), os.Getenv("TRASH_PURGE_INTERVAL")); err != nil {
+		log.Fatal("[main]: Start trash purge error: ", err.Error())
+	}
+
+	reminderNotifier, err := notifier.New(notifier.Config{
+		Kind:         os.Getenv("REMINDER_NOTIFIER"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:     os.Getenv("SMTP_FROM"),
+		WebhookURL:   os.Getenv("REMINDER_WEBHOOK_URL"),
+	})
+	if err != nil {
+		log.Fatal("[main]: Create reminder notifier error: ", err.Error())
+	}
+	if err := jobs.StartReminders(context.Background(), reminderNotifier, os.Getenv("REMINDER_INTERVAL")); err != nil {
+		log.Fatal("[main]: Start reminders error: ", err.Error())
+	}
+	if err := database.EventChanges.Start(context.Background()); err != nil {
+		log.Fatal("[main]: Start event change listener error: ", err.Error())
+	}
+	if err := jobs.StartWebhooks(context.Background(), os.Getenv("WEBHOOK_INTERVAL")); err != nil {
+		log.Fatal("[main]: Start webhooks error: ", err.Error())
+	}
 
 	port := os.Getenv("BACKEND_PORT")
 	if port == "" {
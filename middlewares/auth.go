@@ -0,0 +1,135 @@
+package middlewares
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// AuthMiddleware rejects requests without a valid bearer credential, either
+// an access token or an API key, and stores the authenticated actor and
+// their user ID in the context. The actor acts in the workspace named by
+// the X-Workspace-ID header, which they must be a member of; API keys are
+// bound to the workspace they were created in.
+func AuthMiddleware(userUsecase domain.UserUsecase, apiKeyUsecase domain.APIKeyUsecase, workspaceUsecase domain.WorkspaceUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		token = strings.TrimSpace(token)
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			abortAuth(c, errors.Wrap(domain.NewUnauthorizedError("bearer token is required"), "[AuthMiddleware]: Missing token"))
+			return
+		}
+
+		var actor domain.Actor
+		var err error
+		if strings.HasPrefix(token, constant.APIKeyPrefix) {
+			actor, err = apiKeyUsecase.Authenticate(token)
+		} else {
+			actor, err = userUsecase.Authenticate(token)
+		}
+		if err != nil {
+			abortAuth(c, errors.Wrap(err, "[AuthMiddleware]: Error authenticating"))
+			return
+		}
+
+		workspaceID := actor.WorkspaceID
+		if header := strings.TrimSpace(c.GetHeader(constant.HeaderWorkspaceID)); header != "" {
+			workspaceID, err = strconv.ParseUint(header, 10, 64)
+			if err != nil {
+				abortAuth(c, errors.Wrap(domain.NewValidationError(constant.HeaderWorkspaceID+" must be a workspace ID"), "[AuthMiddleware]: Invalid workspace"))
+				return
+			}
+		}
+		if actor.APIKeyID != 0 && workspaceID != actor.WorkspaceID {
+			abortAuth(c, errors.Wrap(domain.ErrWorkspaceMismatch, "[AuthMiddleware]: Invalid workspace"))
+			return
+		}
+		// Keys are checked too, as their user may have left the workspace
+		actor, err = workspaceUsecase.ResolveWorkspace(actor, workspaceID)
+		if err != nil {
+			abortAuth(c, errors.Wrap(err, "[AuthMiddleware]: Error resolving workspace"))
+			return
+		}
+
+		c.Set(constant.ContextActor, actor)
+		c.Set(constant.ContextUserID, actor.UserID)
+		c.Next()
+	}
+}
+
+// QueryCredentials lets the access_token and workspaceId query parameters
+// stand in for the Authorization and X-Workspace-ID headers, for browser
+// clients such as EventSource and WebSocket that can't set headers. It goes
+// before AuthMiddleware, on the endpoints only such clients need. URLs end
+// up in logs and histories, so only short-lived access tokens are taken
+// there, never API keys; Logger redacts them.
+func QueryCredentials() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("access_token"); token != "" && c.GetHeader("Authorization") == "" {
+			if strings.HasPrefix(token, constant.APIKeyPrefix) {
+				abortAuth(c, errors.Wrap(domain.NewUnauthorizedError("api keys must be sent in the Authorization header"), "[QueryCredentials]: API key in query"))
+				return
+			}
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		if workspaceID := c.Query("workspaceId"); workspaceID != "" && c.GetHeader(constant.HeaderWorkspaceID) == "" {
+			c.Request.Header.Set(constant.HeaderWorkspaceID, workspaceID)
+		}
+		c.Next()
+	}
+}
+
+// RequireScope rejects API keys that were not granted scope. Login
+// sessions always pass.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !GetActor(c).HasScope(scope) {
+			abortAuth(c, errors.Wrap(domain.NewForbiddenError("api key is missing scope "+scope), "[RequireScope]: Missing scope"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession rejects API keys, for endpoints such as managing API keys
+// that only a logged in user may use.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetActor(c).APIKeyID != 0 {
+			abortAuth(c, errors.Wrap(domain.NewForbiddenError("api keys cannot be used here"), "[RequireSession]: API key used"))
+			return
+		}
+		c.Next()
+	}
+}
+
+func abortAuth(c *gin.Context, err error) {
+	log.Warn(err)
+	status, code := utils.HTTPError(err)
+	if code == constant.CodeUnauthorized {
+		c.Header("WWW-Authenticate", `Bearer realm="g12-todo"`)
+	}
+	resp := response.Response[interface{}]{
+		Status:  constant.Failed,
+		Message: utils.StandardError(err),
+		Code:    code,
+		Data:    nil,
+	}
+	c.AbortWithStatusJSON(status, resp)
+}
+
+// GetActor returns the actor AuthMiddleware authenticated for the request.
+func GetActor(c *gin.Context) domain.Actor {
+	actor, _ := c.Get(constant.ContextActor)
+	if actor, ok := actor.(domain.Actor); ok {
+		return actor
+	}
+	return domain.Actor{UserID: c.GetUint64(constant.ContextUserID)}
+}
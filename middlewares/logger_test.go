@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/v1/events", expected: "/v1/events"},
+		{path: "/v1/events/stream?access_token=secret", expected: "/v1/events/stream?access_token=REDACTED"},
+		{path: "/v1/events/stream?workspaceId=3&access_token=g12_secret&lastEventId=7", expected: "/v1/events/stream?workspaceId=3&access_token=REDACTED&lastEventId=7"},
+		{path: "/v1/events/stream?access%5Ftoken=secret", expected: "/v1/events/stream?access_token=REDACTED"},
+		{path: "/v1/events?location=access_token", expected: "/v1/events?location=access_token"},
+	}
+
+	for _, tt := range tests {
+		if got := redactPath(tt.path); got != tt.expected {
+			t.Errorf("Expected %s redacted to %s, got %s", tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestQueryCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedAuth   string
+	}{
+		{name: "access token", query: "access_token=token", expectedStatus: http.StatusOK, expectedAuth: "Bearer token"},
+		{name: "api key", query: "access_token=g12_key", expectedStatus: http.StatusUnauthorized},
+		{name: "none", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			var auth string
+			router.GET("/stream", QueryCredentials(), func(c *gin.Context) {
+				auth = c.GetHeader("Authorization")
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stream?"+tt.query, nil))
+			if recorder.Code != tt.expectedStatus || auth != tt.expectedAuth {
+				t.Errorf("Expected status %d with %q, got %d with %q", tt.expectedStatus, tt.expectedAuth, recorder.Code, auth)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeys authenticate scripts and integrations as their user, limited to
+// Scopes. Only the SHA-256 of a key is stored; Prefix is kept in the clear
+// so users can tell their keys apart.
+type APIKeys struct {
+	ID          uint64         `gorm:"primaryKey; auto_increment;" json:"apiKeyId"`
+	UserID      uint64         `gorm:"not null; index" json:"userId"`
+	WorkspaceID uint64         `gorm:"not null; default:0" json:"workspaceId"` // the only workspace the key acts in
+	Name        string         `gorm:"not null" json:"name"`
+	Prefix      string         `gorm:"not null" json:"prefix"`
+	KeyHash     string         `gorm:"not null; uniqueIndex" json:"-"`
+	Scopes      []string       `gorm:"serializer:json; type:jsonb" json:"scopes"`
+	LastUsedAt  *time.Time     `gorm:"default:null" json:"lastUsedAt"`
+	CreatedAt   *time.Time     `gorm:"default:now()" json:"createdAt"`
+	DeleteAt    gorm.DeletedAt `gorm:"default:null" json:"-"` // revoked
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"gorm.io/gorm"
+)
+
+// Calendars group a user's events, e.g. "Work" and "Home".
+type Calendars struct {
+	ID          uint64         `gorm:"primaryKey; auto_increment;" json:"calendarId"`
+	WorkspaceID uint64         `gorm:"not null; default:0; index" json:"workspaceId"`
+	OwnerID     uint64         `gorm:"not null; index" json:"ownerId"`
+	Name        string         `gorm:"not null" json:"name"`
+	Color       string         `gorm:"not null" json:"color"`    // e.g. "#4285f4"
+	Timezone    string         `gorm:"not null" json:"timezone"` // IANA name, e.g. "Europe/London"
+	CreatedAt   *time.Time     `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt   *time.Time     `gorm:"default:now()" json:"updateAt"`
+	DeleteAt    gorm.DeletedAt `gorm:"default:null" json:"-"`
+
+	// Workflow are the states events in the calendar move through, in
+	// board order; DefaultWorkflow when null.
+	Workflow []WorkflowState `gorm:"serializer:json; type:jsonb" json:"workflow"`
+}
+
+// WorkflowState is a column of a calendar's board. Events in a terminal
+// state are complete.
+type WorkflowState struct {
+	Name     string `json:"name"`
+	Terminal bool   `json:"terminal"`
+}
+
+// DefaultWorkflow is the workflow of calendars without one of their own.
+var DefaultWorkflow = []WorkflowState{
+	{Name: constant.WorkflowTodo},
+	{Name: constant.WorkflowInProgress},
+	{Name: constant.WorkflowReview},
+	{Name: constant.WorkflowDone, Terminal: true},
+}
+
+// CalendarShares give another user access to a calendar and its events
+// once they accept the invitation.
+type CalendarShares struct {
+	ID          uint64     `gorm:"primaryKey; auto_increment;" json:"shareId"`
+	WorkspaceID uint64     `gorm:"not null; default:0; index" json:"workspaceId"`
+	CalendarID  uint64     `gorm:"not null; uniqueIndex:idx_calendar_shares_calendar_user" json:"calendarId"`
+	UserID      uint64     `gorm:"not null; uniqueIndex:idx_calendar_shares_calendar_user; index" json:"userId"`
+	InvitedBy   uint64     `gorm:"not null" json:"invitedBy"`
+	Role        string     `gorm:"not null" json:"role"`   // viewer, editor or owner
+	Status      string     `gorm:"not null" json:"status"` // pending, accepted or declined
+	CreatedAt   *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt   *time.Time `gorm:"default:now()" json:"updateAt"`
+
+	Calendar *Calendars `json:"-"`
+	User     *Users     `json:"-"`
+}
@@ -8,13 +8,110 @@ import (
 
 type Events struct {
 	ID          uint64         `gorm:"primaryKey; auto_increment; index;" json:"eventId"`
+	WorkspaceID uint64         `gorm:"not null; default:0; index; uniqueIndex:idx_events_workspace_owner_uid,priority:1,where:delete_at IS NULL" json:"workspaceId"`
+	OwnerID     uint64         `gorm:"not null; default:0; index; uniqueIndex:idx_events_workspace_owner_uid,priority:2,where:delete_at IS NULL" json:"ownerId"`
+	CalendarID  *uint64        `gorm:"index; default:null" json:"calendarId"`
+	UID         *string        `gorm:"column:uid; uniqueIndex:idx_events_workspace_owner_uid,priority:3,where:delete_at IS NULL; default:null" json:"uid"` // iCalendar UID, unique per owner and workspace
+	Type        string         `gorm:"not null; default:event" json:"type"`                                                                                // constant.EventTypeEvent or EventTypeTask
 	Title       string         `gorm:"not null"`
 	Description *string        `gorm:"default:null"`
 	Complete    bool           `gorm:"default:false; not null"`
+	CompletedAt *time.Time     `gorm:"default:null" json:"completedAt"` // when Complete last became true
 	CreatedAt   *time.Time     `gorm:"default:now()"  json:"createdAt"`
 	UpdatedAt   *time.Time     `gorm:"default:now()"  json:"updateAt"`
 	DeleteAt    gorm.DeletedAt `gorm:"default:null" json:"-"`
-	Location    string         `gorm:"not null" json:"location"`
-	StartTime   time.Time      `gorm:"not null" json:"startTime"`
-	EndTime     time.Time      `gorm:"not null" json:"endTime"`
+	Location    string         `gorm:"not null; default:''" json:"location"` // empty for none
+	StartTime   *time.Time     `gorm:"default:null" json:"startTime"`        // null for tasks without a window
+	EndTime     *time.Time     `gorm:"default:null" json:"endTime"`
+	DueAt       *time.Time     `gorm:"index; default:null" json:"dueAt"`    // tasks only
+	Priority    int            `gorm:"not null; default:0" json:"priority"` // index into constant.Priorities
+	Version     uint64         `gorm:"not null; default:1" json:"version"`
+
+	// Status is the workflow state of the event, which decides Complete.
+	// Position orders the events in the same state of a calendar, or of
+	// their owner outside calendars, from 0.
+	Status   string `gorm:"not null; default:''" json:"status"`
+	Position int    `gorm:"not null; default:0" json:"position"`
+
+	// Reminders are ordered by MinutesBefore.
+	Reminders []*Reminders `gorm:"foreignKey:EventID" json:"reminders"`
+
+	// Recurrence. RRule is an RFC 5545 RRULE value anchored at StartTime;
+	// RecurrenceEnd is the start of the last occurrence, null while the
+	// series is unbounded. The series recurs in the IANA zone Timezone, so
+	// occurrences keep their wall-clock time and weekday across DST; in UTC
+	// when it is null.
+	RRule         *string     `gorm:"column:rrule; default:null" json:"rrule"`
+	ExDates       []time.Time `gorm:"serializer:json; type:jsonb" json:"exdates"`
+	RecurrenceEnd *time.Time  `gorm:"default:null" json:"recurrenceEnd"`
+	Timezone      *string     `gorm:"default:null" json:"timezone"`
+
+	// Tags are the event owner's, ordered by name.
+	Tags []*Tags `gorm:"many2many:event_tags; joinForeignKey:EventID; joinReferences:TagID" json:"tags"`
+
+	// Items are the checklist of the event, of the whole series for a
+	// recurring one. AutoComplete makes Complete follow it.
+	Items        []*EventItems `gorm:"foreignKey:EventID" json:"items"`
+	AutoComplete bool          `gorm:"not null; default:false" json:"autoComplete"`
+
+	// BlockedBy are the events that must be complete before this one can
+	// be, Blocks the ones waiting on it; trashed ones are left out. Only
+	// their IDs and Complete are loaded.
+	BlockedBy              []*Events `gorm:"many2many:event_dependencies; joinForeignKey:EventID; joinReferences:BlockerID" json:"blockedBy"`
+	Blocks                 []*Events `gorm:"many2many:event_dependencies; joinForeignKey:BlockerID; joinReferences:EventID" json:"blocks"`
+	AllowBlockedCompletion bool      `gorm:"not null; default:false" json:"allowBlockedCompletion"`
+
+	// Changes are the constant.WebhookEvents the write saving the event
+	// publishes; it adds them to the outbox in its transaction.
+	Changes []string `gorm:"-" json:"-"`
+	// CompletionChanged has that write publish the events in Blocks too,
+	// whose Blocked changes with Complete.
+	CompletionChanged bool `gorm:"-" json:"-"`
+	// PreviousCalendarID is the calendar that write takes the event out of.
+	PreviousCalendarID *uint64 `gorm:"-" json:"-"`
+}
+
+// EventDependencies is the join table of events and the events blocking
+// them.
+type EventDependencies struct {
+	EventID   uint64 `gorm:"primaryKey" json:"eventId"`
+	BlockerID uint64 `gorm:"primaryKey; index" json:"blockerId"`
+}
+
+// EventItems are the steps of an event's checklist, ordered by Position
+// from 0.
+type EventItems struct {
+	ID          uint64     `gorm:"primaryKey; auto_increment;" json:"itemId"`
+	WorkspaceID uint64     `gorm:"not null; default:0; index" json:"workspaceId"`
+	EventID     uint64     `gorm:"not null; index" json:"eventId"`
+	Text        string     `gorm:"not null" json:"text"`
+	Done        bool       `gorm:"not null; default:false" json:"done"`
+	Position    int        `gorm:"not null; default:0" json:"position"`
+	CreatedAt   *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt   *time.Time `gorm:"default:now()" json:"updateAt"`
+}
+
+// EventExceptions overrides a single occurrence of a recurring event,
+// identified by the start the series gives it.
+type EventExceptions struct {
+	ID              uint64     `gorm:"primaryKey; auto_increment;" json:"exceptionId"`
+	WorkspaceID     uint64     `gorm:"not null; default:0; index" json:"workspaceId"`
+	EventID         uint64     `gorm:"not null; uniqueIndex:idx_event_exception_occurrence" json:"eventId"`
+	OccurrenceStart time.Time  `gorm:"not null; uniqueIndex:idx_event_exception_occurrence" json:"occurrenceStart"`
+	Title           *string    `gorm:"default:null" json:"title"`
+	Description     *string    `gorm:"default:null" json:"description"`
+	Location        *string    `gorm:"default:null" json:"location"`
+	StartTime       *time.Time `gorm:"default:null" json:"startTime"`
+	EndTime         *time.Time `gorm:"default:null" json:"endTime"`
+	Complete        *bool      `gorm:"default:null" json:"complete"`
+	CreatedAt       *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt       *time.Time `gorm:"default:now()" json:"updateAt"`
+}
+
+// EventSearchResult is an event matched by full-text search together with
+// its relevance and a highlighted excerpt.
+type EventSearchResult struct {
+	Events
+	Rank    float64
+	Snippet string
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Feeds is a subscribable iCalendar feed of events. Only the SHA-256 of its
+// token is stored, so the token is shown once, when it is issued.
+type Feeds struct {
+	ID          uint64         `gorm:"primaryKey; auto_increment;" json:"feedId"`
+	WorkspaceID uint64         `gorm:"not null; default:0; index" json:"workspaceId"`
+	OwnerID     uint64         `gorm:"not null; default:0; index" json:"ownerId"`
+	Name        string         `gorm:"not null" json:"name"`
+	TokenHash   string         `gorm:"not null; uniqueIndex" json:"-"`
+	Complete    *bool          `gorm:"default:null" json:"complete"`
+	Location    string         `gorm:"not null; default:''" json:"location"`
+	CreatedAt   *time.Time     `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt   *time.Time     `gorm:"default:now()" json:"updateAt"`
+	DeleteAt    gorm.DeletedAt `gorm:"default:null" json:"-"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Reminders notify the owner of an event MinutesBefore it starts, or is due
+// for tasks without a window. RemindAt is when the next one is due, null
+// once there is none left; a recurring event's moves on to the next
+// occurrence after each one.
+type Reminders struct {
+	ID            uint64     `gorm:"primaryKey; auto_increment;" json:"reminderId"`
+	WorkspaceID   uint64     `gorm:"not null; default:0; index" json:"workspaceId"`
+	EventID       uint64     `gorm:"not null; index" json:"eventId"`
+	UserID        uint64     `gorm:"not null" json:"userId"` // who is reminded, the owner of the event
+	MinutesBefore int        `gorm:"not null" json:"minutesBefore"`
+	RemindAt      *time.Time `gorm:"index; default:null" json:"remindAt"`
+	SentAt        *time.Time `gorm:"default:null" json:"sentAt"`          // when the last one was sent
+	Late          bool       `gorm:"not null; default:false" json:"late"` // the last one was sent after its time was missed
+	Attempts      int        `gorm:"not null; default:0" json:"attempts"` // failed deliveries of the next one
+	CreatedAt     *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt     *time.Time `gorm:"default:now()" json:"updateAt"`
+
+	Event *Events `gorm:"-" json:"-"`
+	User  *Users  `gorm:"-" json:"-"`
+}
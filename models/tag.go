@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Tags label events across calendars, e.g. "exam". Names are lower case and
+// unique per owner within a workspace.
+type Tags struct {
+	ID          uint64     `gorm:"primaryKey; auto_increment;" json:"tagId"`
+	WorkspaceID uint64     `gorm:"not null; default:0; uniqueIndex:idx_tags_workspace_owner_name,priority:1" json:"workspaceId"`
+	OwnerID     uint64     `gorm:"not null; uniqueIndex:idx_tags_workspace_owner_name,priority:2" json:"ownerId"`
+	Name        string     `gorm:"not null; uniqueIndex:idx_tags_workspace_owner_name,priority:3" json:"name"`
+	CreatedAt   *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt   *time.Time `gorm:"default:now()" json:"updateAt"`
+}
+
+// EventTags is the join table of events and their tags.
+type EventTags struct {
+	EventID uint64 `gorm:"primaryKey" json:"eventId"`
+	TagID   uint64 `gorm:"primaryKey; index" json:"tagId"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Users struct {
+	ID           uint64         `gorm:"primaryKey; auto_increment;" json:"userId"`
+	Email        string         `gorm:"not null; uniqueIndex" json:"email"` // stored lower-cased
+	Name         string         `gorm:"not null" json:"name"`
+	PasswordHash string         `gorm:"not null" json:"-"` // bcrypt
+	CreatedAt    *time.Time     `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt    *time.Time     `gorm:"default:now()" json:"updateAt"`
+	DeleteAt     gorm.DeletedAt `gorm:"default:null" json:"-"`
+}
+
+// RefreshTokens are single-use: refreshing revokes the token presented and
+// issues a new one. Only the SHA-256 of a token is stored.
+type RefreshTokens struct {
+	ID        uint64     `gorm:"primaryKey; auto_increment;" json:"refreshTokenId"`
+	UserID    uint64     `gorm:"not null; index" json:"userId"`
+	TokenHash string     `gorm:"not null; uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt *time.Time `gorm:"default:null" json:"revokedAt"`
+	CreatedAt *time.Time `gorm:"default:now()" json:"createdAt"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhooks post the changes to the events their owner can see that they
+// subscribe to to URL, signed with Secret.
+type Webhooks struct {
+	ID          uint64         `gorm:"primaryKey; auto_increment;" json:"webhookId"`
+	WorkspaceID uint64         `gorm:"not null; default:0; index" json:"workspaceId"`
+	OwnerID     uint64         `gorm:"not null; default:0; index" json:"ownerId"`
+	URL         string         `gorm:"not null" json:"url"`
+	Secret      string         `gorm:"not null" json:"-"`
+	Events      []string       `gorm:"serializer:json; type:jsonb" json:"events"` // constant.WebhookEvents subscribed to
+	Active      bool           `gorm:"not null; default:true" json:"active"`
+	CreatedAt   *time.Time     `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt   *time.Time     `gorm:"default:now()" json:"updateAt"`
+	DeleteAt    gorm.DeletedAt `gorm:"default:null" json:"-"`
+}
+
+// OutboxEvents are changes to events, saved in the transaction making them
+// and fanned out to the webhooks subscribed to them once DispatchedAt is
+// set. Version is the one of the event the change left it at.
+// PreviousCalendarID is the calendar the change took the event out of, so
+// the users it is shared with hear that it left. A change with a UserID is
+// for that user alone: the deletion of an event they no longer see once a
+// calendar stops being shared with them.
+type OutboxEvents struct {
+	ID                 uint64     `gorm:"primaryKey; auto_increment;" json:"outboxEventId"`
+	WorkspaceID        uint64     `gorm:"not null; default:0; index" json:"workspaceId"`
+	Type               string     `gorm:"not null" json:"type"`
+	EventID            uint64     `gorm:"not null" json:"eventId"`
+	OwnerID            uint64     `gorm:"not null" json:"ownerId"`
+	CalendarID         *uint64    `gorm:"default:null" json:"calendarId"`
+	PreviousCalendarID *uint64    `gorm:"default:null" json:"previousCalendarId"`
+	UserID             *uint64    `gorm:"default:null" json:"userId"`
+	Version            uint64     `gorm:"not null" json:"version"`
+	CreatedAt          *time.Time `gorm:"default:now()" json:"createdAt"`
+	DispatchedAt       *time.Time `gorm:"index; default:null" json:"dispatchedAt"`
+}
+
+// WebhookDeliveries are the posts of outbox events to webhooks. Payload is
+// the body, the same on every attempt; the response or error of the last
+// attempt is kept for the delivery log.
+type WebhookDeliveries struct {
+	ID             uint64     `gorm:"primaryKey; auto_increment;" json:"deliveryId"`
+	WorkspaceID    uint64     `gorm:"not null; default:0; index" json:"workspaceId"`
+	WebhookID      uint64     `gorm:"not null; index" json:"webhookId"`
+	OutboxEventID  uint64     `gorm:"not null" json:"outboxEventId"`
+	Type           string     `gorm:"not null" json:"type"`
+	Payload        string     `gorm:"type:jsonb; not null" json:"payload"`
+	Status         string     `gorm:"not null; index" json:"status"` // constant.WebhookDeliveryPending, Delivered or Dead
+	Attempts       int        `gorm:"not null; default:0" json:"attempts"`
+	NextAttemptAt  *time.Time `gorm:"index; default:null" json:"nextAttemptAt"` // null unless pending
+	ResponseStatus int        `gorm:"not null; default:0" json:"responseStatus"`
+	LastError      string     `gorm:"not null; default:''" json:"lastError"`
+	DeliveredAt    *time.Time `gorm:"default:null" json:"deliveredAt"`
+	CreatedAt      *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt      *time.Time `gorm:"default:now()" json:"updateAt"`
+
+	Webhook *Webhooks `gorm:"-" json:"-"`
+}
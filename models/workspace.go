@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Workspaces isolate the data of a group of users, e.g. a class, from
+// every other workspace. Workspace 0 is not stored: it is the personal
+// space each user has.
+type Workspaces struct {
+	ID        uint64         `gorm:"primaryKey; auto_increment;" json:"workspaceId"`
+	Name      string         `gorm:"not null" json:"name"`
+	CreatedBy uint64         `gorm:"not null" json:"createdBy"`
+	CreatedAt *time.Time     `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt *time.Time     `gorm:"default:now()" json:"updateAt"`
+	DeleteAt  gorm.DeletedAt `gorm:"default:null" json:"-"`
+}
+
+// WorkspaceMembers may act in a workspace; admins also manage it.
+type WorkspaceMembers struct {
+	ID          uint64     `gorm:"primaryKey; auto_increment;" json:"memberId"`
+	WorkspaceID uint64     `gorm:"not null; uniqueIndex:idx_workspace_members_workspace_user" json:"workspaceId"`
+	UserID      uint64     `gorm:"not null; uniqueIndex:idx_workspace_members_workspace_user; index" json:"userId"`
+	Role        string     `gorm:"not null" json:"role"` // admin or member
+	CreatedAt   *time.Time `gorm:"default:now()" json:"createdAt"`
+
+	Workspace *Workspaces `json:"-"`
+	User      *Users      `json:"-"`
+}
@@ -0,0 +1,27 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/response"
+	log "github.com/sirupsen/logrus"
+)
+
+// logNotifier writes reminders to the log, for development.
+type logNotifier struct{}
+
+func NewLogNotifier() domain.Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, notification *response.ReminderNotification) error {
+	log.WithFields(log.Fields{
+		"reminderId": notification.ReminderID,
+		"eventId":    notification.EventID,
+		"email":      notification.Email,
+		"startTime":  notification.StartTime,
+		"late":       notification.Late,
+	}).Infof("[Notifier.Log]: Reminder: %s", notification.Title)
+	return nil
+}
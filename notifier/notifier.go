@@ -0,0 +1,36 @@
+package notifier
+
+import (
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+)
+
+// Config picks the notifier reminders are delivered through and sets it
+// up. Kind is constant.NotifierLog, the default, NotifierSMTP or
+// NotifierWebhook.
+type Config struct {
+	Kind string
+
+	SMTPHost     string
+	SMTPPort     string // defaults to 587
+	SMTPUsername string // no authentication when empty
+	SMTPPassword string
+	SMTPFrom     string
+
+	WebhookURL string
+}
+
+// New returns the notifier config describes.
+func New(config Config) (domain.Notifier, error) {
+	switch config.Kind {
+	case "", constant.NotifierLog:
+		return NewLogNotifier(), nil
+	case constant.NotifierSMTP:
+		return NewSMTPNotifier(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.SMTPFrom)
+	case constant.NotifierWebhook:
+		return NewWebhookNotifier(config.WebhookURL)
+	default:
+		return nil, errors.Errorf("unknown notifier %q", config.Kind)
+	}
+}
@@ -0,0 +1,124 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// smtpTimeout bounds a whole delivery, so a stalled server can't hold up
+// the reminders claimed with it.
+const smtpTimeout = 30 * time.Second
+
+// smtpNotifier emails reminders, upgrading to TLS when the server offers it.
+type smtpNotifier struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPNotifier(host, port, username, password, from string) (domain.Notifier, error) {
+	if host == "" || from == "" {
+		return nil, errors.New("SMTP_HOST and SMTP_FROM are required")
+	}
+	if port == "" {
+		port = "587"
+	}
+
+	notifier := &smtpNotifier{host: host, addr: net.JoinHostPort(host, port), from: from}
+	if username != "" {
+		notifier.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return notifier, nil
+}
+
+func (n *smtpNotifier) Notify(ctx context.Context, notification *response.ReminderNotification) error {
+	dialer := net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error connecting")
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error setting deadline")
+	}
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error greeting server")
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return errors.Wrap(err, "[Notifier.SMTP]: Error starting TLS")
+		}
+	}
+	if n.auth != nil {
+		if err := client.Auth(n.auth); err != nil {
+			return errors.Wrap(err, "[Notifier.SMTP]: Error authenticating")
+		}
+	}
+
+	if err := client.Mail(n.from); err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error setting sender")
+	}
+	if err := client.Rcpt(notification.Email); err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error setting recipient")
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error starting message")
+	}
+	if _, err := writer.Write(reminderEmail(n.from, notification)); err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error writing message")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(err, "[Notifier.SMTP]: Error sending message")
+	}
+	return client.Quit()
+}
+
+// reminderEmail is the message reminding of notification, sent from from.
+func reminderEmail(from string, notification *response.ReminderNotification) []byte {
+	title := headerText(notification.Title)
+	subject := "Reminder: " + title
+	if notification.Late {
+		subject += " (late)"
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s starts at %s.\r\n", title, notification.StartTime.UTC().Format(time.RFC1123))
+	if notification.Location != "" {
+		fmt.Fprintf(&body, "Location: %s\r\n", headerText(notification.Location))
+	}
+	if notification.Late {
+		fmt.Fprintf(&body, "\r\nThis reminder was due at %s but could not be sent on time.\r\n", notification.RemindAt.UTC().Format(time.RFC1123))
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", notification.Email)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(body.String())
+	return []byte(message.String())
+}
+
+// headerText puts user text on one line, so it can't break out of a header.
+func headerText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,140 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+// startSMTPStub accepts one SMTP session on a local port, without STARTTLS
+// or authentication, and sends the commands and message it received on the
+// channel once the client quits.
+func startSMTPStub(t *testing.T) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP stub")
+		for data := false; ; {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+
+			switch {
+			case data:
+				if line == "." {
+					data = false
+					reply("250 OK")
+				}
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case line == "DATA":
+				data = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case line == "QUIT":
+				reply("221 Bye")
+				received <- lines
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	addr, received := startSMTPStub(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	notifier, err := NewSMTPNotifier(host, port, "", "", "reminders@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	err = notifier.Notify(context.Background(), &response.ReminderNotification{
+		ReminderID: 1,
+		EventID:    2,
+		Title:      "Review\r\nBcc: someone@example.com",
+		Location:   "Office",
+		StartTime:  start,
+		RemindAt:   start.Add(-15 * time.Minute),
+		Late:       true,
+		Email:      "owner@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var lines []string
+	select {
+	case lines = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the stub to receive a message")
+	}
+	session := strings.Join(lines, "\n")
+
+	for _, expected := range []string{
+		"MAIL FROM:<reminders@example.com>",
+		"RCPT TO:<owner@example.com>",
+		"To: owner@example.com",
+		"Subject: Reminder: Review Bcc: someone@example.com (late)",
+		"Location: Office",
+	} {
+		if !strings.Contains(session, expected) {
+			t.Errorf("Expected the session to contain %q, got:\n%s", expected, session)
+		}
+	}
+	if strings.Contains(session, "\nBcc:") {
+		t.Errorf("Expected the title kept out of the headers, got:\n%s", session)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        Config
+		expectedError bool
+	}{
+		{name: "log by default", config: Config{}},
+		{name: "smtp", config: Config{Kind: "smtp", SMTPHost: "localhost", SMTPFrom: "reminders@example.com"}},
+		{name: "smtp without host", config: Config{Kind: "smtp"}, expectedError: true},
+		{name: "webhook", config: Config{Kind: "webhook", WebhookURL: "https://example.com/hook"}},
+		{name: "webhook without url", config: Config{Kind: "webhook", WebhookURL: "example.com"}, expectedError: true},
+		{name: "unknown", config: Config{Kind: "pager"}, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, err := New(tt.config)
+			if tt.expectedError != (err != nil) {
+				t.Errorf("Expected error %v, got: %v", tt.expectedError, err)
+			}
+			if err == nil && notifier == nil {
+				t.Error("Expected a notifier, got nil")
+			}
+		})
+	}
+}
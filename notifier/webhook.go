@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// webhookNotifier posts reminders as JSON to a URL, which must answer with
+// a 2xx status.
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(webhookURL string) (domain.Notifier, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.Errorf("REMINDER_WEBHOOK_URL %q must be an http or https URL", webhookURL)
+	}
+	return &webhookNotifier{url: webhookURL, client: &http.Client{Timeout: webhookTimeout}}, nil
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, notification *response.ReminderNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Wrap(err, "[Notifier.Webhook]: Error encoding reminder")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "[Notifier.Webhook]: Error creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "[Notifier.Webhook]: Error posting reminder")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("[Notifier.Webhook]: Reminder rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
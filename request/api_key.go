@@ -0,0 +1,6 @@
+package request
+
+type APIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=events:read events:write feeds:read feeds:write webhooks:read webhooks:write"`
+}
@@ -0,0 +1,34 @@
+package request
+
+type CalendarRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Color    string `json:"color" binding:"omitempty,hexcolor"`    // defaults to constant.DefaultCalendarColor
+	Timezone string `json:"timezone" binding:"omitempty,timezone"` // defaults to UTC
+	// Workflow replaces the states of the calendar, in board order; the
+	// default workflow when empty, and the current one of a calendar being
+	// updated when left out. It needs a terminal and a non-terminal state.
+	Workflow []*WorkflowStateRequest `json:"workflow" binding:"max=20,dive"`
+}
+
+type WorkflowStateRequest struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	Terminal bool   `json:"terminal"`
+}
+
+// CalendarDeleteRequest is the query of a calendar delete. Mode "cascade"
+// moves the events of the calendar to the trash, "move" moves them to the
+// calendar MoveTo.
+type CalendarDeleteRequest struct {
+	Mode   string  `form:"mode" binding:"required,oneof=cascade move" json:"mode"`
+	MoveTo *uint64 `form:"moveTo" binding:"required_if=Mode move" json:"moveTo"`
+}
+
+// CalendarShareRequest invites the user registered with Email to a calendar.
+type CalendarShareRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+type CalendarShareUpdateRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
@@ -5,10 +5,110 @@ import (
 )
 
 type EventRequest struct {
-	Title       string    `json:"title" binding:"required"`
-	Description string    `json:"description"`
-	Location    string    `json:"location" binding:"required"`
-	StartTime   time.Time `json:"startTime" binding:"required"`
-	EndTime     time.Time `json:"endTime" binding:"required"`
-	Complete    *bool     `json:"complete" binding:"required"`
+	Type        string  `json:"type" binding:"omitempty,oneof=event task"` // defaults to event
+	Title       string  `json:"title" binding:"required,max=200"`
+	Description string  `json:"description" binding:"max=2000"`
+	Location    string  `json:"location" binding:"max=200"` // none when empty
+	Complete    *bool   `json:"complete" binding:"required"`
+	CalendarID  *uint64 `json:"calendarId"` // none when nil
+	// Events need StartTime and EndTime; tasks may leave both out, and
+	// only they have a due date.
+	StartTime *time.Time `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+	DueAt     *time.Time `json:"dueAt"`
+	Priority  string     `json:"priority" binding:"omitempty,oneof=none low medium high urgent"` // defaults to none
+	// UID is the iCalendar UID, generated when empty. It is only read on
+	// creation; updates keep the stored one.
+	UID string `json:"uid" binding:"max=255"`
+	// Tags name the event's tags, replacing the ones it had; names are
+	// matched case-insensitively.
+	Tags []string `json:"tags" binding:"max=20,dive,max=50"`
+
+	// Recurrence, all optional. RRule is an RFC 5545 RRULE value such as
+	// "FREQ=WEEKLY;BYDAY=MO" anchored at StartTime; no occurrence starts
+	// after RecurrenceEnd. The series recurs in the IANA zone Timezone,
+	// the one of its calendar when empty and UTC without a calendar.
+	RRule         string      `json:"rrule" binding:"max=500"`
+	ExDates       []time.Time `json:"exdates" binding:"max=1000"`
+	RecurrenceEnd *time.Time  `json:"recurrenceEnd"`
+	Timezone      string      `json:"timezone" binding:"omitempty,timezone"`
+
+	// AutoComplete completes the event once every item of its checklist is
+	// done, and reopens it when one isn't.
+	AutoComplete bool `json:"autoComplete"`
+
+	// BlockedBy are the events that must be complete first, unless
+	// AllowBlockedCompletion.
+	BlockedBy              []uint64 `json:"blockedBy" binding:"max=50"`
+	AllowBlockedCompletion bool     `json:"allowBlockedCompletion"`
+
+	// Status is a state of the workflow of the calendar and decides
+	// Complete. When empty the event keeps its state, or takes the first
+	// one agreeing with Complete.
+	Status string `json:"status" binding:"max=50"`
+
+	// Reminders are minutes before the event starts, or a task is due, to
+	// remind its owner at, replacing the ones it had.
+	Reminders []int `json:"reminders" binding:"max=10,dive,min=0,max=40320"`
+}
+
+// EventMoveRequest puts an event in the workflow state Status at Position
+// of its column, counting from 0, shifting the events from there down; at
+// the end when Position is nil.
+type EventMoveRequest struct {
+	Status   string `json:"status" binding:"required,max=50"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+// EventItemRequest is a checklist item. Position moves it, counting from
+// 0; new items go last when it is nil.
+type EventItemRequest struct {
+	Text     string `json:"text" binding:"required,max=500"`
+	Done     bool   `json:"done"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+// OccurrenceRequest selects which part of a recurring event a write applies
+// to. Occurrence is the start the series gives the targeted occurrence and
+// is required unless Scope is "all".
+type OccurrenceRequest struct {
+	Scope      string     `form:"scope" binding:"omitempty,oneof=this following all" json:"scope"`
+	Occurrence *time.Time `form:"occurrence" json:"occurrence"`
+}
+
+// EventDeleteRequest is the query of a delete. Permanent skips the trash
+// and applies to whole events only.
+type EventDeleteRequest struct {
+	OccurrenceRequest
+	Permanent bool `form:"permanent" json:"permanent"`
+}
+
+type EventListRequest struct {
+	PaginationRequest
+	CalendarID *uint64    `form:"calendarId" json:"calendarId"`
+	Complete   *bool      `form:"complete" json:"complete"`
+	Status     string     `form:"status" json:"status"`
+	Location   string     `form:"location" json:"location"`
+	Tags       string     `form:"tags" json:"tags"` // comma separated, e.g. "exam,math"
+	TagMode    string     `form:"tagMode" binding:"omitempty,oneof=any all" json:"tagMode"`
+	From       *time.Time `form:"from" json:"from"` // RFC 3339, matches events ending after it
+	To         *time.Time `form:"to" json:"to"`     // RFC 3339, matches events starting before it
+	Type       string     `form:"type" binding:"omitempty,oneof=event task" json:"type"`
+	Sort       string     `form:"sort" json:"sort"` // e.g. "-priority,dueAt"
+	// Cursor pages by cursor rather than by page: empty for the first page,
+	// then the nextCursor or prevCursor of a page. Only this mode counts the
+	// total on request, WithTotal.
+	Cursor    *string `form:"cursor" json:"cursor"`
+	WithTotal bool    `form:"withTotal" json:"withTotal"`
+}
+
+// EventStreamRequest resumes a stream after a change, as the Last-Event-ID
+// header does for clients that can't set headers.
+type EventStreamRequest struct {
+	LastEventID *uint64 `form:"lastEventId" json:"lastEventId"`
+}
+
+type EventSearchRequest struct {
+	EventListRequest
+	Query string `form:"q" binding:"required,max=200" json:"q"`
 }
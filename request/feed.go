@@ -0,0 +1,9 @@
+package request
+
+// FeedRequest creates a feed. Complete and Location filter its events the
+// way they filter the event list.
+type FeedRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Complete *bool  `json:"complete"`
+	Location string `json:"location" binding:"max=200"`
+}
@@ -0,0 +1,25 @@
+package request
+
+// SyncRequest asks for what changed since the sync token Since, or for
+// every event when there is none, Limit at a time.
+type SyncRequest struct {
+	Since string `form:"since"`
+	Limit int    `form:"limit" binding:"min=1,max=500"`
+}
+
+// SyncMutationsRequest is a batch of up to 100 writes a client made
+// offline, applied in order and each on its own.
+type SyncMutationsRequest struct {
+	Mutations []*SyncMutation `json:"mutations" binding:"required,min=1,max=100,dive"`
+}
+
+// SyncMutation is a write to an event, made against the version the client
+// last saw of it. Event is checked with each mutation rather than with the
+// batch, so one that can't be applied doesn't hold the others back.
+type SyncMutation struct {
+	ClientID string        `json:"clientId" binding:"required,max=100"` // echoed in the result
+	Op       string        `json:"op" binding:"required,oneof=create update delete"`
+	EventID  uint64        `json:"eventId" binding:"required_unless=Op create"`
+	Version  uint64        `json:"version" binding:"required_unless=Op create"`
+	Event    *EventRequest `json:"event" binding:"-"` // for create and update
+}
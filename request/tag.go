@@ -0,0 +1,10 @@
+package request
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// TagMergeRequest moves the events of a tag to the tag TargetID.
+type TagMergeRequest struct {
+	TargetID uint64 `json:"targetId" binding:"required"`
+}
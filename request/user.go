@@ -0,0 +1,16 @@
+package request
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email,max=254"`
+	Name     string `json:"name" binding:"required,max=100"`
+	Password string `json:"password" binding:"required,min=8,max=72"` // bcrypt reads at most 72 bytes
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
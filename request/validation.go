@@ -0,0 +1,97 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
+
+func init() {
+	// Report fields by the names clients send them under
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// FieldErrors lists the per-field failures in a binding or validation
+// error. It returns nil when err carries no field information.
+func FieldErrors(err error) []response.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]response.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, response.FieldError{
+				Field:   fieldPath(fe),
+				Rule:    fe.Tag(),
+				Message: fieldMessage(fe),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []response.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type.Kind()),
+		}}
+	}
+
+	return nil
+}
+
+// fieldPath turns a validator namespace such as "EventRequest.startTime"
+// into "startTime". Segments still spelled as Go identifiers belong to the
+// top-level type or to embedded structs and are left out.
+func fieldPath(fe validator.FieldError) string {
+	var path []string
+	for _, segment := range strings.Split(fe.Namespace(), ".") {
+		if segment != "" && !unicode.IsUpper(rune(segment[0])) {
+			path = append(path, segment)
+		}
+	}
+	if len(path) == 0 {
+		return fe.Field()
+	}
+	return strings.Join(path, ".")
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	field := fe.Field()
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at most %s characters", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("%s failed the %s rule", field, fe.Tag())
+	}
+}
@@ -0,0 +1,15 @@
+package request
+
+// WebhookRequest creates or replaces a webhook. Events are the changes it
+// is sent; Active defaults to true, and inactive webhooks keep their
+// deliveries pending.
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2000"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=event.created event.updated event.completed event.deleted"`
+	Active *bool    `json:"active"`
+}
+
+type WebhookDeliveryListRequest struct {
+	PaginationRequest
+	Status string `form:"status" binding:"omitempty,oneof=pending delivered dead" json:"status"`
+}
@@ -0,0 +1,11 @@
+package request
+
+type WorkspaceRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// WorkspaceMemberRequest adds the user registered with Email to a workspace.
+type WorkspaceMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=admin member"`
+}
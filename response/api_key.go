@@ -0,0 +1,16 @@
+package response
+
+import (
+	"time"
+)
+
+type APIKeyResponse struct {
+	ID          uint64     `json:"apiKeyId"`
+	WorkspaceID uint64     `json:"workspaceId"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	Key         *string    `json:"key,omitempty"` // only when the key is created
+}
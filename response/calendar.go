@@ -0,0 +1,37 @@
+package response
+
+import (
+	"time"
+)
+
+type CalendarResponse struct {
+	ID        uint64     `json:"calendarId"`
+	OwnerID   uint64     `json:"ownerId"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	Timezone  string     `json:"timezone"`
+	Access    string     `json:"access"` // the caller's role: viewer, editor or owner
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updateAt"`
+
+	Workflow []*WorkflowStateResponse `json:"workflow"` // the default one for calendars without their own
+}
+
+type WorkflowStateResponse struct {
+	Name     string `json:"name"`
+	Terminal bool   `json:"terminal"`
+}
+
+type CalendarShareResponse struct {
+	ID           uint64     `json:"shareId"`
+	CalendarID   uint64     `json:"calendarId"`
+	CalendarName string     `json:"calendarName"`
+	UserID       uint64     `json:"userId"`
+	Email        string     `json:"email"`
+	Name         string     `json:"name"`
+	InvitedBy    uint64     `json:"invitedBy"`
+	Role         string     `json:"role"`
+	Status       string     `json:"status"`
+	CreatedAt    *time.Time `json:"createdAt"`
+	UpdatedAt    *time.Time `json:"updateAt"`
+}
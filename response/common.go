@@ -1,16 +1,22 @@
 package response
 
 type Response[T any] struct {
-	Status  string `json:"status"`  // "success" or "failed"
-	Message string `json:"message"` // human-readable message
-	Data    T      `json:"data"`    // generic payload
+	Status  string       `json:"status"`           // "success" or "failed"
+	Message string       `json:"message"`          // human-readable message
+	Code    string       `json:"code,omitempty"`   // machine-readable error code, failures only
+	Errors  []FieldError `json:"errors,omitempty"` // per-field failures, validation failures only
+	Data    T            `json:"data"`             // generic payload
 }
 
 type PaginatedResponse[T any] struct {
-	Status     string     `json:"status"`     // "success" or "failed"
-	Message    string     `json:"message"`    // human-readable message
-	Data       []T        `json:"data"`       // array of payloads
-	Pagination Pagination `json:"pagination"` // pagination info
+	Status     string       `json:"status"`              // "success" or "failed"
+	Message    string       `json:"message"`             // human-readable message
+	Code       string       `json:"code,omitempty"`      // machine-readable error code, failures only
+	Errors     []FieldError `json:"errors,omitempty"`    // per-field failures, validation failures only
+	Data       []T          `json:"data"`                // array of payloads
+	Pagination Pagination   `json:"pagination,omitzero"` // pagination info, paging by page
+	// pagination info instead, paging by cursor
+	CursorPagination *CursorPagination `json:"cursorPagination,omitempty"`
 }
 
 type Pagination struct {
@@ -19,3 +25,18 @@ type Pagination struct {
 	Total      int `json:"total"`
 	TotalPages int `json:"total_pages"`
 }
+
+// CursorPagination links the pages around one paged by cursor. A cursor
+// is null at either end of the list; Total is only counted on request.
+type CursorPagination struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"nextCursor"`
+	PrevCursor *string `json:"prevCursor"`
+	Total      *int    `json:"total,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`   // JSON path of the offending input, e.g. "startTime"
+	Rule    string `json:"rule"`    // failed rule, e.g. "required" or "max"
+	Message string `json:"message"` // human-readable message
+}
@@ -6,12 +6,123 @@ import (
 
 type EventResponse struct {
 	ID          uint64     `json:"eventId"`
+	Type        string     `json:"type"`
+	UID         *string    `json:"uid"`
+	OwnerID     uint64     `json:"ownerId"`
+	CalendarID  *uint64    `json:"calendarId"`
+	Access      string     `json:"access,omitempty"` // the caller's role on listed and fetched events
 	Title       string     `json:"title"`
 	Description *string    `json:"description"`
 	Complete    *bool      `json:"complete"`
+	CompletedAt *time.Time `json:"completedAt"`
 	CreatedAt   *time.Time `json:"createdAt"`
 	UpdatedAt   *time.Time `json:"updateAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"` // trashed events only
 	Location    string     `json:"location"`
-	StartTime   time.Time  `json:"startTime"`
-	EndTime     time.Time  `json:"endTime"`
+	Tags        []string   `json:"tags"`
+	StartTime   *time.Time `json:"startTime"`
+	EndTime     *time.Time `json:"endTime"`
+	DueAt       *time.Time `json:"dueAt"`
+	Priority    string     `json:"priority"`
+	Version     uint64     `json:"version"`
+	Score       *float64   `json:"score,omitempty"`   // search relevance, search results only
+	Snippet     *string    `json:"snippet,omitempty"` // highlighted match, search results only
+
+	RRule           *string     `json:"rrule"`
+	ExDates         []time.Time `json:"exdates"`
+	RecurrenceEnd   *time.Time  `json:"recurrenceEnd"`
+	Timezone        *string     `json:"timezone"`                  // the zone the series recurs in, UTC when nil
+	OccurrenceStart *time.Time  `json:"occurrenceStart,omitempty"` // set on expanded occurrences of a recurring event
+
+	// Progress counts the done items of the checklist, none without one
+	Progress     *EventProgress `json:"progress,omitempty"`
+	AutoComplete bool           `json:"autoComplete"`
+
+	BlockedBy              []uint64 `json:"blockedBy"`
+	Blocks                 []uint64 `json:"blocks"`
+	Blocked                bool     `json:"blocked"` // some of BlockedBy aren't complete
+	AllowBlockedCompletion bool     `json:"allowBlockedCompletion"`
+
+	Status   string `json:"status"`
+	Position int    `json:"position"` // within the events of its calendar in Status
+
+	Reminders []*ReminderResponse `json:"reminders"`
+}
+
+type ReminderResponse struct {
+	MinutesBefore int        `json:"minutesBefore"`
+	RemindAt      *time.Time `json:"remindAt"` // the next one, none when there is none left
+	SentAt        *time.Time `json:"sentAt"`
+	Late          bool       `json:"late"`
+}
+
+type EventProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type EventItemResponse struct {
+	ID        uint64     `json:"itemId"`
+	EventID   uint64     `json:"eventId"`
+	Text      string     `json:"text"`
+	Done      bool       `json:"done"`
+	Position  int        `json:"position"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updateAt"`
+}
+
+// EventGraphResponse is the dependency graph around an event: the events it
+// waits on and the ones waiting on it, transitively. Edges point from an
+// event to its blocker.
+type EventGraphResponse struct {
+	Nodes []*EventGraphNode `json:"nodes"`
+	Edges []*EventGraphEdge `json:"edges"`
+}
+
+type EventGraphNode struct {
+	ID       uint64 `json:"eventId"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Complete bool   `json:"complete"`
+}
+
+type EventGraphEdge struct {
+	EventID   uint64 `json:"eventId"`
+	BlockerID uint64 `json:"blockerId"`
+}
+
+// EventExportResponse is an iCalendar rendering of events. LastModified is
+// the latest change among them or to the events of the actor since deleted,
+// nil when there are none.
+type EventExportResponse struct {
+	Calendar     []byte
+	LastModified *time.Time
+}
+
+// EventImportResponse reports an iCalendar import, one result per VEVENT
+// in file order.
+type EventImportResponse struct {
+	Created int                  `json:"created"`
+	Skipped int                  `json:"skipped"`
+	Failed  int                  `json:"failed"`
+	Results []*EventImportResult `json:"results"`
+}
+
+type EventImportResult struct {
+	Index   int          `json:"index"` // position of the VEVENT in the file, from 1
+	UID     string       `json:"uid"`
+	Status  string       `json:"status"` // CREATED, SKIPPED or FAILED
+	EventID *uint64      `json:"eventId,omitempty"`
+	Message string       `json:"message,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// EventChangeResponse is a change the real-time stream sends. Over SSE, ID
+// is the event id and Type the event name, with Event as the data.
+type EventChangeResponse struct {
+	ID   uint64 `json:"id"`   // of the change, to resume the stream after it
+	Type string `json:"type"` // created, updated or deleted
+	// Event is the event as the caller sees it now; only its IDs, version
+	// and deletion time once deleted
+	Event *EventResponse `json:"event"`
 }
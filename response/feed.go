@@ -0,0 +1,17 @@
+package response
+
+import (
+	"time"
+)
+
+type FeedResponse struct {
+	ID        uint64     `json:"feedId"`
+	Name      string     `json:"name"`
+	Complete  *bool      `json:"complete"`
+	Location  string     `json:"location"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updateAt"`
+	// Token and URL are only returned when a token is issued
+	Token *string `json:"token,omitempty"`
+	URL   *string `json:"url,omitempty"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import (
+	"time"
+)
+
+// ReminderNotification is what notifiers deliver, and the body of reminder
+// webhooks.
+type ReminderNotification struct {
+	ReminderID uint64    `json:"reminderId"`
+	EventID    uint64    `json:"eventId"`
+	Title      string    `json:"title"`
+	Location   string    `json:"location"`
+	StartTime  time.Time `json:"startTime"` // of the occurrence, or when a task is due
+	RemindAt   time.Time `json:"remindAt"`
+	Late       bool      `json:"late"` // the reminder's time was missed, e.g. during downtime
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+}
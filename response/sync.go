@@ -0,0 +1,39 @@
+package response
+
+import "time"
+
+// SyncResponse is a page of what changed since a sync token. Each event
+// that changed is in Events as it is now, or in Deleted once gone.
+type SyncResponse struct {
+	Events  []*EventResponse  `json:"events"`
+	Deleted []*EventTombstone `json:"deleted"`
+	Token   string            `json:"token"`   // to sync from next time
+	HasMore bool              `json:"hasMore"` // sync again with Token right away
+}
+
+// EventTombstone is an event the caller should drop: deleted, trashed
+// included, or no longer shared with them, when DeletedAt is unset.
+type EventTombstone struct {
+	ID        uint64     `json:"id"`
+	Version   uint64     `json:"version"`
+	DeletedAt *time.Time `json:"deletedAt"`
+}
+
+// SyncMutationsResponse reports a batch of mutations, one result per
+// mutation in request order.
+type SyncMutationsResponse struct {
+	Applied   int                   `json:"applied"`
+	Conflicts int                   `json:"conflicts"`
+	Failed    int                   `json:"failed"`
+	Results   []*SyncMutationResult `json:"results"`
+}
+
+type SyncMutationResult struct {
+	ClientID string `json:"clientId"`
+	Status   string `json:"status"` // APPLIED, CONFLICT or FAILED
+	// Event is the event as it is now: written when applied, the server's
+	// on a conflict. It is unset once the event is gone.
+	Event   *EventResponse `json:"event,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Errors  []FieldError   `json:"errors,omitempty"`
+}
@@ -0,0 +1,13 @@
+package response
+
+import (
+	"time"
+)
+
+type TagResponse struct {
+	ID         uint64     `json:"tagId"`
+	Name       string     `json:"name"`
+	EventCount int64      `json:"eventCount"`
+	CreatedAt  *time.Time `json:"createdAt"`
+	UpdatedAt  *time.Time `json:"updateAt"`
+}
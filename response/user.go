@@ -0,0 +1,24 @@
+package response
+
+import (
+	"time"
+)
+
+type UserResponse struct {
+	ID        uint64     `json:"userId"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updateAt"`
+}
+
+// AuthResponse carries a short-lived access token for the Authorization
+// header and the refresh token that renews it.
+type AuthResponse struct {
+	TokenType             string        `json:"tokenType"` // always "Bearer"
+	AccessToken           string        `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time     `json:"accessTokenExpiresAt"`
+	RefreshToken          string        `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time     `json:"refreshTokenExpiresAt"`
+	User                  *UserResponse `json:"user"`
+}
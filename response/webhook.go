@@ -0,0 +1,45 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookResponse struct {
+	ID        uint64     `json:"webhookId"`
+	URL       string     `json:"url"`
+	Events    []string   `json:"events"`
+	Active    bool       `json:"active"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updateAt"`
+	// Secret signs the deliveries; it is only returned on creation
+	Secret *string `json:"secret,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint64          `json:"deliveryId"`
+	WebhookID      uint64          `json:"webhookId"`
+	Type           string          `json:"type"`
+	Status         string          `json:"status"` // pending, delivered or dead
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt"`
+	ResponseStatus int             `json:"responseStatus"` // of the last attempt, 0 without a response
+	LastError      string          `json:"lastError"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+	CreatedAt      *time.Time      `json:"createdAt"`
+	UpdatedAt      *time.Time      `json:"updateAt"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// WebhookPayload is the body of a webhook delivery.
+type WebhookPayload struct {
+	ID          uint64     `json:"id"` // of the change, the same for every webhook and attempt
+	Type        string     `json:"type"`
+	OccurredAt  *time.Time `json:"occurredAt"`
+	WorkspaceID uint64     `json:"workspaceId"`
+	EventID     uint64     `json:"eventId"`
+	Version     uint64     `json:"version"` // of the event once changed
+	// Event is the event as the owner of the webhook saw it when the change
+	// was sent out, null once it is gone
+	Event *EventResponse `json:"event"`
+}
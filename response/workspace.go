@@ -0,0 +1,23 @@
+package response
+
+import (
+	"time"
+)
+
+type WorkspaceResponse struct {
+	ID        uint64     `json:"workspaceId"`
+	Name      string     `json:"name"`
+	CreatedBy uint64     `json:"createdBy"`
+	Role      string     `json:"role"` // the caller's role: admin or member
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updateAt"`
+}
+
+type WorkspaceMemberResponse struct {
+	WorkspaceID uint64     `json:"workspaceId"`
+	UserID      uint64     `json:"userId"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	Role        string     `json:"role"`
+	CreatedAt   *time.Time `json:"createdAt"`
+}
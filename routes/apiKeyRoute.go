@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/feature/apikey/delivery"
+	"github.com/pubestpubest/g12-todo-backend/feature/apikey/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/apikey/usecase"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+)
+
+func APIKeyRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	apiKeyHandler := delivery.NewAPIKeyHandler(
+		usecase.NewAPIKeyUsecase(
+			repository.NewAPIKeyRepository(database.DB)))
+
+	// Keys are managed by logging in, so a leaked key cannot mint more
+	apiKeyRoutes := router.Group("/api-keys", auth, middlewares.RequireSession())
+	{
+		apiKeyRoutes.GET("", apiKeyHandler.GetAPIKeyList)
+		apiKeyRoutes.POST("", apiKeyHandler.CreateAPIKey)
+		apiKeyRoutes.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+	}
+}
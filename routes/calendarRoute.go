@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/feature/calendar/delivery"
+	"github.com/pubestpubest/g12-todo-backend/feature/calendar/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/calendar/usecase"
+	eventRepository "github.com/pubestpubest/g12-todo-backend/feature/event/repository"
+	eventUsecase "github.com/pubestpubest/g12-todo-backend/feature/event/usecase"
+	userRepository "github.com/pubestpubest/g12-todo-backend/feature/user/repository"
+	workspaceRepository "github.com/pubestpubest/g12-todo-backend/feature/workspace/repository"
+	workspaceUsecase "github.com/pubestpubest/g12-todo-backend/feature/workspace/usecase"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+)
+
+func CalendarRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	calendarRepository := repository.NewCalendarRepository(database.DB)
+	calendarHandler := delivery.NewCalendarHandler(
+		usecase.NewCalendarUsecase(
+			calendarRepository,
+			userRepository.NewUserRepository(database.DB),
+			workspaceUsecase.NewWorkspaceUsecase(
+				workspaceRepository.NewWorkspaceRepository(database.DB),
+				userRepository.NewUserRepository(database.DB)),
+			eventUsecase.NewEventUsecase(
+				eventRepository.NewEventRepository(database.DB),
+				calendarRepository)))
+
+	// Calendars hold events, so they share their scopes
+	read := middlewares.RequireScope(constant.ScopeEventsRead)
+	write := middlewares.RequireScope(constant.ScopeEventsWrite)
+
+	calendarRoutes := router.Group("/calendars", auth)
+	{
+		calendarRoutes.GET("", read, calendarHandler.GetCalendarList)
+		calendarRoutes.GET("/:id", read, calendarHandler.GetCalendarByID)
+		calendarRoutes.GET("/:id/events", read, calendarHandler.GetCalendarEvents)
+		calendarRoutes.POST("", write, calendarHandler.CreateCalendar)
+		calendarRoutes.PUT("/:id", write, calendarHandler.UpdateCalendar)
+		calendarRoutes.DELETE("/:id", write, calendarHandler.DeleteCalendar)
+
+		calendarRoutes.GET("/:id/shares", read, calendarHandler.GetCalendarShareList)
+		calendarRoutes.POST("/:id/shares", write, calendarHandler.ShareCalendar)
+		calendarRoutes.PUT("/:id/shares/:shareId", write, calendarHandler.UpdateCalendarShare)
+		calendarRoutes.DELETE("/:id/shares/:shareId", write, calendarHandler.DeleteCalendarShare)
+	}
+
+	// Invitations are the calendar shares waiting for the caller's answer
+	invitationRoutes := router.Group("/invitations", auth)
+	{
+		invitationRoutes.GET("", read, calendarHandler.GetInvitationList)
+		invitationRoutes.POST("/:id/accept", write, calendarHandler.AcceptInvitation)
+		invitationRoutes.POST("/:id/decline", write, calendarHandler.DeclineInvitation)
+	}
+}
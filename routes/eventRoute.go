@@ -2,25 +2,56 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/g12-todo-backend/constant"
 	"github.com/pubestpubest/g12-todo-backend/database"
+	calendarRepository "github.com/pubestpubest/g12-todo-backend/feature/calendar/repository"
 	"github.com/pubestpubest/g12-todo-backend/feature/event/delivery"
 	"github.com/pubestpubest/g12-todo-backend/feature/event/repository"
 	"github.com/pubestpubest/g12-todo-backend/feature/event/usecase"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
 )
 
-func EventRoutes(router *gin.RouterGroup) {
+func EventRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
 	// NewEventRepository := repository.NewEventRepository(database.DB)
 	// newEventUsecase := usecase.NewEventUsecase(NewEventRepository)
 	eventHandler := delivery.NewEventHandler(
 		usecase.NewEventUsecase(
-			repository.NewEventRepository(database.DB)))
+			repository.NewEventRepository(database.DB),
+			calendarRepository.NewCalendarRepository(database.DB)))
 
-	eventRoutes := router.Group("/events")
+	read := middlewares.RequireScope(constant.ScopeEventsRead)
+	write := middlewares.RequireScope(constant.ScopeEventsWrite)
+
+	eventRoutes := router.Group("/events", auth)
+	{
+		eventRoutes.GET("", read, eventHandler.GetEventList)
+		eventRoutes.GET("/search", read, eventHandler.SearchEvents)
+		eventRoutes.GET("/export.ics", read, eventHandler.ExportEvents)
+		eventRoutes.POST("/import", write, eventHandler.ImportEvents)
+		eventRoutes.GET("/trash", read, eventHandler.GetDeletedEventList)
+		eventRoutes.GET("/:id", read, eventHandler.GetEventByID)
+		eventRoutes.POST("", write, eventHandler.CreateEvent)
+		eventRoutes.PUT("/:id", write, eventHandler.UpdateEvent)
+		eventRoutes.PATCH("/:id", write, eventHandler.PatchEvent)
+		eventRoutes.DELETE("/:id", write, eventHandler.DeleteEvent)
+		eventRoutes.POST("/:id/restore", write, eventHandler.RestoreEvent)
+		eventRoutes.POST("/:id/move", write, eventHandler.MoveEvent)
+		eventRoutes.GET("/:id/items", read, eventHandler.GetEventItemList)
+		eventRoutes.POST("/:id/items", write, eventHandler.CreateEventItem)
+		eventRoutes.PUT("/:id/items/:itemId", write, eventHandler.UpdateEventItem)
+		eventRoutes.DELETE("/:id/items/:itemId", write, eventHandler.DeleteEventItem)
+		eventRoutes.GET("/:id/graph", read, eventHandler.GetEventGraph)
+	}
+
+	streamRoutes := router.Group("/events/stream", middlewares.QueryCredentials(), auth, read)
+	{
+		streamRoutes.GET("", eventHandler.StreamEvents)
+		streamRoutes.GET("/ws", eventHandler.StreamEventsWebSocket)
+	}
+
+	syncRoutes := router.Group("/sync", auth)
 	{
-		eventRoutes.GET("", eventHandler.GetEventList)
-		eventRoutes.GET("/:id", eventHandler.GetEventByID)
-		eventRoutes.POST("", eventHandler.CreateEvent)
-		eventRoutes.PUT("/:id", eventHandler.UpdateEvent)
-		eventRoutes.DELETE("/:id", eventHandler.DeleteEvent)
+		syncRoutes.GET("", read, eventHandler.SyncEvents)
+		syncRoutes.POST("", write, eventHandler.ApplySyncMutations)
 	}
 }
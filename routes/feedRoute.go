@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	calendarRepository "github.com/pubestpubest/g12-todo-backend/feature/calendar/repository"
+	eventRepository "github.com/pubestpubest/g12-todo-backend/feature/event/repository"
+	eventUsecase "github.com/pubestpubest/g12-todo-backend/feature/event/usecase"
+	"github.com/pubestpubest/g12-todo-backend/feature/feed/delivery"
+	"github.com/pubestpubest/g12-todo-backend/feature/feed/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/feed/usecase"
+	userRepository "github.com/pubestpubest/g12-todo-backend/feature/user/repository"
+	workspaceRepository "github.com/pubestpubest/g12-todo-backend/feature/workspace/repository"
+	workspaceUsecase "github.com/pubestpubest/g12-todo-backend/feature/workspace/usecase"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+)
+
+func FeedRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	feedHandler := delivery.NewFeedHandler(
+		usecase.NewFeedUsecase(
+			repository.NewFeedRepository(database.DB),
+			eventUsecase.NewEventUsecase(
+				eventRepository.NewEventRepository(database.DB),
+				calendarRepository.NewCalendarRepository(database.DB)),
+			workspaceUsecase.NewWorkspaceUsecase(
+				workspaceRepository.NewWorkspaceRepository(database.DB),
+				userRepository.NewUserRepository(database.DB))))
+
+	read := middlewares.RequireScope(constant.ScopeFeedsRead)
+	write := middlewares.RequireScope(constant.ScopeFeedsWrite)
+
+	feedRoutes := router.Group("/feeds")
+	{
+		// Calendar apps cannot log in; the feed token is the credential
+		feedRoutes.GET("/:token", feedHandler.GetFeedCalendar) // /feeds/{token}.ics
+
+		ownFeedRoutes := feedRoutes.Group("", auth)
+		ownFeedRoutes.GET("", read, feedHandler.GetFeedList)
+		ownFeedRoutes.POST("", write, feedHandler.CreateFeed)
+		ownFeedRoutes.POST("/:id/rotate", write, feedHandler.RotateFeedToken)
+		ownFeedRoutes.DELETE("/:id", write, feedHandler.DeleteFeed)
+	}
+}
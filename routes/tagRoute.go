@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	"github.com/pubestpubest/g12-todo-backend/feature/tag/delivery"
+	"github.com/pubestpubest/g12-todo-backend/feature/tag/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/tag/usecase"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+)
+
+func TagRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	tagHandler := delivery.NewTagHandler(usecase.NewTagUsecase(repository.NewTagRepository(database.DB)))
+
+	// Tags label events, so they share their scopes
+	read := middlewares.RequireScope(constant.ScopeEventsRead)
+	write := middlewares.RequireScope(constant.ScopeEventsWrite)
+
+	tagRoutes := router.Group("/tags", auth)
+	{
+		tagRoutes.GET("", read, tagHandler.GetTagList)
+		tagRoutes.POST("", write, tagHandler.CreateTag)
+		tagRoutes.PUT("/:id", write, tagHandler.UpdateTag)
+		tagRoutes.DELETE("/:id", write, tagHandler.DeleteTag)
+		tagRoutes.POST("/:id/merge", write, tagHandler.MergeTag)
+	}
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	apiKeyRepository "github.com/pubestpubest/g12-todo-backend/feature/apikey/repository"
+	apiKeyUsecase "github.com/pubestpubest/g12-todo-backend/feature/apikey/usecase"
+	"github.com/pubestpubest/g12-todo-backend/feature/user/delivery"
+	"github.com/pubestpubest/g12-todo-backend/feature/user/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/user/usecase"
+	workspaceRepository "github.com/pubestpubest/g12-todo-backend/feature/workspace/repository"
+	workspaceUsecase "github.com/pubestpubest/g12-todo-backend/feature/workspace/usecase"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+	"github.com/pubestpubest/g12-todo-backend/utils"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// UserRoutes registers the auth, user and workspace endpoints and returns
+// the middleware that authenticates the rest of the API. jwtSecret is required;
+// empty token lifetimes take the defaults.
+func UserRoutes(router *gin.RouterGroup, jwtSecret, accessTokenTTL, refreshTokenTTL string) (gin.HandlerFunc, error) {
+	if jwtSecret == "" {
+		return nil, errors.New("[Routes.UserRoutes]: JWT_SECRET is required")
+	}
+	accessTTL, err := utils.ParseDuration(accessTokenTTL, defaultAccessTokenTTL)
+	if err != nil || accessTTL == 0 {
+		return nil, errors.Errorf("[Routes.UserRoutes]: Invalid ACCESS_TOKEN_TTL %q", accessTokenTTL)
+	}
+	refreshTTL, err := utils.ParseDuration(refreshTokenTTL, defaultRefreshTokenTTL)
+	if err != nil || refreshTTL == 0 {
+		return nil, errors.Errorf("[Routes.UserRoutes]: Invalid REFRESH_TOKEN_TTL %q", refreshTokenTTL)
+	}
+
+	userUsecase := usecase.NewUserUsecase(
+		repository.NewUserRepository(database.DB), []byte(jwtSecret), accessTTL, refreshTTL)
+	userHandler := delivery.NewUserHandler(userUsecase)
+	workspaces := workspaceUsecase.NewWorkspaceUsecase(
+		workspaceRepository.NewWorkspaceRepository(database.DB),
+		repository.NewUserRepository(database.DB))
+	auth := middlewares.AuthMiddleware(userUsecase,
+		apiKeyUsecase.NewAPIKeyUsecase(
+			apiKeyRepository.NewAPIKeyRepository(database.DB)),
+		workspaces)
+
+	authRoutes := router.Group("/auth")
+	{
+		authRoutes.POST("/register", userHandler.Register)
+		authRoutes.POST("/login", userHandler.Login)
+		authRoutes.POST("/refresh", userHandler.RefreshToken)
+		authRoutes.POST("/logout", userHandler.Logout)
+	}
+
+	userRoutes := router.Group("/users", auth)
+	{
+		userRoutes.GET("/me", userHandler.GetMe)
+	}
+
+	WorkspaceRoutes(router, auth, workspaces)
+
+	return auth, nil
+}
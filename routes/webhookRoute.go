@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/database"
+	calendarRepository "github.com/pubestpubest/g12-todo-backend/feature/calendar/repository"
+	eventRepository "github.com/pubestpubest/g12-todo-backend/feature/event/repository"
+	eventUsecase "github.com/pubestpubest/g12-todo-backend/feature/event/usecase"
+	userRepository "github.com/pubestpubest/g12-todo-backend/feature/user/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/webhook/delivery"
+	"github.com/pubestpubest/g12-todo-backend/feature/webhook/repository"
+	"github.com/pubestpubest/g12-todo-backend/feature/webhook/usecase"
+	workspaceRepository "github.com/pubestpubest/g12-todo-backend/feature/workspace/repository"
+	workspaceUsecase "github.com/pubestpubest/g12-todo-backend/feature/workspace/usecase"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+)
+
+func WebhookRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	webhookHandler := delivery.NewWebhookHandler(
+		usecase.NewWebhookUsecase(
+			repository.NewWebhookRepository(database.DB),
+			eventUsecase.NewEventUsecase(
+				eventRepository.NewEventRepository(database.DB),
+				calendarRepository.NewCalendarRepository(database.DB)),
+			workspaceUsecase.NewWorkspaceUsecase(
+				workspaceRepository.NewWorkspaceRepository(database.DB),
+				userRepository.NewUserRepository(database.DB))))
+
+	read := middlewares.RequireScope(constant.ScopeWebhooksRead)
+	write := middlewares.RequireScope(constant.ScopeWebhooksWrite)
+
+	webhookRoutes := router.Group("/webhooks", auth)
+	{
+		webhookRoutes.GET("", read, webhookHandler.GetWebhookList)
+		webhookRoutes.GET("/:id", read, webhookHandler.GetWebhookByID)
+		webhookRoutes.POST("", write, webhookHandler.CreateWebhook)
+		webhookRoutes.PUT("/:id", write, webhookHandler.UpdateWebhook)
+		webhookRoutes.DELETE("/:id", write, webhookHandler.DeleteWebhook)
+		webhookRoutes.GET("/:id/deliveries", read, webhookHandler.GetWebhookDeliveryList)
+		webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", write, webhookHandler.RedeliverWebhookDelivery)
+	}
+}
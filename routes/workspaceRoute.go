@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/feature/workspace/delivery"
+	"github.com/pubestpubest/g12-todo-backend/middlewares"
+)
+
+func WorkspaceRoutes(router *gin.RouterGroup, auth gin.HandlerFunc, workspaceUsecase domain.WorkspaceUsecase) {
+	workspaceHandler := delivery.NewWorkspaceHandler(workspaceUsecase)
+
+	// Like keys, membership is managed by logging in
+	workspaceRoutes := router.Group("/workspaces", auth, middlewares.RequireSession())
+	{
+		workspaceRoutes.GET("", workspaceHandler.GetWorkspaceList)
+		workspaceRoutes.POST("", workspaceHandler.CreateWorkspace)
+		workspaceRoutes.GET("/:id", workspaceHandler.GetWorkspaceByID)
+		workspaceRoutes.PUT("/:id", workspaceHandler.UpdateWorkspace)
+		workspaceRoutes.DELETE("/:id", workspaceHandler.DeleteWorkspace)
+		workspaceRoutes.GET("/:id/members", workspaceHandler.GetWorkspaceMemberList)
+		workspaceRoutes.POST("/:id/members", workspaceHandler.AddWorkspaceMember)
+		workspaceRoutes.DELETE("/:id/members/:userId", workspaceHandler.RemoveWorkspaceMember)
+	}
+}
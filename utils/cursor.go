@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// EncodeCursor packs a position into an opaque, URL-safe token, for
+// clients to hand back as they are.
+func EncodeCursor(position any) string {
+	// Positions are plain structs, which always marshal
+	data, _ := json.Marshal(position)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor unpacks a token EncodeCursor made into position.
+func DecodeCursor(token string, position any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return errors.Wrap(err, "[DecodeCursor]: Error decoding cursor")
+	}
+	if err := json.Unmarshal(data, position); err != nil {
+		return errors.Wrap(err, "[DecodeCursor]: Error reading cursor")
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseDuration parses a duration setting such as "720h", returning
+// fallback when it is empty. Negative durations are rejected.
+func ParseDuration(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration < 0 {
+		return 0, errors.Errorf("duration %q must not be negative", value)
+	}
+	return duration, nil
+}
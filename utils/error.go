@@ -1,8 +1,54 @@
 package utils
 
-import "strings"
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/g12-todo-backend/constant"
+	"github.com/pubestpubest/g12-todo-backend/domain"
+	"github.com/pubestpubest/g12-todo-backend/request"
+	"github.com/pubestpubest/g12-todo-backend/response"
+)
 
 func StandardError(err error) string {
 	errorMessages := strings.Split(err.Error(), "]: ")
 	return errorMessages[len(errorMessages)-1]
 }
+
+// HTTPError translates an error returned by a usecase into the HTTP status
+// and error code to reply with. Anything that is not a domain.Error is an
+// internal error.
+func HTTPError(err error) (int, string) {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		return http.StatusInternalServerError, constant.CodeInternal
+	}
+
+	switch domainErr.Code {
+	case constant.CodeUnauthorized:
+		return http.StatusUnauthorized, domainErr.Code
+	case constant.CodeForbidden:
+		return http.StatusForbidden, domainErr.Code
+	case constant.CodeNotFound:
+		return http.StatusNotFound, domainErr.Code
+	case constant.CodeValidationFailed:
+		return http.StatusUnprocessableEntity, domainErr.Code
+	case constant.CodeConflict:
+		return http.StatusConflict, domainErr.Code
+	case constant.CodePreconditionFailed:
+		return http.StatusPreconditionFailed, domainErr.Code
+	default:
+		return http.StatusInternalServerError, constant.CodeInternal
+	}
+}
+
+// FieldErrors lists the per-field failures behind err, whether it comes
+// from request binding or is a domain validation error.
+func FieldErrors(err error) []response.FieldError {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Fields
+	}
+	return request.FieldErrors(err)
+}
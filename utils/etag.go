@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ETag formats an entity version as a strong entity tag, e.g. "3".
+func ETag(version uint64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ParseIfMatch reads the version from an If-Match header value. "*" matches
+// any version and yields 0.
+func ParseIfMatch(header string) (uint64, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.Trim(header, `"`)
+	if strings.HasPrefix(header, "W/") || len(tag) != len(header)-2 {
+		return 0, errors.New("If-Match must be a single strong entity tag or *")
+	}
+
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil || version == 0 {
+		return 0, errors.New("If-Match does not hold a valid entity tag")
+	}
+	return version, nil
+}
+
+// IfNoneMatch reports whether etag is listed in an If-None-Match header
+// value, using the weak comparison RFC 9110 requires for it.
+func IfNoneMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentETag derives a strong entity tag from a representation, for
+// resources that have no version of their own.
+func ContentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModifiedSince reports whether a resource last modified at
+// lastModified is unchanged since an If-Modified-Since header value.
+// Invalid dates never match.
+func NotModifiedSince(header string, lastModified time.Time) bool {
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have whole-second precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"strings"
+)
+
+// NormalizeTag folds a tag name to the form it is stored and matched in:
+// trimmed, lower case, with runs of spaces collapsed.
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// NormalizeStatus folds a workflow state name the way NormalizeTag folds
+// tag names.
+func NormalizeStatus(name string) string {
+	return NormalizeTag(name)
+}